@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+)
+
+var (
+	adoptScan bool
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt (<path>... | --scan)",
+	Short: "Register existing Go installations with gx",
+	Long: `Register Go toolchains installed outside gx (/usr/local/go, distro packages,
+Homebrew, golang.org/dl, ...) so they can be used with gx use, gx exec and
+.go-version files.
+
+Adopted versions are linked, not owned: gx never modifies or deletes their
+files. 'gx uninstall' only unregisters them.
+
+The path can be a GOROOT, its bin directory or the go executable itself.
+With --scan, common installation locations and the go found on PATH are
+searched and every valid toolchain is registered.
+
+Example:
+  gx adopt /usr/local/go
+  gx adopt ~/sdk/go1.21.5
+  gx adopt $(which go)
+  gx adopt --scan`,
+	RunE: runAdopt,
+}
+
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+	adoptCmd.Flags().BoolVar(&adoptScan, "scan", false, "search common locations for Go installations")
+}
+
+func runAdopt(cmd *cobra.Command, args []string) error {
+	if adoptScan == (len(args) > 0) {
+		return fmt.Errorf("specify either one or more paths or --scan")
+	}
+
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	paths := args
+	if adoptScan {
+		messenger.Info("Scanning for Go installations...")
+		paths, err = ctx.VersionManager.ScanToolchains()
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		if len(paths) == 0 {
+			messenger.Warning("No Go installations found")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	failed := 0
+	for _, path := range paths {
+		version, err := ctx.VersionManager.Adopt(path)
+		if err != nil {
+			failed++
+			// 扫描模式下单个失败不中断，逐条报告
+			if adoptScan {
+				messenger.Warning(fmt.Sprintf("Skipped %s: %v", path, err))
+				continue
+			}
+			errorFormatter.Format(err)
+			continue
+		}
+		messenger.Success(fmt.Sprintf("Adopted Go %s (%s)", goversion.Display(version), path))
+	}
+
+	if failed > 0 && !adoptScan {
+		return fmt.Errorf("failed to adopt %d of %d paths", failed, len(paths))
+	}
+
+	fmt.Println()
+	messenger.Info("To use an adopted version, run:")
+	fmt.Println("  gx use <version>")
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+)
+
+var (
+	aliasDelete bool
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias [name] [version]",
+	Short: "Manage named aliases for Go versions",
+	Long: `Create, show or delete named aliases for Go versions.
+An alias can point to an exact version (1.21.5), a release line (1.22 or 1.22.x)
+or a keyword (latest, stable, oldstable). Aliases can be used anywhere a version
+is accepted: gx use, gx install, gx env, gx shell and .go-version files.
+
+Without arguments, lists all aliases.
+
+Example:
+  gx alias prod 1.21.5
+  gx alias edge latest
+  gx alias              # list aliases
+  gx alias prod         # show one alias
+  gx alias -d prod      # delete an alias`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runAlias,
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.Flags().BoolVarP(&aliasDelete, "delete", "d", false, "delete the named alias")
+}
+
+func runAlias(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	// 删除别名
+	if aliasDelete {
+		if len(args) != 1 {
+			return fmt.Errorf("--delete requires exactly one alias name")
+		}
+		if err := ctx.VersionManager.RemoveAlias(args[0]); err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		messenger.Success(fmt.Sprintf("Alias %s deleted", args[0]))
+		return nil
+	}
+
+	// 设置别名
+	if len(args) == 2 {
+		if err := ctx.VersionManager.SetAlias(args[0], args[1]); err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		messenger.Success(fmt.Sprintf("Alias %s -> %s", args[0], goversion.Display(args[1])))
+		return nil
+	}
+
+	aliases, err := ctx.VersionManager.ListAliases()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	// 显示单个别名
+	if len(args) == 1 {
+		spec, ok := aliases[args[0]]
+		if !ok {
+			messenger.Warning(fmt.Sprintf("Alias %s does not exist", args[0]))
+			return nil
+		}
+		printAlias(ctx, args[0], spec)
+		return nil
+	}
+
+	if len(aliases) == 0 {
+		messenger.Info("No aliases defined")
+		fmt.Println()
+		messenger.Info("To create an alias, run:")
+		fmt.Println("  gx alias <name> <version>")
+		return nil
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	messenger.Info("Aliases:")
+	fmt.Println()
+	for _, name := range names {
+		printAlias(ctx, name, aliases[name])
+	}
+
+	return nil
+}
+
+// printAlias 显示别名及其当前对应的已安装版本
+func printAlias(ctx *AppContext, name string, spec string) {
+	resolved := "not installed"
+	if version, err := ctx.VersionManager.FindInstalled(name); err == nil {
+		resolved = goversion.Display(version)
+	}
+	fmt.Printf("  %-12s -> %-10s (%s)\n", name, goversion.Display(spec), resolved)
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+var (
+	bundlePlatforms []string
+	bundleOutput    string
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create and install offline bundles of Go releases",
+	Long: `Package Go releases for machines without network access.
+
+A bundle is a single tar file holding the official release archives for
+one or more versions and platforms, a manifest and their SHA256 checksums.
+Create it on a connected machine, copy it over, and install it there:
+
+  gx bundle create 1.22.3 1.21.10 --platform linux/amd64,linux/arm64 -o go.tar
+  gx bundle install go.tar`,
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create <version>...",
+	Short: "Download releases into a bundle",
+	Long: `Download the release archives for the given versions and platforms into
+one bundle file. Versions may be specifiers such as 1.22 or stable; they
+are resolved against the remote version list. Without --platform the
+bundle targets the current platform.
+
+Example:
+  gx bundle create 1.22.3 -o go1.22.3.tar
+  gx bundle create 1.22 1.21 --platform linux/amd64,linux/arm64,windows/amd64 -o go.tar`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBundleCreate,
+}
+
+var bundleInstallCmd = &cobra.Command{
+	Use:   "install <bundle>",
+	Short: "Verify a bundle and install its releases for this platform",
+	Long: `Verify every archive in a bundle against its manifest and install the
+versions built for the current platform. Nothing is installed if any
+archive fails verification. Versions that are already installed are skipped.
+No network access is needed.
+
+Example:
+  gx bundle install go.tar`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleInstall,
+}
+
+var bundleListCmd = &cobra.Command{
+	Use:   "list <bundle>",
+	Short: "List the contents of a bundle",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBundleList,
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleInstallCmd)
+	bundleCmd.AddCommand(bundleListCmd)
+
+	bundleCreateCmd.Flags().StringSliceVarP(&bundlePlatforms, "platform", "p", nil, "target platforms as os/arch, comma separated (default: current platform)")
+	bundleCreateCmd.Flags().StringVarP(&bundleOutput, "output", "o", "gx-bundle.tar", "bundle file to write")
+}
+
+func runBundleCreate(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	platforms, err := parsePlatforms(bundlePlatforms)
+	if err != nil {
+		return err
+	}
+
+	// 每个安装包使用单独的进度条
+	var progressBar *ui.ProgressBar
+	current := ""
+	progress := func(entry interfaces.BundleEntry, downloaded, total int64) {
+		if entry.Filename != current {
+			current = entry.Filename
+			progressBar = nil
+			if total > 0 {
+				progressBar = ui.NewProgressBar(os.Stdout, total, entry.Filename)
+			}
+		}
+		if progressBar != nil {
+			progressBar.Update(downloaded)
+			if downloaded >= total {
+				progressBar.Finish()
+				progressBar = nil
+			}
+		}
+	}
+
+	messenger.Info(fmt.Sprintf("Creating bundle %s...", bundleOutput))
+	runCtx, cancel := withInterrupt(cmd.Context())
+	defer cancel()
+	manifest, err := ctx.Bundler.Create(runCtx, interfaces.BundleOptions{
+		Versions:   args,
+		Platforms:  platforms,
+		OutputPath: bundleOutput,
+		Progress:   progress,
+	})
+	if err != nil {
+		if progressBar != nil {
+			fmt.Println()
+		}
+		errorFormatter.Format(err)
+		return err
+	}
+
+	printBundleEntries(manifest.Entries)
+	messenger.Success(fmt.Sprintf("Bundle %s created with %d archives", bundleOutput, len(manifest.Entries)))
+	logger.Info("Bundle %s created", bundleOutput)
+	return nil
+}
+
+func runBundleInstall(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	messenger.Info(fmt.Sprintf("Verifying bundle %s...", args[0]))
+	runCtx, cancel := withInterrupt(cmd.Context())
+	defer cancel()
+	result, err := ctx.Bundler.Install(runCtx, args[0])
+	if result != nil {
+		for _, version := range result.Installed {
+			messenger.Success(fmt.Sprintf("Go %s installed", goversion.Display(version)))
+		}
+		for _, version := range result.Skipped {
+			messenger.Info(fmt.Sprintf("Go %s is already installed, skipped", goversion.Display(version)))
+		}
+		for version, reason := range result.Failed {
+			messenger.Error(fmt.Sprintf("Go %s failed: %s", goversion.Display(version), reason))
+		}
+		if len(result.Foreign) > 0 {
+			messenger.Info(fmt.Sprintf("Verified %d archives for other platforms without installing them", len(result.Foreign)))
+		}
+	}
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	logger.Info("Bundle %s installed", args[0])
+	return nil
+}
+
+func runBundleList(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	manifest, err := ctx.Bundler.Inspect(args[0])
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	messenger.Section(fmt.Sprintf("Bundle %s (created %s)", args[0], manifest.Created.Local().Format("2006-01-02 15:04")))
+	printBundleEntries(manifest.Entries)
+	return nil
+}
+
+// printBundleEntries 以表格列出集合中的安装包
+func printBundleEntries(entries []interfaces.BundleEntry) {
+	messenger := ui.NewMessenger(os.Stdout)
+	rows := make([][]string, len(entries))
+	for i, entry := range entries {
+		rows[i] = []string{goversion.Display(entry.Version), entry.OS + "/" + entry.Arch, ui.FormatBytes(entry.Size), entry.SHA256}
+	}
+	messenger.Table([]string{"Version", "Platform", "Size", "SHA256"}, rows)
+}
+
+// parsePlatforms 解析 os/arch 形式的平台列表
+func parsePlatforms(values []string) ([]interfaces.PlatformInfo, error) {
+	var platforms []interfaces.PlatformInfo
+	for _, value := range values {
+		parts := strings.Split(strings.TrimSpace(value), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid platform %q: expected os/arch, e.g. linux/amd64", value)
+		}
+		platforms = append(platforms, interfaces.PlatformInfo{OS: parts[0], Arch: parts[1]})
+	}
+	return platforms, nil
+}
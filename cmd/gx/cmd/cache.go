@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the download cache",
+	Long: `Manage the cache of downloaded release archives.
+
+Every archive gx downloads is kept in the cache, keyed by its SHA256.
+Reinstalling a version, or installing it under another GX_HOME, reuses the
+cached archive instead of downloading it again. Cached archives are checked
+against the checksum from the version list before they are used.
+
+The cache lives in ~/.gx/cache and is shared by all gx homes. Its location
+and size limit are the cache.dir and cache.max-size settings; when the
+cache grows past the limit, the least recently used archives are evicted.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached archives",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheList,
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cached archives and remove corrupted ones",
+	Long: `Recompute the SHA256 of every cached archive. Archives that no longer
+match, or whose file is missing, are removed from the cache and the
+command exits with an error.`,
+	Args: cobra.NoArgs,
+	RunE: runCacheVerify,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean [version...]",
+	Short: "Remove cached archives",
+	Long: `Remove the cached archives of the given exact versions, or empty the
+cache when no version is given.
+
+Example:
+  gx cache clean 1.21.5
+  gx cache clean`,
+	RunE: runCacheClean,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheListCmd, cacheVerifyCmd, cacheCleanCmd)
+}
+
+func runCacheList(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	archiveCache, err := ctx.Cache()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	entries, err := archiveCache.List()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+
+	usage := fmt.Sprintf("%s of %s", ui.FormatBytes(total), ui.FormatBytes(archiveCache.MaxSize()))
+	if archiveCache.MaxSize() == 0 {
+		usage = fmt.Sprintf("disabled: %s is 0", constants.SettingCacheMaxSize)
+	}
+	messenger.Section(fmt.Sprintf("Download cache %s (%s)", archiveCache.Dir(), usage))
+
+	if len(entries) == 0 {
+		messenger.Info("The cache is empty")
+		return nil
+	}
+
+	rows := make([][]string, len(entries))
+	for i, entry := range entries {
+		rows[i] = []string{
+			goversion.Display(entry.Version),
+			entry.Filename,
+			ui.FormatBytes(entry.Size),
+			entry.LastUsed.Local().Format("2006-01-02 15:04"),
+			entry.Source,
+		}
+	}
+	messenger.Table([]string{"Version", "File", "Size", "Last Used", "Source"}, rows)
+	return nil
+}
+
+func runCacheVerify(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	archiveCache, err := ctx.Cache()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	messenger.Info(fmt.Sprintf("Verifying cached archives in %s...", archiveCache.Dir()))
+	result, err := archiveCache.Verify()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	for _, entry := range result.Corrupted {
+		messenger.Error(fmt.Sprintf("%s is corrupted, removed from the cache", entry.Filename))
+	}
+	for _, entry := range result.Missing {
+		messenger.Error(fmt.Sprintf("%s is missing, removed from the cache", entry.Filename))
+	}
+
+	if bad := len(result.Corrupted) + len(result.Missing); bad > 0 {
+		err := errors.ErrChecksumMismatch.WithMessage(fmt.Sprintf("%d of %d cached archives failed verification", bad, bad+len(result.Valid)))
+		errorFormatter.Format(err)
+		return err
+	}
+
+	messenger.Success(fmt.Sprintf("%d cached archives verified", len(result.Valid)))
+	return nil
+}
+
+func runCacheClean(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	archiveCache, err := ctx.Cache()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	removed, err := archiveCache.Clean(args)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	if len(removed) == 0 {
+		if len(args) > 0 {
+			messenger.Info(fmt.Sprintf("No cached archives for %s", strings.Join(args, ", ")))
+		} else {
+			messenger.Info("The cache is already empty")
+		}
+		return nil
+	}
+
+	var freed int64
+	for _, entry := range removed {
+		freed += entry.Size
+	}
+	printCacheEntries(removed)
+	messenger.Success(fmt.Sprintf("Removed %d cached archives (%s freed)", len(removed), ui.FormatBytes(freed)))
+	logger.Info("Removed %d archives from the cache", len(removed))
+	return nil
+}
+
+// printCacheEntries 以表格列出缓存中的安装包
+func printCacheEntries(entries []interfaces.CacheEntry) {
+	messenger := ui.NewMessenger(os.Stdout)
+	rows := make([][]string, len(entries))
+	for i, entry := range entries {
+		rows[i] = []string{goversion.Display(entry.Version), entry.Filename, ui.FormatBytes(entry.Size)}
+	}
+	messenger.Table([]string{"Version", "File", "Size"}, rows)
+}
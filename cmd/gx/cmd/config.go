@@ -0,0 +1,548 @@
+package cmd
+
+import (
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/settings"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+
+	configpkg "github.com/kawaiirei0/gx/internal/config"
+)
+
+var (
+	configShowOrigin bool
+)
+
+// errSettingsChanged 编辑期间用户配置被其他进程修改
+var errSettingsChanged = stderrors.New("settings changed while editing")
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change gx settings",
+	Long: `Inspect and change the settings gx uses in the current directory.
+
+Settings are read from these layers; later layers override earlier ones:
+  1. built-in defaults
+  2. system:  /etc/gx/config (TOML; %ProgramData%\gx\config on Windows, or $GX_SYSTEM_CONFIG)
+  3. user:    the "settings" object in $GX_HOME/config.json
+  4. project: the nearest gx.toml or .gx.json in this or a parent directory;
+     it can only set the settings marked "project" below, because a cloned
+     repository must not choose where toolchains come from
+
+'gx config set', 'unset' and 'edit' change the user layer.`,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List effective settings",
+	Long: `List every effective setting as key=value.
+
+With --show-origin, each line is prefixed with the layer and file the value
+came from, or "default" for built-in values.
+
+Example:
+  gx config list
+  gx config list --show-origin`,
+	Args: cobra.NoArgs,
+	RunE: runConfigList,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a setting",
+	Long: `Print the effective value of a setting.
+
+Example:
+  gx config get download.mirror
+  gx config get install.root --show-origin`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSettingKeys,
+	RunE:              runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a setting in the user config",
+	Long: `Validate a value and store it in the "settings" of $GX_HOME/config.json.
+Relative paths are resolved against the current directory.
+
+Example:
+  gx config set download.mirror https://mirrors.example.com/golang/
+  gx config set download.verify strict
+  gx config set download.timeout 10m
+  gx config set install.root ~/toolchains`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSettingKeyValue,
+	RunE:              runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting from the user config",
+	Long: `Remove a setting from the user config, falling back to the project, system
+or built-in value.
+
+Example:
+  gx config unset download.mirror`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSettingKeys,
+	RunE:              runConfigUnset,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the user settings in $VISUAL or $EDITOR",
+	Long: `Open the user settings as TOML in $VISUAL or $EDITOR. Every known setting
+is listed with its description and default. The edited settings are validated
+before they are saved; if they are invalid you can edit them again.
+
+The config stays locked while the editor is open, so other gx processes that
+change it wait instead of losing their changes.
+
+Example:
+  gx config edit`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd, configGetCmd, configSetCmd, configUnsetCmd, configEditCmd)
+	configListCmd.Flags().BoolVar(&configShowOrigin, "show-origin", false, "show the file each value comes from")
+	configGetCmd.Flags().BoolVar(&configShowOrigin, "show-origin", false, "show the file the value comes from")
+
+	// 在帮助中列出已知配置项
+	var b strings.Builder
+	b.WriteString("\n\nSettings:\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, def := range settings.Definitions() {
+		scope := ""
+		if def.Project {
+			scope = "project"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", def.Key, def.TypeDescription(), scope, def.Description)
+	}
+	w.Flush()
+	configCmd.Long += strings.TrimRight(b.String(), "\n")
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	effective, err := loadSettings()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, setting := range effective.List() {
+		printSetting(w, setting, true)
+	}
+	return w.Flush()
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	effective, err := loadSettings()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	setting, ok := effective.Lookup(args[0])
+	if !ok {
+		err := settings.UnknownKeyError(args[0])
+		errorFormatter.Format(err)
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printSetting(w, setting, false)
+	return w.Flush()
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	key, value := args[0], args[1]
+	value, err := validateSetting(key, value)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	store, err := configpkg.NewStore()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	if err := store.UpdateSettings(func(userSettings map[string]string) error {
+		userSettings[key] = value
+		return nil
+	}); err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	messenger.Success(fmt.Sprintf("%s = %s", key, value))
+	warnIfSettingOverridden(messenger, store, key)
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	key := args[0]
+
+	store, err := configpkg.NewStore()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	// 未知的配置项同样可以删除，便于清理拼写错误
+	found := false
+	if err := store.UpdateSettings(func(userSettings map[string]string) error {
+		_, found = userSettings[key]
+		delete(userSettings, key)
+		return nil
+	}); err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	if !found {
+		messenger.Warning(fmt.Sprintf("%s is not set in the user config", key))
+		return nil
+	}
+
+	messenger.Success(fmt.Sprintf("%s unset", key))
+	if effective, err := store.Settings(""); err == nil {
+		if setting, ok := effective.Lookup(key); ok {
+			messenger.Info(fmt.Sprintf("Now %s = %s (%s)", key, setting.Value, describeOrigin(setting)))
+		}
+	}
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	messenger := ui.NewMessenger(os.Stdout)
+	prompter := ui.NewPrompter(os.Stdin, os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	store, err := configpkg.NewStore()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	configPath, err := configpkg.GetConfigFilePath()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	// 编辑器可能打开很久，编辑期间不持有配置锁；保存时若配置已被修改则提示重新编辑
+	cfg, err := store.Load()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+	base := maps.Clone(cfg.Settings)
+	original := renderUserSettings(base, configPath)
+	content := original
+
+	for {
+		edited, err := editText(content)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(edited, original) {
+			messenger.Info("No changes")
+			return nil
+		}
+		content = edited
+
+		values, err := parseUserSettings(edited)
+		if err == nil {
+			err = store.UpdateSettings(func(userSettings map[string]string) error {
+				if !maps.Equal(userSettings, base) {
+					current := maps.Clone(userSettings)
+					base = current
+					original = renderUserSettings(current, configPath)
+					return errSettingsChanged
+				}
+				for key := range userSettings {
+					delete(userSettings, key)
+				}
+				for key, value := range values {
+					userSettings[key] = value
+				}
+				return nil
+			})
+			if err == nil {
+				messenger.Success(fmt.Sprintf("Settings saved to %s", configPath))
+				return nil
+			}
+			if err != errSettingsChanged {
+				return err
+			}
+			messenger.Warning(fmt.Sprintf("%s was changed by another process while you were editing; saving now would overwrite those changes", configPath))
+		} else {
+			errorFormatter.Format(err)
+		}
+
+		again, promptErr := prompter.Confirm("Edit again?", true)
+		if promptErr != nil || !again {
+			if err == errSettingsChanged {
+				messenger.Info("Settings not saved")
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// loadSettings 加载当前目录下的生效配置
+// 不创建完整的应用上下文，配置项无效时仍可以查看和修改
+func loadSettings() (*interfaces.Settings, error) {
+	store, err := configpkg.NewStore()
+	if err != nil {
+		return nil, err
+	}
+
+	effective, err := store.Settings("")
+	if err != nil {
+		return nil, err
+	}
+	printSettingWarnings(effective)
+	return effective, nil
+}
+
+// printSettingWarnings 在标准错误中列出合并配置时被忽略的配置层和配置项
+func printSettingWarnings(effective *interfaces.Settings) {
+	messenger := ui.NewMessenger(os.Stderr)
+	for _, warning := range effective.Warnings() {
+		messenger.Warning(warning)
+	}
+}
+
+// printSetting 输出一个配置项，list 为 true 时输出 key=value 形式
+func printSetting(w *tabwriter.Writer, setting interfaces.Setting, list bool) {
+	line := setting.Value
+	if list {
+		line = fmt.Sprintf("%s=%s", setting.Key, setting.Value)
+	}
+
+	if configShowOrigin {
+		fmt.Fprintf(w, "%s\t%s\n", describeOrigin(setting), line)
+	} else {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// describeOrigin 返回配置项来源的描述，例如 system:/etc/gx/config
+func describeOrigin(setting interfaces.Setting) string {
+	if setting.Origin == "" {
+		return string(setting.Scope)
+	}
+	return fmt.Sprintf("%s:%s", setting.Scope, setting.Origin)
+}
+
+// validateSetting 检查配置项名称和取值，返回写入用户配置的值
+// 路径类型的相对路径相对于当前目录解析
+func validateSetting(key string, value string) (string, error) {
+	def, ok := settings.Lookup(key)
+	if !ok {
+		return "", settings.UnknownKeyError(key)
+	}
+
+	value, err := def.Validate(value)
+	if err != nil {
+		return "", settings.InvalidValueError(key, "", err)
+	}
+
+	if def.Type == settings.TypePath && !strings.HasPrefix(value, "~") {
+		if abs, err := filepath.Abs(value); err == nil {
+			value = abs
+		}
+	}
+	return value, nil
+}
+
+// warnIfSettingOverridden 用户配置被项目配置覆盖时给出提示
+func warnIfSettingOverridden(messenger *ui.Messenger, store interfaces.ConfigStore, key string) {
+	effective, err := store.Settings("")
+	if err != nil {
+		return
+	}
+
+	if setting, ok := effective.Lookup(key); ok && setting.Scope == interfaces.ScopeProject {
+		messenger.Warning(fmt.Sprintf("Overridden in this directory by %s (%s = %s)", setting.Origin, key, setting.Value))
+	}
+}
+
+// renderUserSettings 将用户配置渲染为供编辑的 TOML
+// 已知配置项都会列出，未设置的以注释形式给出默认值
+func renderUserSettings(userSettings map[string]string, configPath string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# gx user settings, stored in %s\n", configPath)
+	b.WriteString("# Uncomment a line to set it; delete or comment it out to fall back to the\n")
+	b.WriteString("# project, system or built-in value. Run 'gx config list --show-origin' to see\n")
+	b.WriteString("# the effective values.\n")
+
+	for _, def := range settings.Definitions() {
+		fmt.Fprintf(&b, "\n# %s (%s)\n", def.Description, def.TypeDescription())
+		if value, ok := userSettings[def.Key]; ok {
+			fmt.Fprintf(&b, "%s = %s\n", def.Key, formatTOMLValue(def, value))
+			continue
+		}
+		if value, err := def.Default(); err == nil {
+			fmt.Fprintf(&b, "# %s = %s\n", def.Key, formatTOMLValue(def, value))
+		}
+	}
+
+	var unknown []string
+	for key := range userSettings {
+		if _, ok := settings.Lookup(key); !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		b.WriteString("\n# Unknown settings, ignored by gx and removed when saved:\n")
+		for _, key := range unknown {
+			fmt.Fprintf(&b, "# %s = %s\n", key, settings.QuoteTOML(userSettings[key]))
+		}
+	}
+
+	return b.Bytes()
+}
+
+// formatTOMLValue 布尔值和整数原样输出，其他类型输出为字符串
+func formatTOMLValue(def settings.Definition, value string) string {
+	if def.Type == settings.TypeBool || def.Type == settings.TypeInt {
+		return value
+	}
+	return settings.QuoteTOML(value)
+}
+
+// parseUserSettings 解析并校验编辑后的用户配置
+func parseUserSettings(data []byte) (map[string]string, error) {
+	values, err := settings.ParseTOML(data)
+	if err != nil {
+		return nil, settings.InvalidValueError("settings", "", err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, err := validateSetting(key, values[key])
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// editText 在编辑器中编辑 content，返回编辑后的内容
+func editText(content []byte) ([]byte, error) {
+	f, err := os.CreateTemp("", "gx-settings-*.toml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	editor := strings.Fields(editorCommand())
+	command := exec.Command(editor[0], append(editor[1:], path)...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		return nil, fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+
+	return os.ReadFile(path)
+}
+
+// editorCommand 返回编辑器命令：$VISUAL > $EDITOR > 平台默认编辑器
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// completeSettingKeys 补全配置项名称
+func completeSettingKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var keys []string
+	for _, def := range settings.Definitions() {
+		keys = append(keys, def.Key+"\t"+def.Description)
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSettingKeyValue 补全配置项名称，以及枚举和布尔类型的取值
+func completeSettingKeyValue(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeSettingKeys(cmd, args, toComplete)
+	}
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	def, ok := settings.Lookup(args[0])
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	switch def.Type {
+	case settings.TypeEnum:
+		return def.Values, cobra.ShellCompDirectiveNoFileComp
+	case settings.TypeBool:
+		return []string{"true", "false"}, cobra.ShellCompDirectiveNoFileComp
+	case settings.TypePath:
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
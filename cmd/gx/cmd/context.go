@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"context"
+	"sync"
+
+	"github.com/kawaiirei0/gx/internal/bundle"
+	"github.com/kawaiirei0/gx/internal/cache"
 	"github.com/kawaiirei0/gx/internal/crossbuilder"
 	"github.com/kawaiirei0/gx/internal/downloader"
 	"github.com/kawaiirei0/gx/internal/environment"
@@ -18,9 +23,15 @@ type AppContext struct {
 	VersionManager interfaces.VersionManager
 	CLIWrapper     interfaces.CLIWrapper
 	CrossBuilder   interfaces.CrossBuilder
+	Bundler        interfaces.Bundler
 	ConfigStore    interfaces.ConfigStore
+	Storage        interfaces.Storage
 	Platform       interfaces.PlatformAdapter
 	EnvManager     interfaces.EnvironmentManager
+
+	// 生效配置和下载缓存在第一次使用时创建，见 Settings 和 Cache
+	settings *interfaces.Settings
+	cache    interfaces.ArchiveCache
 }
 
 // NewAppContext 创建新的应用程序上下文
@@ -34,11 +45,24 @@ func NewAppContext() (*AppContext, error) {
 		return nil, err
 	}
 
+	// 初始化版本元数据存储
+	storage, err := configpkg.NewStorage(configStore)
+	if err != nil {
+		return nil, err
+	}
+
 	// 初始化环境管理器
 	envManager := environment.NewManager(platformAdapter)
 
-	// 初始化下载器
-	downloaderInstance := downloader.NewDownloader()
+	app := &AppContext{
+		ConfigStore: configStore,
+		Storage:     storage,
+		Platform:    platformAdapter,
+		EnvManager:  envManager,
+	}
+
+	// 初始化下载器，第一次下载时才解析配置
+	downloaderInstance := &lazyDownloader{app: app}
 
 	// 初始化安装器
 	installerInstance := installer.NewInstaller(platformAdapter)
@@ -46,6 +70,7 @@ func NewAppContext() (*AppContext, error) {
 	// 初始化版本管理器
 	versionManager := version.NewManager(
 		configStore,
+		storage,
 		platformAdapter,
 		envManager,
 		downloaderInstance,
@@ -58,12 +83,109 @@ func NewAppContext() (*AppContext, error) {
 	// 初始化跨平台构建器
 	crossBuilderInstance := crossbuilder.NewCrossBuilder(versionManager, platformAdapter)
 
-	return &AppContext{
-		VersionManager: versionManager,
-		CLIWrapper:     cliWrapper,
-		CrossBuilder:   crossBuilderInstance,
-		ConfigStore:    configStore,
-		Platform:       platformAdapter,
-		EnvManager:     envManager,
-	}, nil
+	// 初始化离线安装包集合管理器
+	bundler := bundle.NewBundler(versionManager, downloaderInstance, platformAdapter)
+
+	app.VersionManager = versionManager
+	app.CLIWrapper = cliWrapper
+	app.CrossBuilder = crossBuilderInstance
+	app.Bundler = bundler
+	return app, nil
+}
+
+// Settings 返回当前目录下的生效配置
+// 第一次调用时解析分层配置，并在标准错误中列出被忽略的配置；
+// 不需要配置项的命令不读取系统和项目配置，也不受其中错误的影响
+func (c *AppContext) Settings() (*interfaces.Settings, error) {
+	if c.settings == nil {
+		settings, err := c.ConfigStore.Settings("")
+		if err != nil {
+			return nil, err
+		}
+		printSettingWarnings(settings)
+		c.settings = settings
+	}
+	return c.settings, nil
+}
+
+// Cache 返回下载缓存，位置和容量取自生效配置
+func (c *AppContext) Cache() (interfaces.ArchiveCache, error) {
+	if c.cache == nil {
+		settings, err := c.Settings()
+		if err != nil {
+			return nil, err
+		}
+		archiveCache, err := cache.NewFromSettings(settings)
+		if err != nil {
+			return nil, err
+		}
+		c.cache = archiveCache
+	}
+	return c.cache, nil
+}
+
+// lazyDownloader 第一次使用时才根据生效配置创建下载器
+type lazyDownloader struct {
+	app        *AppContext
+	once       sync.Once
+	downloader interfaces.Downloader
+	err        error
+}
+
+// get 返回根据生效配置创建的下载器
+func (d *lazyDownloader) get() (interfaces.Downloader, error) {
+	d.once.Do(func() {
+		settings, err := d.app.Settings()
+		if err != nil {
+			d.err = err
+			return
+		}
+		archiveCache, err := d.app.Cache()
+		if err != nil {
+			d.err = err
+			return
+		}
+		d.downloader = downloader.NewDownloader(settings, archiveCache)
+	})
+	return d.downloader, d.err
+}
+
+func (d *lazyDownloader) Download(ctx context.Context, version string, destPath string, progress interfaces.ProgressCallback) (*interfaces.DownloadResult, error) {
+	dl, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return dl.Download(ctx, version, destPath, progress)
+}
+
+func (d *lazyDownloader) DownloadFor(ctx context.Context, version string, os string, arch string, destPath string, progress interfaces.ProgressCallback) (*interfaces.DownloadResult, error) {
+	dl, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return dl.DownloadFor(ctx, version, os, arch, destPath, progress)
+}
+
+func (d *lazyDownloader) DownloadSource(ctx context.Context, version string, destPath string, progress interfaces.ProgressCallback) (*interfaces.DownloadResult, error) {
+	dl, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return dl.DownloadSource(ctx, version, destPath, progress)
+}
+
+func (d *lazyDownloader) GetDownloadURL(ctx context.Context, version string, os string, arch string) (string, error) {
+	dl, err := d.get()
+	if err != nil {
+		return "", err
+	}
+	return dl.GetDownloadURL(ctx, version, os, arch)
+}
+
+func (d *lazyDownloader) Versions(ctx context.Context, all bool) ([]interfaces.RemoteVersion, error) {
+	dl, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return dl.Versions(ctx, all)
 }
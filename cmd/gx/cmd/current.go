@@ -3,16 +3,22 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/internal/version"
+	"github.com/kawaiirei0/gx/pkg/goversion"
 )
 
 var currentCmd = &cobra.Command{
 	Use:   "current",
 	Short: "Show the current active Go version",
-	Long: `Display the currently active Go version managed by gx.
+	Long: `Display the Go version used in the current directory and where it was selected.
+
+The version is resolved in this order:
+  1. GX_VERSION environment variable
+  2. the nearest .go-version file in this or a parent directory
+  3. the global default set by 'gx global' or 'gx use'
 
 Example:
   gx current`,
@@ -32,13 +38,14 @@ func runCurrent(cmd *cobra.Command, args []string) error {
 	messenger := ui.NewMessenger(os.Stdout)
 	errorFormatter := ui.NewErrorFormatter(os.Stderr)
 
-	activeVersion, err := ctx.VersionManager.GetActive()
+	activeVersion, err := ctx.VersionManager.Resolve("")
 	if err != nil {
 		errorFormatter.Format(err)
 		return err
 	}
 
-	messenger.Success(fmt.Sprintf("Current Go version: %s", strings.TrimPrefix(activeVersion.Version, "go")))
+	messenger.Success(fmt.Sprintf("Current Go version: %s", goversion.Display(activeVersion.Version)))
+	messenger.Info(fmt.Sprintf("Set by: %s", version.DescribeSource(activeVersion.Source, activeVersion.SourcePath)))
 
 	if verbose {
 		fmt.Println()
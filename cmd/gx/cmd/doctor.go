@@ -7,6 +7,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/kawaiirei0/gx/internal/logger"
 	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
 )
 
 var (
@@ -55,7 +57,12 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	messenger.Info(fmt.Sprintf("Install path: %s", cfg.InstallPath))
+	effective, err := ctx.Settings()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+	messenger.Info(fmt.Sprintf("Install path: %s", effective.Get(constants.SettingInstallRoot)))
 	fmt.Println()
 
 	// 检查问题
@@ -122,22 +129,25 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 			fmt.Println()
 			messenger.Info("Fixing issues...")
 
-			// 删除无效的版本记录
-			for version := range invalidVersions {
-				delete(cfg.Versions, version)
-				messenger.Info(fmt.Sprintf("  Removed invalid version: %s", version))
-			}
-
-			// 如果激活版本无效，清除它
-			if cfg.ActiveVersion != "" {
-				if _, exists := invalidVersions[cfg.ActiveVersion]; exists {
-					cfg.ActiveVersion = ""
-					messenger.Info("  Cleared invalid active version")
+			// 在配置锁内修改，避免覆盖其他 gx 进程同时做出的修改
+			err := ctx.ConfigStore.Update(func(cfg *interfaces.Config) error {
+				// 删除无效的版本记录
+				for version := range invalidVersions {
+					delete(cfg.Versions, version)
+					delete(cfg.Linked, version)
+					messenger.Info(fmt.Sprintf("  Removed invalid version: %s", version))
 				}
-			}
 
-			// 保存配置
-			if err := ctx.ConfigStore.Save(cfg); err != nil {
+				// 如果激活版本无效，清除它
+				if cfg.ActiveVersion != "" {
+					if _, exists := invalidVersions[cfg.ActiveVersion]; exists {
+						cfg.ActiveVersion = ""
+						messenger.Info("  Cleared invalid active version")
+					}
+				}
+				return nil
+			})
+			if err != nil {
 				errorFormatter.Format(fmt.Errorf("failed to save config: %w", err))
 				return err
 			}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/environment"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+var (
+	envShell string
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env [version]",
+	Short: "Print shell commands that select a Go version for the current session",
+	Long: `Print GOROOT, PATH and GX_VERSION assignments for the given Go version.
+Evaluate the output to switch only the current shell, without touching any
+shell configuration files. Without a version, uses the version resolved for
+the current directory.
+
+Supported shells: bash, zsh, fish, sh, powershell (detected from $SHELL by default).
+
+Example:
+  eval "$(gx env 1.22.0 --shell bash)"
+  gx env 1.22.0 --shell fish | source
+  gx env 1.22.0 --shell powershell | Invoke-Expression`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEnv,
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().StringVar(&envShell, "shell", "", "shell syntax to emit (bash, zsh, fish, sh, powershell)")
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	vars, err := sessionEnvFor(ctx, args)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	shell := envShell
+	if shell == "" {
+		shell = environment.DetectShell()
+	}
+
+	script, err := environment.FormatExports(shell, vars)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	fmt.Print(script)
+	return nil
+}
+
+// sessionEnvFor 计算会话级环境变量；args 为空时使用当前目录解析出的版本
+func sessionEnvFor(ctx *AppContext, args []string) (map[string]string, error) {
+	var target *interfaces.GoVersion
+	system := false
+	if len(args) == 0 {
+		resolved, err := ctx.VersionManager.Resolve("")
+		if err != nil {
+			return nil, err
+		}
+		target = &resolved.GoVersion
+		system = resolved.Source == interfaces.VersionSourceSystem
+	} else {
+		installed, err := lookupInstalledVersion(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+		target = installed
+	}
+
+	return sessionEnvForVersion(ctx, target, system)
+}
+
+// sessionEnvForVersion 计算指定版本的会话级环境变量
+// system 表示 target 是系统 PATH 中的 Go
+func sessionEnvForVersion(ctx *AppContext, target *interfaces.GoVersion, system bool) (map[string]string, error) {
+	vars, err := ctx.EnvManager.SessionEnv(target.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 让 gx 命令和 shims 在该会话中解析到同一版本
+	// 系统 Go 没有安装在 gx 中，导出它的版本号会让 shims 报告版本未安装
+	if !system {
+		vars[constants.EnvGxVersion] = goversion.Display(target.Version)
+	}
+	return vars, nil
+}
+
+// lookupInstalledVersion 查找与版本说明符匹配的已安装版本
+func lookupInstalledVersion(ctx *AppContext, spec string) (*interfaces.GoVersion, error) {
+	version, err := ctx.VersionManager.FindInstalled(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := ctx.ConfigStore.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	versionPath := cfg.Versions[version]
+
+	return &interfaces.GoVersion{
+		Version:  version,
+		Path:     versionPath,
+		IsActive: version == cfg.ActiveVersion,
+	}, nil
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/environment"
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/internal/wrapper"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+var (
+	execAll bool
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec (<version> | --all) [--] <command> [arguments...]",
+	Short: "Run a command with a specific Go version",
+	Long: `Run any command (make, golangci-lint, dlv, ...) with GOROOT, PATH and
+GX_VERSION pointing at an installed Go version, without switching globally.
+The command's exit code is preserved.
+
+With --all, the command runs once per installed version and a summary is
+printed at the end. The exit code is non-zero if any run failed.
+
+Example:
+  gx exec 1.21.5 -- golangci-lint run
+  gx exec 1.22 make test
+  gx exec prod -- dlv debug
+  gx exec --all -- go test ./...`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().BoolVar(&execAll, "all", false, "run the command once for every installed version")
+	// 版本号之后的参数全部交给被执行的命令
+	execCmd.Flags().SetInterspersed(false)
+}
+
+// execResult 记录 --all 模式下单个版本的执行结果
+type execResult struct {
+	version  string
+	exitCode int
+	err      error
+	elapsed  time.Duration
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	if execAll {
+		command := trimDashes(args)
+		if len(command) == 0 {
+			return fmt.Errorf("no command specified")
+		}
+		return runExecAll(ctx, command)
+	}
+
+	command := trimDashes(args[1:])
+	if len(command) == 0 {
+		return fmt.Errorf("no command specified")
+	}
+
+	target, err := lookupInstalledVersion(ctx, args[0])
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	if err := execWithVersion(ctx, target, command); err != nil {
+		// 保留原始退出码
+		if exitErr, ok := wrapper.IsExitError(err); ok {
+			os.Exit(exitErr.GetExitCode())
+		}
+		errorFormatter.Format(err)
+		return err
+	}
+
+	return nil
+}
+
+// runExecAll 依次在每个已安装版本下运行命令并输出汇总
+func runExecAll(ctx *AppContext, command []string) error {
+	messenger := ui.NewMessenger(os.Stderr)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	cfg, err := ctx.ConfigStore.Load()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	if len(cfg.Versions) == 0 {
+		messenger.Warning("No Go versions installed by gx")
+		return nil
+	}
+
+	versions := make([]string, 0, len(cfg.Versions))
+	for v := range cfg.Versions {
+		versions = append(versions, v)
+	}
+	goversion.Sort(versions)
+
+	results := make([]execResult, 0, len(versions))
+	for _, v := range versions {
+		messenger.Section(fmt.Sprintf("Go %s", goversion.Display(v)))
+
+		target := &interfaces.GoVersion{Version: v, Path: cfg.Versions[v]}
+		start := time.Now()
+		err := execWithVersion(ctx, target, command)
+
+		result := execResult{version: v, err: err, elapsed: time.Since(start)}
+		if exitErr, ok := wrapper.IsExitError(err); ok {
+			result.exitCode = exitErr.GetExitCode()
+		} else if err != nil {
+			result.exitCode = 1
+			errorFormatter.Format(err)
+		}
+		results = append(results, result)
+	}
+
+	// 汇总
+	messenger.Section("Summary")
+	fmt.Fprintln(os.Stderr)
+
+	failed := 0
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		status := "✓ ok"
+		if r.err != nil {
+			failed++
+			status = fmt.Sprintf("✗ exit %d", r.exitCode)
+		}
+		rows[i] = []string{goversion.Display(r.version), status, r.elapsed.Round(time.Millisecond).String()}
+	}
+	messenger.Table([]string{"Version", "Result", "Time"}, rows)
+	fmt.Fprintln(os.Stderr)
+
+	if failed > 0 {
+		messenger.Error(fmt.Sprintf("%d of %d versions failed", failed, len(results)))
+		os.Exit(1)
+	}
+
+	messenger.Success(fmt.Sprintf("All %d versions succeeded", len(results)))
+	return nil
+}
+
+// execWithVersion 在指定版本的环境中运行命令，透传标准输入输出
+func execWithVersion(ctx *AppContext, target *interfaces.GoVersion, command []string) error {
+	vars, err := sessionEnvForVersion(ctx, target, false)
+	if err != nil {
+		return err
+	}
+
+	// 在子进程的 PATH 中查找命令，使 "go" 等命令解析到所选版本
+	program, err := wrapper.LookPathIn(command[0], vars[constants.EnvPath])
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Executing %s with Go %s", program, target.Version)
+	ctx.VersionManager.RecordUsage(target.Version)
+
+	child := exec.Command(program, command[1:]...)
+	child.Env = environment.MergeEnv(os.Environ(), vars)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	return wrapper.Run(child)
+}
+
+// trimDashes 去掉命令前的 "--" 分隔符
+func trimDashes(args []string) []string {
+	if len(args) > 0 && args[0] == "--" {
+		return args[1:]
+	}
+	return args
+}
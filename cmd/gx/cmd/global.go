@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/internal/version"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+)
+
+var globalCmd = &cobra.Command{
+	Use:   "global [version]",
+	Short: "Set or show the global default Go version",
+	Long: `Set the global default Go version.
+The global default is used when neither GX_VERSION nor a .go-version file selects a version.
+Without arguments, shows the current global default.
+
+Example:
+  gx global 1.22.0
+  gx global 1.22     # newest installed 1.22.x
+  gx global`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGlobal,
+}
+
+func init() {
+	rootCmd.AddCommand(globalCmd)
+}
+
+func runGlobal(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	if len(args) == 0 {
+		cfg, err := ctx.ConfigStore.Load()
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		if cfg.ActiveVersion == "" {
+			messenger.Info("No global default version set")
+			return nil
+		}
+		messenger.Success(fmt.Sprintf("Global Go version: %s", goversion.Display(cfg.ActiveVersion)))
+		return nil
+	}
+
+	target, err := ctx.VersionManager.FindInstalled(args[0])
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	if err := ctx.VersionManager.SwitchTo(target); err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	messenger.Success(fmt.Sprintf("Global Go version set to %s", goversion.Display(target)))
+	warnIfOverridden(ctx, messenger, target)
+
+	return nil
+}
+
+// warnIfOverridden 当前目录的 GX_VERSION 或 .go-version 覆盖了全局版本时给出提示
+func warnIfOverridden(ctx *AppContext, messenger *ui.Messenger, globalVersion string) {
+	resolved, err := ctx.VersionManager.Resolve("")
+	if err != nil || resolved.Version == globalVersion {
+		return
+	}
+
+	fmt.Println()
+	messenger.Warning(fmt.Sprintf("Go %s is still used in this directory (set by %s)",
+		goversion.Display(resolved.Version), version.DescribeSource(resolved.Source, resolved.SourcePath)))
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -8,10 +9,19 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/kawaiirei0/gx/internal/logger"
 	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/internal/version"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
 )
 
 var (
 	installInteractive bool
+	installFromSource  string
+	installName        string
+	installBootstrap   string
+	installArchive     string
+	installSHA256      string
 )
 
 var installCmd = &cobra.Command{
@@ -22,8 +32,33 @@ If no version is specified, installs the latest stable version.
 
 Example:
   gx install 1.21.5
+  gx install 1.22   # newest 1.22.x patch release
+  gx install stable # also: latest, oldstable
+  gx install prod   # an alias created with 'gx alias'
   gx install        # installs latest version
-  gx install -i     # interactive version selection`,
+  gx install -i     # interactive version selection
+
+Build from source:
+  gx install tip                          # latest commit on master, registered as "tip"
+  gx install --from-source 1.22.3         # official source archive
+  gx install --from-source release-branch.go1.22 --name go122-fix
+  gx install --from-source ~/src/go       # local source tree
+
+A source build runs make.bash with the newest installed release as the
+bootstrap toolchain (see --bootstrap). Builds from git refs and source
+trees are registered under a name (src-<ref> unless --name is given)
+that works with 'gx use', 'gx local' and the other commands.
+Running 'gx install tip' again rebuilds tip at the latest commit.
+
+Offline install:
+  gx install --archive ./go1.22.3.linux-amd64.tar.gz
+  gx install --archive ./go.tar.gz --sha256 <checksum>
+  gx install --archive ./go.tar.gz 1.22.3   # version given explicitly
+
+The version is taken from the file name or the VERSION file inside the
+archive. The archive is verified against --sha256 or, if present, a
+<archive>.sha256 file next to it; without either, download.verify=strict
+refuses the install. The archive itself is left in place.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInstall,
 }
@@ -31,6 +66,11 @@ Example:
 func init() {
 	rootCmd.AddCommand(installCmd)
 	installCmd.Flags().BoolVarP(&installInteractive, "interactive", "i", false, "interactive version selection")
+	installCmd.Flags().StringVar(&installFromSource, "from-source", "", "build from source: a version, git ref or source directory")
+	installCmd.Flags().StringVar(&installName, "name", "", "name to register a source build under")
+	installCmd.Flags().StringVar(&installBootstrap, "bootstrap", "", "installed version used to bootstrap a source build")
+	installCmd.Flags().StringVar(&installArchive, "archive", "", "install from a local release archive instead of downloading")
+	installCmd.Flags().StringVar(&installSHA256, "sha256", "", "expected SHA256 of the --archive file")
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
@@ -46,13 +86,43 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	prompter := ui.NewPrompter(os.Stdin, os.Stdout)
 	errorFormatter := ui.NewErrorFormatter(os.Stderr)
 
+	if installSHA256 != "" && installArchive == "" {
+		return fmt.Errorf("--sha256 only applies to --archive")
+	}
+
+	// 从本地安装包离线安装
+	if installArchive != "" {
+		if installFromSource != "" || installName != "" || installBootstrap != "" || installInteractive {
+			return fmt.Errorf("--archive cannot be combined with --from-source, --name, --bootstrap or --interactive")
+		}
+		explicit := ""
+		if len(args) == 1 {
+			explicit = args[0]
+		}
+		return runInstallArchive(cmd.Context(), ctx, installArchive, explicit)
+	}
+
+	// 从源码构建
+	if installFromSource != "" || (len(args) == 1 && strings.EqualFold(args[0], constants.TipVersion)) {
+		source := installFromSource
+		if source == "" {
+			source = constants.TipVersion
+		} else if len(args) > 0 {
+			return fmt.Errorf("--from-source takes the source as its value; remove the extra argument %q", args[0])
+		}
+		return runInstallFromSource(cmd.Context(), ctx, source)
+	}
+	if installName != "" || installBootstrap != "" {
+		return fmt.Errorf("--name and --bootstrap only apply to source builds (--from-source or tip)")
+	}
+
 	var versionToInstall string
 
 	// 交互式版本选择
 	if installInteractive && len(args) == 0 {
 		messenger.Info("Fetching available Go versions...")
 
-		versions, err := ctx.VersionManager.ListAvailable()
+		versions, err := ctx.VersionManager.ListAvailable(cmd.Context())
 		if err != nil {
 			errorFormatter.Format(err)
 			return err
@@ -67,7 +137,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		// 格式化版本显示
 		displayVersions := make([]string, len(stableVersions))
 		for i, v := range stableVersions {
-			displayVersions[i] = strings.TrimPrefix(v, "go")
+			displayVersions[i] = goversion.Display(v)
 		}
 
 		selected, err := prompter.SelectVersion(displayVersions, 10)
@@ -76,22 +146,20 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		}
 
 		versionToInstall = selected
-		if !strings.HasPrefix(versionToInstall, "go") {
-			versionToInstall = "go" + versionToInstall
-		}
+		versionToInstall = goversion.Normalize(versionToInstall)
 	} else if len(args) == 0 {
 		// 如果没有指定版本，获取最新版本
 		messenger.Info("Fetching latest Go version...")
-		latest, err := ctx.VersionManager.GetLatest()
+		latest, err := ctx.VersionManager.GetLatest(cmd.Context())
 		if err != nil {
 			errorFormatter.Format(err)
 			return err
 		}
 		versionToInstall = latest
-		messenger.Info(fmt.Sprintf("Latest version: %s", strings.TrimPrefix(versionToInstall, "go")))
+		messenger.Info(fmt.Sprintf("Latest version: %s", goversion.Display(versionToInstall)))
 
 		// 确认安装
-		confirmed, err := prompter.Confirm(fmt.Sprintf("Install Go %s?", strings.TrimPrefix(versionToInstall, "go")), true)
+		confirmed, err := prompter.Confirm(fmt.Sprintf("Install Go %s?", goversion.Display(versionToInstall)), true)
 		if err != nil {
 			return err
 		}
@@ -100,14 +168,19 @@ func runInstall(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 	} else {
-		versionToInstall = args[0]
-		// 规范化版本号
-		if !strings.HasPrefix(versionToInstall, "go") {
-			versionToInstall = "go" + versionToInstall
+		// 将版本说明符（1.22、latest、别名等）解析为具体版本
+		resolved, err := ctx.VersionManager.FindRemote(cmd.Context(), args[0])
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
 		}
+		if goversion.Display(resolved) != goversion.Display(args[0]) {
+			messenger.Info(fmt.Sprintf("Resolved %s to Go %s", args[0], goversion.Display(resolved)))
+		}
+		versionToInstall = resolved
 	}
 
-	messenger.Info(fmt.Sprintf("Installing Go %s...", strings.TrimPrefix(versionToInstall, "go")))
+	messenger.Info(fmt.Sprintf("Installing Go %s...", goversion.Display(versionToInstall)))
 
 	// 创建进度条
 	var progressBar *ui.ProgressBar
@@ -122,8 +195,10 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// 执行安装
-	err = ctx.VersionManager.Install(versionToInstall, progressCallback)
+	// 执行安装，Ctrl-C 时中止并回滚
+	runCtx, cancel := withInterrupt(cmd.Context())
+	defer cancel()
+	err = ctx.VersionManager.Install(runCtx, versionToInstall, progressCallback)
 	if err != nil {
 		if progressBar != nil {
 			fmt.Println() // 换行
@@ -137,11 +212,95 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		progressBar.Finish()
 	}
 
-	messenger.Success(fmt.Sprintf("Go %s installed successfully", strings.TrimPrefix(versionToInstall, "go")))
+	messenger.Success(fmt.Sprintf("Go %s installed successfully", goversion.Display(versionToInstall)))
 	fmt.Println()
 	messenger.Info("To use this version, run:")
-	fmt.Printf("  gx use %s\n", strings.TrimPrefix(versionToInstall, "go"))
+	fmt.Printf("  gx use %s\n", goversion.Display(versionToInstall))
 
 	logger.Info("Install command completed successfully for version %s", versionToInstall)
 	return nil
 }
+
+// runInstallFromSource 从源码构建并安装 Go
+func runInstallFromSource(parent context.Context, ctx *AppContext, source string) error {
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	messenger.Info(fmt.Sprintf("Building Go from source (%s)...", source))
+
+	var progressBar *ui.ProgressBar
+	downloaded := false
+	progressCallback := func(current, total int64) {
+		if downloaded {
+			return
+		}
+		if progressBar == nil && total > 0 {
+			progressBar = ui.NewProgressBar(os.Stdout, total, "Downloading source")
+		}
+		if progressBar != nil {
+			progressBar.Update(current)
+			// 下载完成后换行，后面是构建脚本的输出
+			if current >= total {
+				progressBar.Finish()
+				downloaded = true
+			}
+		}
+	}
+
+	runCtx, cancel := withInterrupt(parent)
+	defer cancel()
+	name, err := ctx.VersionManager.InstallFromSource(runCtx, source, interfaces.SourceOptions{
+		Name:      installName,
+		Bootstrap: installBootstrap,
+		Progress:  progressCallback,
+		Output:    os.Stdout,
+	})
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	messenger.Success(fmt.Sprintf("Go %s built from source and installed", goversion.Display(name)))
+	fmt.Println()
+	messenger.Info("To use this version, run:")
+	fmt.Printf("  gx use %s\n", goversion.Display(name))
+
+	logger.Info("Source build of %s completed successfully", name)
+	return nil
+}
+
+// runInstallArchive 从本地安装包安装 Go，不访问网络
+func runInstallArchive(parent context.Context, ctx *AppContext, archivePath, explicitVersion string) error {
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	effective, err := ctx.Settings()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+	if installSHA256 == "" && !version.HasChecksumFile(archivePath) && effective.Get(constants.SettingDownloadVerify) != constants.VerifyStrict {
+		messenger.Warning(fmt.Sprintf("No --sha256 given and no %s%s found; the archive will not be verified", archivePath, constants.ChecksumFileSuffix))
+	}
+
+	messenger.Info(fmt.Sprintf("Installing Go from %s...", archivePath))
+
+	runCtx, cancel := withInterrupt(parent)
+	defer cancel()
+	installed, err := ctx.VersionManager.InstallArchive(runCtx, archivePath, interfaces.ArchiveOptions{
+		Version: explicitVersion,
+		SHA256:  installSHA256,
+	})
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	messenger.Success(fmt.Sprintf("Go %s installed successfully", goversion.Display(installed)))
+	fmt.Println()
+	messenger.Info("To use this version, run:")
+	fmt.Printf("  gx use %s\n", goversion.Display(installed))
+
+	logger.Info("Archive install of %s completed successfully", installed)
+	return nil
+}
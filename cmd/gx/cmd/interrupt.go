@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// interruptExitCode 操作被中断时的退出码，与 shell 中被 SIGINT 终止的进程一致
+const interruptExitCode = 130
+
+// withInterrupt 返回在收到 SIGINT 或 SIGTERM 时取消的 context，用于会修改安装目录的命令
+// 第一次中断时取消操作，由操作自行回滚后返回 ErrCancelled；
+// 之后恢复信号的默认行为，再次中断时立即退出
+func withInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			fmt.Fprintln(os.Stderr, "\nInterrupted, rolling back... (interrupt again to exit immediately)")
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(signals)
+		}
+	}()
+
+	return ctx, cancel
+}
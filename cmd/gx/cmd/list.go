@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
-	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
 )
 
 var (
@@ -38,7 +41,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	if listRemote {
-		return listRemoteVersions(ctx)
+		return listRemoteVersions(cmd.Context(), ctx)
 	}
 
 	return listInstalledVersions(ctx)
@@ -65,36 +68,50 @@ func listInstalledVersions(ctx *AppContext) error {
 	messenger.Section("Installed Go Versions")
 	fmt.Println()
 
-	// 按版本号排序
-	sort.Slice(versions, func(i, j int) bool {
-		return versions[i].Version < versions[j].Version
+	// 按版本号排序（go1.9 < go1.21rc1 < go1.21.0）
+	sort.SliceStable(versions, func(i, j int) bool {
+		return goversion.Compare(versions[i].Version, versions[j].Version) < 0
 	})
 
 	// 准备表格数据
 	if verbose {
-		headers := []string{"Status", "Version", "Path", "Installed"}
+		headers := []string{"Status", "Version", "Origin", "Size", "Installed", "Took", "Last Used", "Path"}
 		rows := make([][]string, len(versions))
 
+		var sourceRows [][]string
 		for i, v := range versions {
 			status := " "
 			if v.IsActive {
 				status = "✓"
 			}
 
-			installDate := ""
-			if !v.InstallDate.IsZero() {
-				installDate = v.InstallDate.Format("2006-01-02")
-			}
-
 			rows[i] = []string{
 				status,
-				strings.TrimPrefix(v.Version, "go"),
+				goversion.Display(v.Version),
+				versionOrigin(v),
+				orDash(v.Size > 0, func() string { return ui.FormatBytes(v.Size) }),
+				orDash(!v.InstallDate.IsZero(), func() string { return v.InstallDate.Format("2006-01-02 15:04") }),
+				orDash(v.InstallDuration > 0, func() string { return v.InstallDuration.Round(time.Millisecond).String() }),
+				orDash(!v.LastUsed.IsZero(), func() string { return v.LastUsed.Format("2006-01-02 15:04") }),
 				v.Path,
-				installDate,
+			}
+
+			if v.SourceURL != "" || v.SHA256 != "" {
+				sourceRows = append(sourceRows, []string{
+					goversion.Display(v.Version),
+					orDash(v.SHA256 != "", func() string { return v.SHA256 }),
+					orDash(v.ReleaseSource != "", func() string { return v.ReleaseSource }),
+					orDash(v.SourceURL != "", func() string { return v.SourceURL }),
+				})
 			}
 		}
 
 		messenger.Table(headers, rows)
+
+		if len(sourceRows) > 0 {
+			fmt.Println()
+			messenger.Table([]string{"Version", "SHA256", "Served By", "Source"}, sourceRows)
+		}
 	} else {
 		// 简单列表显示
 		for _, v := range versions {
@@ -104,7 +121,10 @@ func listInstalledVersions(ctx *AppContext) error {
 				marker = "✓"
 				status = " (active)"
 			}
-			fmt.Printf("%s %s%s\n", marker, strings.TrimPrefix(v.Version, "go"), status)
+			if v.Linked {
+				status += " (linked)"
+			}
+			fmt.Printf("%s %s%s\n", marker, goversion.Display(v.Version), status)
 		}
 	}
 
@@ -116,7 +136,28 @@ func listInstalledVersions(ctx *AppContext) error {
 	return nil
 }
 
-func listRemoteVersions(ctx *AppContext) error {
+// versionOrigin 返回版本来源的显示文本
+// 元数据功能加入之前安装的版本没有来源记录
+func versionOrigin(v interfaces.GoVersion) string {
+	switch {
+	case v.Origin != "":
+		return string(v.Origin)
+	case v.Linked:
+		return string(interfaces.OriginAdopted)
+	default:
+		return "-"
+	}
+}
+
+// orDash 条件成立时返回 value() 的结果，否则返回 "-"
+func orDash(ok bool, value func() string) string {
+	if !ok {
+		return "-"
+	}
+	return value()
+}
+
+func listRemoteVersions(parent context.Context, ctx *AppContext) error {
 	messenger := ui.NewMessenger(os.Stdout)
 	errorFormatter := ui.NewErrorFormatter(os.Stderr)
 
@@ -138,7 +179,7 @@ func listRemoteVersions(ctx *AppContext) error {
 		}
 	}()
 
-	versions, err := ctx.VersionManager.ListAvailable()
+	versions, err := ctx.VersionManager.ListAvailable(parent)
 	done <- true
 	spinner.Clear()
 
@@ -170,7 +211,7 @@ func listRemoteVersions(ctx *AppContext) error {
 			idx := row + col*rows
 			if idx < maxDisplay {
 				version := versions[idx]
-				displayVersion := strings.TrimPrefix(version, "go")
+				displayVersion := goversion.Display(version)
 				fmt.Printf("  %-15s", displayVersion)
 			}
 		}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/internal/version"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+)
+
+var (
+	localUnset bool
+)
+
+var localCmd = &cobra.Command{
+	Use:   "local [version]",
+	Short: "Pin a Go version for the current directory",
+	Long: `Write a .go-version file in the current directory.
+gx commands run in this directory or any subdirectory will use the pinned version.
+Without arguments, shows the version pinned for the current directory.
+
+Example:
+  gx local 1.21.5
+  gx local          # show the pinned version
+  gx local --unset  # remove the .go-version file`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLocal,
+}
+
+func init() {
+	rootCmd.AddCommand(localCmd)
+	localCmd.Flags().BoolVar(&localUnset, "unset", false, "remove the .go-version file in the current directory")
+}
+
+func runLocal(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	// 移除当前目录的 .go-version
+	if localUnset {
+		versionFile := filepath.Join(wd, constants.VersionFileName)
+		if _, err := os.Stat(versionFile); os.IsNotExist(err) {
+			messenger.Info(fmt.Sprintf("No %s file in the current directory", constants.VersionFileName))
+			return nil
+		}
+		if err := os.Remove(versionFile); err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		messenger.Success(fmt.Sprintf("Removed %s", versionFile))
+		return nil
+	}
+
+	// 显示当前目录生效的 .go-version
+	if len(args) == 0 {
+		versionFile, err := version.FindVersionFile(wd)
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		if versionFile == "" {
+			messenger.Info(fmt.Sprintf("No %s file found in this directory or its parents", constants.VersionFileName))
+			return nil
+		}
+
+		pinned, err := version.ReadVersionFile(versionFile)
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		messenger.Success(fmt.Sprintf("Pinned Go version: %s", goversion.Display(pinned)))
+		messenger.Info(fmt.Sprintf("Set by: %s", versionFile))
+		return nil
+	}
+
+	target := args[0]
+	if err := ctx.VersionManager.SetLocal(wd, target); err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	versionDisplay := goversion.Display(target)
+	messenger.Success(fmt.Sprintf("Pinned Go %s in %s", versionDisplay, filepath.Join(wd, constants.VersionFileName)))
+
+	// 提示尚未安装的版本
+	if _, err := ctx.VersionManager.Resolve(wd); err != nil {
+		fmt.Println()
+		messenger.Warning(fmt.Sprintf("Go %s is not installed yet. Install it using:", versionDisplay))
+		fmt.Printf("  gx install %s\n", versionDisplay)
+	}
+
+	return nil
+}
@@ -3,18 +3,23 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/kawaiirei0/gx/internal/logger"
 	"github.com/kawaiirei0/gx/internal/ui"
+
+	configpkg "github.com/kawaiirei0/gx/internal/config"
 )
 
 var migrateCmd = &cobra.Command{
 	Use:   "migrate-config",
-	Short: "Migrate configuration to new format",
-	Long: `Migrate configuration file to use consistent version number format.
-This command will update version numbers to include the 'go' prefix.
+	Short: "Migrate configuration to the current schema",
+	Long: `Upgrade the configuration file to the schema version used by this gx.
+
+Migrations normally run automatically the first time gx loads an older
+configuration; this command runs them explicitly and shows what changed.
+A backup of the configuration is written before each migration step
+(config.json.v<N>.bak). Configurations written by a newer gx are refused.
 
 Example:
   gx migrate-config`,
@@ -28,81 +33,59 @@ func init() {
 func runMigrate(cmd *cobra.Command, args []string) error {
 	logger.Info("Config migration started")
 
-	ctx, err := NewAppContext()
-	if err != nil {
-		return fmt.Errorf("failed to initialize: %w", err)
-	}
-
 	messenger := ui.NewMessenger(os.Stdout)
 	errorFormatter := ui.NewErrorFormatter(os.Stderr)
 
 	messenger.Section("Configuration Migration")
 	fmt.Println()
 
-	// 加载配置
-	cfg, err := ctx.ConfigStore.Load()
+	messenger.Info("Checking configuration schema...")
+
+	// 在创建应用上下文之前读取 schema 版本，任何加载配置的操作都会先完成迁移
+	from, err := configpkg.FileSchemaVersion()
 	if err != nil {
-		errorFormatter.Format(fmt.Errorf("failed to load config: %w", err))
+		errorFormatter.Format(err)
 		return err
 	}
 
-	messenger.Info("Checking configuration format...")
-	fmt.Println()
-
-	// 检查是否需要迁移
-	needsMigration := false
-	migratedVersions := make(map[string]string)
-	var newActiveVersion string
-
-	// 迁移 versions 映射
-	for version, path := range cfg.Versions {
-		normalizedVersion := version
-		if !strings.HasPrefix(version, "go") {
-			normalizedVersion = "go" + version
-			needsMigration = true
-			messenger.Info(fmt.Sprintf("  %s → %s", version, normalizedVersion))
-		}
-		migratedVersions[normalizedVersion] = path
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
 	}
 
-	// 迁移 active_version
-	if cfg.ActiveVersion != "" && !strings.HasPrefix(cfg.ActiveVersion, "go") {
-		newActiveVersion = "go" + cfg.ActiveVersion
-		needsMigration = true
+	pending := configpkg.PendingMigrations(from)
+	if len(pending) == 0 && from == configpkg.CurrentSchemaVersion {
 		fmt.Println()
-		messenger.Info(fmt.Sprintf("Active version: %s → %s", cfg.ActiveVersion, newActiveVersion))
-	} else {
-		newActiveVersion = cfg.ActiveVersion
+		messenger.Success(fmt.Sprintf("Configuration is already at schema version %d", from))
+		return nil
 	}
 
-	if !needsMigration {
+	if len(pending) > 0 {
+		fmt.Println()
+		messenger.Warning(fmt.Sprintf("Configuration needs migration (schema v%d → v%d)", from, configpkg.CurrentSchemaVersion))
+		for _, step := range pending {
+			fmt.Printf("  %s\n", step)
+		}
 		fmt.Println()
-		messenger.Success("Configuration is already in the correct format")
-		return nil
 	}
 
-	fmt.Println()
-	messenger.Warning("Configuration needs migration")
-	fmt.Println()
-
-	// 更新配置
-	cfg.Versions = migratedVersions
-	cfg.ActiveVersion = newActiveVersion
+	// 加载配置会在配置锁内执行迁移，较新的 schema 在这里被拒绝
+	if _, err := ctx.ConfigStore.Load(); err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
 
-	// 保存配置
-	messenger.Info("Saving migrated configuration...")
-	if err := ctx.ConfigStore.Save(cfg); err != nil {
-		errorFormatter.Format(fmt.Errorf("failed to save config: %w", err))
+	configPath, err := configpkg.GetConfigFilePath()
+	if err != nil {
 		return err
 	}
 
+	messenger.Success(fmt.Sprintf("Configuration migrated to schema version %d", configpkg.CurrentSchemaVersion))
 	fmt.Println()
-	messenger.Success("Configuration migrated successfully!")
-	fmt.Println()
-	messenger.Info("You can now use gx commands normally:")
-	fmt.Println("  gx list")
-	fmt.Println("  gx current")
-	fmt.Println("  gx use <version>")
+	messenger.Info("Backups of the previous versions:")
+	for v := from; v < configpkg.CurrentSchemaVersion; v++ {
+		fmt.Printf("  %s\n", configpkg.SchemaBackupPath(configPath, v))
+	}
 
 	logger.Info("Config migration completed successfully")
 	return nil
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+var (
+	pruneKeepPatches int
+	pruneKeepPinned  []string
+	pruneUnusedFor   string
+	pruneDryRun      bool
+	pruneForce       bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old Go versions according to retention policies",
+	Long: `Remove Go versions installed by gx that no retention policy keeps.
+
+Policies (at least --keep-patches or --unused-for is required):
+  --keep-patches N    keep the newest N patch releases of every minor line
+  --unused-for AGE    keep versions used within AGE (e.g. 30d, 12h)
+  --keep-pinned DIR   keep versions pinned by .go-version files under DIR
+
+A version is removed only if no policy keeps it. The active version, linked
+versions (gx adopt) and versions referenced by aliases are always kept.
+Last-used times are recorded when a version is selected with 'gx use' or runs
+through the go shim or gx wrappers.
+
+Example:
+  gx prune --keep-patches 2 --dry-run
+  gx prune --unused-for 90d
+  gx prune --keep-patches 1 --unused-for 30d --keep-pinned ~/src`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().IntVar(&pruneKeepPatches, "keep-patches", 0, "keep the newest N patches of each minor version")
+	pruneCmd.Flags().StringArrayVar(&pruneKeepPinned, "keep-pinned", nil, "keep versions pinned by .go-version files under this directory (repeatable)")
+	pruneCmd.Flags().StringVar(&pruneUnusedFor, "unused-for", "", "keep versions used within this period (e.g. 30d, 12h)")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "show what would be removed without removing anything")
+	pruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "skip confirmation prompt")
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	prompter := ui.NewPrompter(os.Stdin, os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	policy := interfaces.PrunePolicy{
+		KeepPatches:    pruneKeepPatches,
+		KeepPinnedDirs: pruneKeepPinned,
+	}
+	if pruneUnusedFor != "" {
+		policy.UnusedFor, err = parseAge(pruneUnusedFor)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 先计算清理计划，确认后再真正删除
+	plan, err := ctx.VersionManager.Prune(policy, true)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	printPrunePlan(messenger, plan)
+
+	if len(plan.Removed) == 0 {
+		messenger.Success("Nothing to prune")
+		return nil
+	}
+
+	if pruneDryRun {
+		messenger.Info(fmt.Sprintf("Dry run: %d versions would be removed, freeing %s", len(plan.Removed), ui.FormatBytes(plan.FreedBytes)))
+		return nil
+	}
+
+	if !pruneForce {
+		confirmed, err := prompter.Confirm(fmt.Sprintf("Remove %d Go versions?", len(plan.Removed)), false)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			messenger.Info("Prune cancelled")
+			return nil
+		}
+	}
+
+	result, err := ctx.VersionManager.Prune(policy, false)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	for _, item := range result.Removed {
+		messenger.Success(fmt.Sprintf("Removed Go %s", goversion.Display(item.Version)))
+	}
+	for _, item := range result.Failed {
+		messenger.Error(fmt.Sprintf("Failed to remove Go %s: %s", goversion.Display(item.Version), item.Reason))
+	}
+
+	fmt.Println()
+	messenger.Success(fmt.Sprintf("Freed %s", ui.FormatBytes(result.FreedBytes)))
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("failed to remove %d versions", len(result.Failed))
+	}
+	return nil
+}
+
+// printPrunePlan 显示将删除和保留的版本
+func printPrunePlan(messenger *ui.Messenger, plan *interfaces.PruneResult) {
+	if len(plan.Removed) > 0 {
+		messenger.Section("Versions to remove")
+		fmt.Println()
+
+		rows := make([][]string, len(plan.Removed))
+		for i, item := range plan.Removed {
+			rows[i] = []string{goversion.Display(item.Version), ui.FormatBytes(item.Size), formatLastUsed(item.LastUsed), item.Reason}
+		}
+		messenger.Table([]string{"Version", "Size", "Last Used", "Reason"}, rows)
+		fmt.Println()
+	}
+
+	if len(plan.Kept) > 0 {
+		messenger.Section("Versions to keep")
+		fmt.Println()
+
+		rows := make([][]string, len(plan.Kept))
+		for i, item := range plan.Kept {
+			rows[i] = []string{goversion.Display(item.Version), formatLastUsed(item.LastUsed), item.Reason}
+		}
+		messenger.Table([]string{"Version", "Last Used", "Reason"}, rows)
+		fmt.Println()
+	}
+}
+
+// formatLastUsed 格式化最近使用时间
+func formatLastUsed(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
+
+// parseAge 解析时长，除 time.ParseDuration 支持的格式外还支持以天为单位（如 30d）
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid duration %q: use a positive value such as 30d or 12h", value)
+}
@@ -5,8 +5,10 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/gxhome"
 	"github.com/kawaiirei0/gx/internal/logger"
 	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
 )
 
 var (
@@ -25,7 +27,11 @@ var rootCmd = &cobra.Command{
 	Use:   constants.AppName,
 	Short: "Go version manager and development tool",
 	Long: `gx is a cross-platform Go version manager and development tool.
-It simplifies Go environment installation, version switching, and cross-platform compilation.`,
+It simplifies Go environment installation, version switching, and cross-platform compilation.
+
+All state (config, versions, shims, logs) lives under $GX_HOME, which
+defaults to ~/.gx. --config selects another config file; unless GX_HOME is
+set, the directory containing it becomes the gx home.`,
 	Version: constants.AppVersion,
 }
 
@@ -40,18 +46,15 @@ func SetVersionInfo(v, c, bd string) {
 
 // Execute 执行根命令
 func Execute() {
-	// 初始化日志记录器
-	if err := logger.Init(); err != nil {
-		// 日志初始化失败不影响程序运行，只打印警告
-		fmt.Fprintf(os.Stderr, "Warning: failed to initialize logger: %v\n", err)
-	}
+	// 日志记录器在解析完 --config 之后初始化，见 initRoot
 	defer logger.Close()
 
-	logger.Info("gx %s started", appVersion)
-	
 	if err := rootCmd.Execute(); err != nil {
 		logger.Error("Command execution failed: %v", err)
 		fmt.Fprintln(os.Stderr, err)
+		if errors.IsType(err, errors.ErrCancelled) {
+			os.Exit(interruptExitCode)
+		}
 		os.Exit(1)
 	}
 	
@@ -61,13 +64,35 @@ func Execute() {
 func init() {
 	// 全局标志
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().StringVar(&config, "config", "", "config file (default is $HOME/.gx/config.json)")
+	rootCmd.PersistentFlags().StringVar(&config, "config", "", "config file (default is $GX_HOME/config.json)")
 	
-	// 设置 PersistentPreRun 来处理 verbose 标志
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
-		if verbose {
-			logger.SetLevel(logger.LevelDebug)
-			logger.Debug("Verbose mode enabled")
+	rootCmd.PersistentPreRunE = initRoot
+}
+
+// initRoot 在执行任何子命令之前应用全局标志
+func initRoot(cmd *cobra.Command, args []string) error {
+	// 所有子系统都通过 gxhome 解析路径，必须在初始化日志和加载配置之前设置
+	if config != "" {
+		if err := gxhome.SetConfigFile(config); err != nil {
+			return fmt.Errorf("invalid --config path: %w", err)
 		}
+		// 让子进程（gx exec、gx shell、shims）使用同一个配置
+		if err := gxhome.Export(); err != nil {
+			return fmt.Errorf("failed to export %s: %w", constants.EnvGxHome, err)
+		}
+	}
+
+	// 初始化日志记录器
+	if err := logger.Init(); err != nil {
+		// 日志初始化失败不影响程序运行，只打印警告
+		fmt.Fprintf(os.Stderr, "Warning: failed to initialize logger: %v\n", err)
+	}
+	logger.Info("gx %s started", appVersion)
+
+	// 处理 verbose 标志
+	if verbose {
+		logger.SetLevel(logger.LevelDebug)
+		logger.Debug("Verbose mode enabled")
 	}
+	return nil
 }
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/environment"
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/constants"
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell <version>",
+	Short: "Start a subshell that uses a specific Go version",
+	Long: `Start a new interactive shell with GOROOT, PATH and GX_VERSION set to the
+given Go version. Exit the subshell to return to the previous environment.
+
+Example:
+  gx shell 1.22.0`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShell,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
+
+func runShell(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stderr)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	vars, err := sessionEnvFor(ctx, args)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	shellPath := userShell()
+	logger.Info("Starting subshell %s for Go %s", shellPath, vars[constants.EnvGxVersion])
+	messenger.Info(fmt.Sprintf("Starting %s with Go %s (exit to return)", shellPath, vars[constants.EnvGxVersion]))
+
+	sub := exec.Command(shellPath)
+	sub.Env = environment.MergeEnv(os.Environ(), vars)
+	sub.Stdin = os.Stdin
+	sub.Stdout = os.Stdout
+	sub.Stderr = os.Stderr
+
+	if err := sub.Run(); err != nil {
+		// 保留子 shell 的退出码
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		errorFormatter.Format(err)
+		return err
+	}
+
+	return nil
+}
+
+// userShell 返回用户的交互式 shell
+func userShell() string {
+	if runtime.GOOS == "windows" {
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return comspec
+		}
+		return "cmd.exe"
+	}
+
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/shim"
+	"github.com/kawaiirei0/gx/internal/ui"
+)
+
+var shimCmd = &cobra.Command{
+	Use:   "shim",
+	Short: "Manage go/gofmt shims",
+	Long: `Manage the shim directory (~/.gx/shims).
+The shims resolve the Go version for the current directory on every call,
+so IDEs and already-open terminals pick up version changes immediately.
+
+Example:
+  gx shim rehash`,
+}
+
+var shimRehashCmd = &cobra.Command{
+	Use:   "rehash",
+	Short: "Regenerate the go/gofmt shims",
+	Args:  cobra.NoArgs,
+	RunE:  runShimRehash,
+}
+
+var shimExecCmd = &cobra.Command{
+	Use:                "exec <tool> [arguments...]",
+	Short:              "Run a tool through the shim resolver",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Args:               cobra.MinimumNArgs(1),
+	RunE:               runShimExec,
+}
+
+func init() {
+	rootCmd.AddCommand(shimCmd)
+	shimCmd.AddCommand(shimRehashCmd)
+	shimCmd.AddCommand(shimExecCmd)
+}
+
+func runShimRehash(cmd *cobra.Command, args []string) error {
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	exePath, err := os.Executable()
+	if err != nil {
+		errorFormatter.Format(fmt.Errorf("failed to get executable path: %w", err))
+		return err
+	}
+
+	exePath, err = filepath.EvalSymlinks(exePath)
+	if err != nil {
+		errorFormatter.Format(fmt.Errorf("failed to resolve executable path: %w", err))
+		return err
+	}
+
+	shimDir, err := shim.Rehash(exePath)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	messenger.Success(fmt.Sprintf("Shims regenerated in %s", shimDir))
+	fmt.Println()
+	messenger.Info("Make sure the shim directory comes first in your PATH:")
+	if runtime.GOOS == "windows" {
+		fmt.Printf("  setx PATH \"%s;%%PATH%%\"\n", shimDir)
+	} else {
+		fmt.Printf("  export PATH=\"%s:$PATH\"\n", shimDir)
+	}
+
+	return nil
+}
+
+func runShimExec(cmd *cobra.Command, args []string) error {
+	tool, ok := shim.ToolFromArgv0(args[0])
+	if !ok {
+		return fmt.Errorf("unknown shim tool: %s", args[0])
+	}
+
+	os.Exit(shim.Main(tool, args[1:]))
+	return nil
+}
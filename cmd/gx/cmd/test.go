@@ -2,23 +2,43 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/ui"
 	"github.com/kawaiirei0/gx/internal/wrapper"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
 )
 
+// defaultTestJobs 同时运行的版本数默认值
+// go test 本身已经并行编译和测试各个包，同时运行过多版本只会互相争抢 CPU
+const defaultTestJobs = 2
+
 var testCmd = &cobra.Command{
 	Use:   "test [flags] [packages]",
 	Short: "Test packages",
 	Long: `Test packages using the active Go version.
 This is a wrapper around 'go test' command.
 
+Use --go to run the tests once per listed version (in parallel, at most
+--go-jobs at a time). Output lines are prefixed with the version, and a
+pass/fail matrix per package is printed at the end. Versions accept the same
+specifiers as 'gx use' (1.22, latest, aliases, ...).
+
 Example:
   gx test
   gx test ./...
   gx test -v
-  gx test -cover ./...`,
+  gx test -cover ./...
+  gx test --go 1.21,1.22,1.23 ./...
+  gx test --go 1.21,1.22 --go-jobs 1 -race ./...`,
 	DisableFlagParsing: true, // 禁用标志解析，让所有参数传递给 go test
 	RunE:               runTest,
 }
@@ -33,6 +53,14 @@ func runTest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize: %w", err)
 	}
 
+	specs, jobs, goArgs, err := parseMatrixFlags(args)
+	if err != nil {
+		return err
+	}
+	if len(specs) > 0 {
+		return runTestMatrix(ctx, specs, jobs, goArgs)
+	}
+
 	// 执行 go test 命令
 	err = ctx.CLIWrapper.Execute("test", args)
 	if err != nil {
@@ -46,3 +74,240 @@ func runTest(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseMatrixFlags 从参数中取出 gx 自己的 --go 和 --go-jobs 标志，其余参数原样交给 go test
+func parseMatrixFlags(args []string) (specs []string, jobs int, rest []string, err error) {
+	jobs = defaultTestJobs
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// "--" 之后的参数全部属于 go test（如 -args 传给测试二进制的参数）
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "--go" && name != "--go-jobs" {
+			rest = append(rest, arg)
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, 0, nil, fmt.Errorf("flag %s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "--go":
+			for _, spec := range strings.Split(value, ",") {
+				if spec = strings.TrimSpace(spec); spec != "" {
+					specs = append(specs, spec)
+				}
+			}
+		case "--go-jobs":
+			jobs, err = strconv.Atoi(value)
+			if err != nil || jobs < 1 {
+				return nil, 0, nil, fmt.Errorf("invalid --go-jobs value %q: must be a positive integer", value)
+			}
+		}
+	}
+
+	return specs, jobs, rest, nil
+}
+
+// testRun 记录单个版本的测试结果
+type testRun struct {
+	version  *interfaces.GoVersion
+	err      error
+	exitCode int
+	elapsed  time.Duration
+
+	mu       sync.Mutex
+	packages map[string]string // 包路径 -> ok / FAIL / ?
+}
+
+// recordLine 从 go test 输出中识别包级结果行
+// 例如 "ok  \tpkg\t0.01s"、"FAIL\tpkg [build failed]"、"?   \tpkg\t[no test files]"
+func (r *testRun) recordLine(line string) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < 2 {
+		return
+	}
+
+	status := strings.TrimSpace(fields[0])
+	if status != "ok" && status != "FAIL" && status != "?" {
+		return
+	}
+
+	pkg := fields[1]
+	if idx := strings.Index(pkg, " ["); idx >= 0 {
+		pkg = pkg[:idx]
+	}
+
+	r.mu.Lock()
+	r.packages[pkg] = status
+	r.mu.Unlock()
+}
+
+// runTestMatrix 对每个版本运行一次 go test 并输出矩阵
+func runTestMatrix(ctx *AppContext, specs []string, jobs int, goArgs []string) error {
+	messenger := ui.NewMessenger(os.Stderr)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	// 先解析全部版本，避免运行到一半才发现某个版本未安装
+	var runs []*testRun
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		target, err := lookupInstalledVersion(ctx, spec)
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		if seen[target.Version] {
+			continue
+		}
+		seen[target.Version] = true
+		runs = append(runs, &testRun{version: target, packages: make(map[string]string)})
+	}
+
+	messenger.Info(fmt.Sprintf("Testing with %d Go versions (%d at a time)", len(runs), jobs))
+
+	// 所有版本的输出共享同一组 writer，OutputStreamer 按整行写入，各行不会相互交错
+	stdout := ui.NewSyncWriter(os.Stdout)
+	stderr := ui.NewSyncWriter(os.Stderr)
+	width := 0
+	for _, r := range runs {
+		if l := len(goversion.Display(r.version.Version)); l > width {
+			width = l
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, jobs)
+	for _, r := range runs {
+		wg.Add(1)
+		go func(r *testRun) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			prefix := fmt.Sprintf("[%-*s] ", width, goversion.Display(r.version.Version))
+			runTestForVersion(ctx, r, goArgs, prefix, stdout, stderr)
+		}(r)
+	}
+	wg.Wait()
+
+	return printTestMatrix(messenger, runs)
+}
+
+// runTestForVersion 使用指定版本运行 go test，输出按行加上版本前缀写入 stdout 和 stderr
+func runTestForVersion(ctx *AppContext, r *testRun, goArgs []string, prefix string, stdout, stderr io.Writer) {
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderrReader, stderrWriter := io.Pipe()
+
+	var streams sync.WaitGroup
+	streams.Add(2)
+	go func() {
+		defer streams.Done()
+		ui.NewOutputStreamer(stdoutReader, stdout, prefix).StreamWithCallback(r.recordLine)
+		io.Copy(io.Discard, stdoutReader)
+	}()
+	go func() {
+		defer streams.Done()
+		ui.NewOutputStreamer(stderrReader, stderr, prefix).Stream()
+		io.Copy(io.Discard, stderrReader)
+	}()
+
+	start := time.Now()
+	err := ctx.CLIWrapper.ExecuteWithVersion(r.version, "test", goArgs, stdoutWriter, stderrWriter)
+	r.elapsed = time.Since(start)
+
+	stdoutWriter.Close()
+	stderrWriter.Close()
+	streams.Wait()
+
+	r.err = err
+	if exitErr, ok := wrapper.IsExitError(err); ok {
+		r.exitCode = exitErr.GetExitCode()
+	} else if err != nil {
+		r.exitCode = 1
+		fmt.Fprintf(stderr, "%s%v\n", prefix, err)
+	}
+}
+
+// printTestMatrix 输出包 × 版本的结果矩阵，有失败时以非零状态退出
+func printTestMatrix(messenger *ui.Messenger, runs []*testRun) error {
+	messenger.Section("Test Matrix")
+	fmt.Fprintln(os.Stderr)
+
+	pkgSet := make(map[string]bool)
+	for _, r := range runs {
+		for pkg := range r.packages {
+			pkgSet[pkg] = true
+		}
+	}
+	packages := make([]string, 0, len(pkgSet))
+	for pkg := range pkgSet {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	headers := []string{"Package"}
+	for _, r := range runs {
+		headers = append(headers, goversion.Display(r.version.Version))
+	}
+
+	rows := make([][]string, 0, len(packages)+2)
+	for _, pkg := range packages {
+		row := []string{pkg}
+		for _, r := range runs {
+			row = append(row, packageCell(r.packages[pkg]))
+		}
+		rows = append(rows, row)
+	}
+
+	// 汇总行：整体结果和耗时
+	result := []string{"(result)"}
+	elapsed := []string{"(time)"}
+	failed := 0
+	for _, r := range runs {
+		if r.err != nil {
+			failed++
+			result = append(result, fmt.Sprintf("FAIL (exit %d)", r.exitCode))
+		} else {
+			result = append(result, "PASS")
+		}
+		elapsed = append(elapsed, r.elapsed.Round(time.Millisecond).String())
+	}
+	rows = append(rows, result, elapsed)
+
+	messenger.Table(headers, rows)
+	fmt.Fprintln(os.Stderr)
+
+	if failed > 0 {
+		messenger.Error(fmt.Sprintf("%d of %d Go versions failed", failed, len(runs)))
+		os.Exit(1)
+	}
+
+	messenger.Success(fmt.Sprintf("All %d Go versions passed", len(runs)))
+	return nil
+}
+
+// packageCell 将 go test 的包状态转换为矩阵单元格
+func packageCell(status string) string {
+	switch status {
+	case "ok":
+		return "pass"
+	case "FAIL":
+		return "FAIL"
+	case "?":
+		return "no tests"
+	default:
+		return "-"
+	}
+}
@@ -3,10 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/goversion"
 )
 
 var (
@@ -18,6 +18,8 @@ var uninstallCmd = &cobra.Command{
 	Short: "Uninstall a specific Go version",
 	Long: `Uninstall a specific Go version managed by gx.
 Cannot uninstall the currently active version.
+Versions registered with 'gx adopt' are only unregistered; their files are
+left untouched.
 
 Example:
   gx uninstall 1.21.5
@@ -43,15 +45,25 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	errorFormatter := ui.NewErrorFormatter(os.Stderr)
 
 	version := args[0]
-	// 规范化版本号
-	if !strings.HasPrefix(version, "go") {
-		version = "go" + version
+	// 规范化版本号；源码构建的版本（如 tip）以名称注册，保持原样
+	if goversion.IsValid(version) {
+		version = goversion.Normalize(version)
+	}
+
+	// 链接版本只取消注册，不删除文件
+	linked := false
+	if cfg, err := ctx.ConfigStore.Load(); err == nil {
+		linked = cfg.Linked[version]
+	}
+	action := "uninstall"
+	if linked {
+		action = "unregister"
 	}
 
 	// 确认卸载（除非使用 --force）
 	if !uninstallForce {
 		confirmed, err := prompter.Confirm(
-			fmt.Sprintf("Are you sure you want to uninstall Go %s?", strings.TrimPrefix(version, "go")),
+			fmt.Sprintf("Are you sure you want to %s Go %s?", action, goversion.Display(version)),
 			false,
 		)
 		if err != nil {
@@ -63,7 +75,7 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	messenger.Info(fmt.Sprintf("Uninstalling Go %s...", strings.TrimPrefix(version, "go")))
+	messenger.Info(fmt.Sprintf("Uninstalling Go %s...", goversion.Display(version)))
 
 	err = ctx.VersionManager.Uninstall(version)
 	if err != nil {
@@ -71,7 +83,12 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	messenger.Success(fmt.Sprintf("Go %s uninstalled successfully", strings.TrimPrefix(version, "go")))
+	if linked {
+		messenger.Success(fmt.Sprintf("Go %s unregistered (files were left in place)", goversion.Display(version)))
+		return nil
+	}
+
+	messenger.Success(fmt.Sprintf("Go %s uninstalled successfully", goversion.Display(version)))
 
 	return nil
 }
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/settings"
 	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/goversion"
 )
 
 var (
@@ -27,7 +29,7 @@ Example:
 
 func init() {
 	rootCmd.AddCommand(updateCmd)
-	updateCmd.Flags().BoolVarP(&autoSwitch, "switch", "s", false, "automatically switch to the new version after installation")
+	updateCmd.Flags().BoolVarP(&autoSwitch, "switch", "s", false, "automatically switch to the new version after installation (default: update.switch setting)")
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
@@ -40,15 +42,25 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	prompter := ui.NewPrompter(os.Stdin, os.Stdout)
 	errorFormatter := ui.NewErrorFormatter(os.Stderr)
 
+	// 未指定 --switch 时使用配置项 update.switch
+	if !cmd.Flags().Changed("switch") {
+		effective, err := ctx.Settings()
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		autoSwitch = settings.Bool(effective, constants.SettingUpdateSwitch)
+	}
+
 	messenger.Info("Checking for the latest Go version...")
 
-	latest, err := ctx.VersionManager.GetLatest()
+	latest, err := ctx.VersionManager.GetLatest(cmd.Context())
 	if err != nil {
 		errorFormatter.Format(err)
 		return err
 	}
 
-	messenger.Info(fmt.Sprintf("Latest version: %s", strings.TrimPrefix(latest, "go")))
+	messenger.Info(fmt.Sprintf("Latest version: %s", goversion.Display(latest)))
 
 	// 检查是否已安装
 	versions, err := ctx.VersionManager.DetectInstalled()
@@ -68,17 +80,17 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	if alreadyInstalled && isActive {
-		messenger.Success(fmt.Sprintf("You are already using the latest version (%s)", strings.TrimPrefix(latest, "go")))
+		messenger.Success(fmt.Sprintf("You are already using the latest version (%s)", goversion.Display(latest)))
 		return nil
 	}
 
 	if alreadyInstalled {
-		messenger.Success(fmt.Sprintf("Latest version (%s) is already installed", strings.TrimPrefix(latest, "go")))
+		messenger.Success(fmt.Sprintf("Latest version (%s) is already installed", goversion.Display(latest)))
 
 		// 询问是否切换
 		if !autoSwitch {
 			confirmed, err := prompter.Confirm(
-				fmt.Sprintf("Switch to Go %s now?", strings.TrimPrefix(latest, "go")),
+				fmt.Sprintf("Switch to Go %s now?", goversion.Display(latest)),
 				true,
 			)
 			if err != nil {
@@ -88,22 +100,22 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		}
 
 		if autoSwitch {
-			messenger.Info(fmt.Sprintf("Switching to %s...", strings.TrimPrefix(latest, "go")))
+			messenger.Info(fmt.Sprintf("Switching to %s...", goversion.Display(latest)))
 			err = ctx.VersionManager.SwitchTo(latest)
 			if err != nil {
 				errorFormatter.Format(err)
 				return err
 			}
-			messenger.Success(fmt.Sprintf("Now using Go %s", strings.TrimPrefix(latest, "go")))
+			messenger.Success(fmt.Sprintf("Now using Go %s", goversion.Display(latest)))
 		} else {
 			fmt.Println()
 			messenger.Info("To use this version later, run:")
-			fmt.Printf("  gx use %s\n", strings.TrimPrefix(latest, "go"))
+			fmt.Printf("  gx use %s\n", goversion.Display(latest))
 		}
 		return nil
 	}
 
-	messenger.Info(fmt.Sprintf("Installing Go %s...", strings.TrimPrefix(latest, "go")))
+	messenger.Info(fmt.Sprintf("Installing Go %s...", goversion.Display(latest)))
 
 	// 创建进度条
 	var progressBar *ui.ProgressBar
@@ -118,8 +130,10 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// 执行安装
-	err = ctx.VersionManager.Install(latest, progressCallback)
+	// 执行安装，Ctrl-C 时中止并回滚
+	runCtx, cancel := withInterrupt(cmd.Context())
+	defer cancel()
+	err = ctx.VersionManager.Install(runCtx, latest, progressCallback)
 	if err != nil {
 		if progressBar != nil {
 			fmt.Println() // 换行
@@ -133,13 +147,13 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		progressBar.Finish()
 	}
 
-	messenger.Success(fmt.Sprintf("Go %s installed successfully", strings.TrimPrefix(latest, "go")))
+	messenger.Success(fmt.Sprintf("Go %s installed successfully", goversion.Display(latest)))
 
 	// 询问是否切换
 	if !autoSwitch {
 		fmt.Println()
 		confirmed, err := prompter.Confirm(
-			fmt.Sprintf("Switch to Go %s now?", strings.TrimPrefix(latest, "go")),
+			fmt.Sprintf("Switch to Go %s now?", goversion.Display(latest)),
 			true,
 		)
 		if err != nil {
@@ -150,17 +164,17 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	// 如果设置了自动切换标志，切换到新版本
 	if autoSwitch {
-		messenger.Info(fmt.Sprintf("Switching to %s...", strings.TrimPrefix(latest, "go")))
+		messenger.Info(fmt.Sprintf("Switching to %s...", goversion.Display(latest)))
 		err = ctx.VersionManager.SwitchTo(latest)
 		if err != nil {
 			errorFormatter.Format(err)
 			return err
 		}
-		messenger.Success(fmt.Sprintf("Now using Go %s", strings.TrimPrefix(latest, "go")))
+		messenger.Success(fmt.Sprintf("Now using Go %s", goversion.Display(latest)))
 	} else {
 		fmt.Println()
 		messenger.Info("To use this version, run:")
-		fmt.Printf("  gx use %s\n", strings.TrimPrefix(latest, "go"))
+		fmt.Printf("  gx use %s\n", goversion.Display(latest))
 	}
 
 	return nil
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/settings"
+	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/internal/version"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+var (
+	upgradeRemoveOld bool
+	upgradePins      []string
+	upgradeDryRun    bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade every installed minor version to its latest patch",
+	Long: `Install the newest patch release for every installed minor line
+(1.21.x, 1.22.x, ...). Unlike 'gx update', which only installs the single
+newest Go release, this keeps each line you use current.
+
+After a line is upgraded, references to its older patches are moved to the
+new one: the active version, aliases, and either the .go-version file that
+applies to the current directory or, with --pins, every .go-version file
+found under the given directories. References to a line (1.21, 1.21.x) or
+keyword follow automatically and are left unchanged.
+
+With --remove-old, .go-version files outside the updated ones that pin an
+old patch exactly stop resolving once it is removed; pass --pins to cover
+your projects.
+
+Example:
+  gx upgrade
+  gx upgrade --dry-run
+  gx upgrade --remove-old
+  gx upgrade --pins ~/src --pins ~/work`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().BoolVar(&upgradeRemoveOld, "remove-old", false, "uninstall the superseded patch versions (default: upgrade.remove-old setting)")
+	upgradeCmd.Flags().StringArrayVar(&upgradePins, "pins", nil, "directory to search recursively for .go-version files to update (repeatable, default: only the .go-version file of the current directory)")
+	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "show the upgrade plan without installing anything")
+}
+
+// upgradeOutcome 记录单个版本线的升级结果
+type upgradeOutcome struct {
+	plan    interfaces.UpgradePlan
+	status  string
+	refs    []string
+	removed []string
+	failed  bool
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	ctx, err := NewAppContext()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	messenger := ui.NewMessenger(os.Stdout)
+	errorFormatter := ui.NewErrorFormatter(os.Stderr)
+
+	// 未指定 --remove-old 时使用配置项 upgrade.remove-old
+	if !cmd.Flags().Changed("remove-old") {
+		effective, err := ctx.Settings()
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		upgradeRemoveOld = settings.Bool(effective, constants.SettingUpgradeRemoveOld)
+	}
+
+	// 未指定 --pins 时只更新当前目录生效的 .go-version 文件，不递归扫描
+	pins := upgradePins
+	pinScope := strings.Join(upgradePins, ", ")
+	if len(pins) == 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		versionFile, err := version.FindVersionFile(wd)
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
+		}
+		if versionFile != "" {
+			pins = []string{versionFile}
+		}
+		pinScope = "the .go-version file of the current directory"
+	}
+
+	messenger.Info("Checking for newer patch releases...")
+
+	runCtx, cancel := withInterrupt(cmd.Context())
+	defer cancel()
+
+	plans, err := ctx.VersionManager.PlanUpgrade(runCtx)
+	if err != nil {
+		errorFormatter.Format(err)
+		return err
+	}
+
+	if len(plans) == 0 {
+		messenger.Warning("No Go versions installed by gx")
+		return nil
+	}
+
+	// 范围之外固定到旧补丁的 .go-version 文件无法迁移，删除旧补丁后会无法解析
+	if upgradeRemoveOld {
+		var removing []string
+		for _, plan := range plans {
+			for _, old := range plan.Installed {
+				if !plan.UpToDate() && old != plan.To {
+					removing = append(removing, goversion.Display(old))
+				}
+			}
+		}
+		if len(removing) > 0 {
+			messenger.Warning(fmt.Sprintf("--remove-old will uninstall Go %s; only %s is updated, so .go-version files elsewhere that pin these versions will stop resolving (use --pins to include them)", strings.Join(removing, ", "), pinScope))
+		}
+	}
+
+	outcomes := make([]upgradeOutcome, 0, len(plans))
+	for _, plan := range plans {
+		outcome := upgradeOutcome{plan: plan}
+
+		switch {
+		case plan.UpToDate():
+			outcome.status = "up to date"
+		case upgradeDryRun:
+			outcome.status = "would upgrade"
+		default:
+			upgradeLine(runCtx, ctx, messenger, errorFormatter, &outcome, pins)
+		}
+
+		outcomes = append(outcomes, outcome)
+
+		// 被中断时不再升级剩余的版本线
+		if runCtx.Err() != nil {
+			break
+		}
+	}
+
+	printUpgradeSummary(messenger, outcomes)
+
+	if runCtx.Err() != nil {
+		return errors.ErrCancelled.WithMessage(fmt.Sprintf("upgrade was interrupted after %d of %d lines", len(outcomes), len(plans)))
+	}
+
+	failed := 0
+	for _, o := range outcomes {
+		if o.failed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to upgrade %d of %d lines", failed, len(outcomes))
+	}
+	return nil
+}
+
+// upgradeLine 安装版本线的最新补丁并迁移引用，可选地删除旧补丁
+func upgradeLine(runCtx context.Context, ctx *AppContext, messenger *ui.Messenger, errorFormatter *ui.ErrorFormatter, outcome *upgradeOutcome, pins []string) {
+	plan := outcome.plan
+	fmt.Println()
+	messenger.Info(fmt.Sprintf("Upgrading Go %s: %s -> %s", goversion.Display(plan.Line), goversion.Display(plan.From), goversion.Display(plan.To)))
+
+	// 创建进度条
+	var progressBar *ui.ProgressBar
+
+	// 创建进度回调
+	progressCallback := func(downloaded, total int64) {
+		if progressBar == nil && total > 0 {
+			progressBar = ui.NewProgressBar(os.Stdout, total, "Downloading")
+		}
+		if progressBar != nil {
+			progressBar.Update(downloaded)
+		}
+	}
+
+	if err := ctx.VersionManager.Install(runCtx, plan.To, progressCallback); err != nil {
+		if progressBar != nil {
+			fmt.Println() // 换行
+		}
+		errorFormatter.Format(err)
+		outcome.status = "install failed"
+		outcome.failed = true
+		return
+	}
+
+	// 完成进度条
+	if progressBar != nil {
+		progressBar.Finish()
+	}
+
+	result, err := ctx.VersionManager.Repoint(plan.Installed, plan.To, pins)
+	if result != nil {
+		if result.Active {
+			outcome.refs = append(outcome.refs, "active")
+		}
+		for _, name := range result.Aliases {
+			outcome.refs = append(outcome.refs, "alias "+name)
+		}
+		for _, pin := range result.Pins {
+			messenger.Info(fmt.Sprintf("  Updated %s", pin))
+		}
+		if len(result.Pins) > 0 {
+			outcome.refs = append(outcome.refs, fmt.Sprintf("%d .go-version", len(result.Pins)))
+		}
+	}
+	if err != nil {
+		errorFormatter.Format(err)
+		outcome.status = "installed, repoint failed"
+		outcome.failed = true
+		return
+	}
+
+	outcome.status = "upgraded"
+
+	if !upgradeRemoveOld {
+		return
+	}
+
+	// 引用已迁移，旧补丁不再是激活版本，可以安全卸载
+	for _, old := range plan.Installed {
+		if old == plan.To {
+			continue
+		}
+		if err := ctx.VersionManager.Uninstall(old); err != nil {
+			messenger.Warning(fmt.Sprintf("Failed to remove Go %s: %v", goversion.Display(old), err))
+			outcome.failed = true
+			continue
+		}
+		outcome.removed = append(outcome.removed, goversion.Display(old))
+	}
+}
+
+// printUpgradeSummary 输出每个版本线的升级结果
+func printUpgradeSummary(messenger *ui.Messenger, outcomes []upgradeOutcome) {
+	messenger.Section("Upgrade Summary")
+	fmt.Println()
+
+	headers := []string{"Line", "From", "To", "Status", "Updated", "Removed"}
+	rows := make([][]string, len(outcomes))
+	for i, o := range outcomes {
+		rows[i] = []string{
+			goversion.Display(o.plan.Line),
+			goversion.Display(o.plan.From),
+			goversion.Display(o.plan.To),
+			o.status,
+			joinOrDash(o.refs),
+			joinOrDash(o.removed),
+		}
+	}
+	messenger.Table(headers, rows)
+	fmt.Println()
+}
+
+// joinOrDash 用逗号连接列表，空列表显示为 "-"
+func joinOrDash(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	return strings.Join(items, ", ")
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"runtime"
-	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/kawaiirei0/gx/internal/gxhome"
 	"github.com/kawaiirei0/gx/internal/ui"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/goversion"
 )
 
 var (
@@ -18,11 +20,15 @@ var useCmd = &cobra.Command{
 	Use:   "use [version]",
 	Short: "Switch to a specific Go version",
 	Long: `Switch to a specific Go version that has been installed.
-This updates the environment variables to point to the selected version.
+This atomically repoints ~/.gx/current at the selected version; shell
+configuration files reference ~/.gx/current once and never need rewriting again.
 
 Example:
   gx use 1.21.5
   gx use go1.21.5
+  gx use 1.22       # newest installed 1.22.x
+  gx use oldstable  # newest installed patch of the previous release line
+  gx use prod       # an alias created with 'gx alias'
   gx use -i         # interactive version selection`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runUse,
@@ -77,14 +83,15 @@ func runUse(cmd *cobra.Command, args []string) error {
 
 		version = versions[selected].Version
 	} else {
-		version = args[0]
-		// 规范化版本号
-		if !strings.HasPrefix(version, "go") {
-			version = "go" + version
+		// 将版本说明符（1.22、latest、别名等）解析为已安装的具体版本
+		version, err = ctx.VersionManager.FindInstalled(args[0])
+		if err != nil {
+			errorFormatter.Format(err)
+			return err
 		}
 	}
 
-	messenger.Info(fmt.Sprintf("Switching to Go %s...", strings.TrimPrefix(version, "go")))
+	messenger.Info(fmt.Sprintf("Switching to Go %s...", goversion.Display(version)))
 
 	err = ctx.VersionManager.SwitchTo(version)
 	if err != nil {
@@ -92,7 +99,8 @@ func runUse(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	messenger.Success(fmt.Sprintf("Now using Go %s", strings.TrimPrefix(version, "go")))
+	messenger.Success(fmt.Sprintf("Now using Go %s", goversion.Display(version)))
+	warnIfOverridden(ctx, messenger, version)
 	fmt.Println()
 
 	// 根据操作系统提供不同的提示
@@ -100,10 +108,11 @@ func runUse(cmd *cobra.Command, args []string) error {
 		messenger.Info("Note: You may need to restart your terminal or command prompt")
 		messenger.Info("for the environment changes to take effect.")
 	} else {
-		messenger.Info("Note: You may need to restart your terminal or run:")
+		currentPath, _ := gxhome.Path(constants.CurrentLinkName)
+		messenger.Info(fmt.Sprintf("Open shells pick up the change immediately through %s.", currentPath))
+		messenger.Info("If this is your first switch, restart your terminal or run once:")
 		fmt.Println("  source ~/.bashrc  (bash)")
 		fmt.Println("  source ~/.zshrc   (zsh)")
-		messenger.Info("for the environment changes to take effect.")
 	}
 
 	return nil
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"os"
+
 	"github.com/kawaiirei0/gx/cmd/gx/cmd"
+	"github.com/kawaiirei0/gx/internal/shim"
 )
 
 // Version information (set via ldflags during build)
@@ -12,6 +15,12 @@ var (
 )
 
 func main() {
+	// 通过 shims 目录中的 go/gofmt 链接调用时，直接转发给解析出的版本
+	// 跳过命令解析和日志初始化以保持启动开销最小
+	if tool, ok := shim.ToolFromArgv0(os.Args[0]); ok {
+		os.Exit(shim.Main(tool, os.Args[1:]))
+	}
+
 	// Set version information in cmd package
 	cmd.SetVersionInfo(Version, Commit, BuildDate)
 	cmd.Execute()
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/kawaiirei0/gx/internal/config"
+	"github.com/kawaiirei0/gx/pkg/constants"
 )
 
 func main() {
@@ -30,16 +31,23 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	// 安装目录由分层配置中的 install.root 决定
+	settings, err := store.Settings("")
+	if err != nil {
+		log.Fatalf("Failed to resolve settings: %v", err)
+	}
+	installPath := settings.Get(constants.SettingInstallRoot)
+
 	fmt.Printf("   ✓ Config loaded\n")
 	fmt.Printf("   - Active Version: %s\n", cfg.ActiveVersion)
-	fmt.Printf("   - Install Path: %s\n", cfg.InstallPath)
+	fmt.Printf("   - Install Path: %s\n", installPath)
 	fmt.Printf("   - Versions: %d installed\n", len(cfg.Versions))
 
 	// 修改配置
 	fmt.Println("\n3. Updating configuration...")
 	cfg.ActiveVersion = "1.21.5"
-	cfg.Versions["1.21.5"] = cfg.InstallPath + "/go1.21.5"
-	cfg.Versions["1.22.0"] = cfg.InstallPath + "/go1.22.0"
+	cfg.Versions["1.21.5"] = installPath + "/go1.21.5"
+	cfg.Versions["1.22.0"] = installPath + "/go1.22.0"
 	cfg.LastUpdateCheck = time.Now()
 
 	// 保存配置
@@ -25,8 +25,14 @@ func main() {
 		log.Fatalf("Failed to create config store: %v\n", err)
 	}
 
+	// 创建版本元数据存储
+	storage, err := config.NewStorage(configStore)
+	if err != nil {
+		log.Fatalf("Failed to create storage: %v\n", err)
+	}
+
 	// 创建版本管理器
-	versionManager := version.NewManager(configStore, platformAdapter, nil, nil, nil)
+	versionManager := version.NewManager(configStore, storage, platformAdapter, nil, nil, nil)
 
 	// 创建跨平台构建器
 	builder := crossbuilder.NewCrossBuilder(versionManager, platformAdapter)
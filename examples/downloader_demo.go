@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/kawaiirei0/gx/internal/config"
 	"github.com/kawaiirei0/gx/internal/downloader"
 	"github.com/kawaiirei0/gx/internal/installer"
 	"github.com/kawaiirei0/gx/internal/platform"
+	"github.com/kawaiirei0/gx/pkg/constants"
 )
 
 func main() {
@@ -20,12 +22,18 @@ func main() {
 		return
 	}
 
-	dl := downloader.NewDownloader()
+	settings, err := configStore.Settings("")
+	if err != nil {
+		fmt.Printf("Error resolving settings: %v\n", err)
+		return
+	}
+
+	dl := downloader.NewDownloader(settings, nil)
 	_ = installer.NewInstaller(platformAdapter) // Create but don't use in demo
 
 	// 演示 1: 获取下载 URL
 	fmt.Println("1. Getting download URL for Go 1.21.5...")
-	url, err := dl.GetDownloadURL("1.21.5", platformAdapter.GetOS(), platformAdapter.GetArch())
+	url, err := dl.GetDownloadURL(context.Background(), "1.21.5", platformAdapter.GetOS(), platformAdapter.GetArch())
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	} else {
@@ -75,7 +83,7 @@ func main() {
 	fmt.Println("   - Installer: extracts and verifies installations")
 	fmt.Println()
 	fmt.Println("   Example:")
-	fmt.Println("   vm := version.NewManager(configStore, platform, envMgr, downloader, installer)")
+	fmt.Println("   vm := version.NewManager(configStore, storage, platform, envMgr, downloader, installer)")
 	fmt.Println("   err := vm.Install(\"1.21.5\", progressCallback)")
 
 	// 显示平台信息
@@ -89,7 +97,9 @@ func main() {
 	if err != nil {
 		fmt.Printf("\nConfig: Error loading - %v\n", err)
 	} else {
-		fmt.Printf("\nConfig Path: %s\n", cfg.InstallPath)
+		if settings, err := configStore.Settings(""); err == nil {
+			fmt.Printf("\nInstall Path: %s\n", settings.Get(constants.SettingInstallRoot))
+		}
 		fmt.Printf("Active Version: %s\n", cfg.ActiveVersion)
 		fmt.Printf("Installed Versions: %d\n", len(cfg.Versions))
 	}
@@ -105,13 +115,15 @@ func demonstrateFullInstallation() {
 	// Create all necessary components
 	platformAdapter := platform.NewAdapter()
 	configStore, _ := config.NewStore()
-	dl := downloader.NewDownloader()
+	settings, _ := configStore.Settings("")
+	dl := downloader.NewDownloader(settings, nil)
 	inst := installer.NewInstaller(platformAdapter)
 	
 	// Note: envManager needs to be implemented
 	// envManager := environment.NewManager(platformAdapter)
 	
-	// vm := version.NewManager(configStore, platformAdapter, envManager, dl, inst)
+	// storage, _ := config.NewStorage(configStore)
+	// vm := version.NewManager(configStore, storage, platformAdapter, envManager, dl, inst)
 
 	// Define progress callback
 	progress := func(downloaded, total int64) {
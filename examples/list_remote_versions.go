@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -22,14 +23,24 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create config store: %v", err)
 	}
+
+	storage, err := config.NewStorage(configStore)
+	if err != nil {
+		log.Fatalf("Failed to create storage: %v", err)
+	}
 	
 	envManager := environment.NewManager(platformAdapter)
-	downloaderInstance := downloader.NewDownloader()
+	settings, err := configStore.Settings("")
+	if err != nil {
+		log.Fatalf("Failed to resolve settings: %v", err)
+	}
+	downloaderInstance := downloader.NewDownloader(settings, nil)
 	installerInstance := installer.NewInstaller(platformAdapter)
 
 	// 创建版本管理器
 	versionManager := version.NewManager(
 		configStore,
+		storage,
 		platformAdapter,
 		envManager,
 		downloaderInstance,
@@ -38,7 +49,7 @@ func main() {
 
 	// 测试 GetLatest - 获取最新稳定版本
 	fmt.Println("Fetching latest stable version...")
-	latest, err := versionManager.GetLatest()
+	latest, err := versionManager.GetLatest(context.Background())
 	if err != nil {
 		log.Fatalf("Failed to get latest version: %v", err)
 	}
@@ -46,7 +57,7 @@ func main() {
 
 	// 测试 ListAvailable - 获取所有可用版本
 	fmt.Println("Fetching all available versions...")
-	versions, err := versionManager.ListAvailable()
+	versions, err := versionManager.ListAvailable(context.Background())
 	if err != nil {
 		log.Fatalf("Failed to list available versions: %v", err)
 	}
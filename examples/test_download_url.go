@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/kawaiirei0/gx/internal/downloader"
@@ -9,7 +10,8 @@ import (
 
 func main() {
 	platformAdapter := platform.NewAdapter()
-	dl := downloader.NewDownloader()
+	// 使用默认配置项，不缓存下载的归档
+	dl := downloader.NewDownloader(nil, nil)
 
 	// Try actual available versions
 	versions := []string{"1.25.4", "1.24.10"}
@@ -17,7 +19,7 @@ func main() {
 	fmt.Println("Testing download URL generation for recent Go versions:\n")
 
 	for _, version := range versions {
-		url, err := dl.GetDownloadURL(version, platformAdapter.GetOS(), platformAdapter.GetArch())
+		url, err := dl.GetDownloadURL(context.Background(), version, platformAdapter.GetOS(), platformAdapter.GetArch())
 		if err != nil {
 			fmt.Printf("❌ Go %s: %v\n", version, err)
 		} else {
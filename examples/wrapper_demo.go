@@ -25,11 +25,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 创建版本元数据存储
+	storage, err := config.NewStorage(configStore)
+	if err != nil {
+		fmt.Printf("Error creating storage: %v\n", err)
+		os.Exit(1)
+	}
+
 	// 创建环境管理器
 	envManager := environment.NewManager(platformAdapter)
 
 	// 创建下载器
-	downloaderInstance := downloader.NewDownloader()
+	settings, err := configStore.Settings("")
+	if err != nil {
+		fmt.Printf("Error resolving settings: %v\n", err)
+		os.Exit(1)
+	}
+	downloaderInstance := downloader.NewDownloader(settings, nil)
 
 	// 创建安装器
 	installerInstance := installer.NewInstaller(platformAdapter)
@@ -37,6 +49,7 @@ func main() {
 	// 创建版本管理器
 	versionManager := version.NewManager(
 		configStore,
+		storage,
 		platformAdapter,
 		envManager,
 		downloaderInstance,
@@ -0,0 +1,457 @@
+// Package bundle 创建和安装离线安装包集合
+//
+// 集合是一个未压缩的 tar 文件（安装包本身已经压缩），依次包含：
+//
+//  1. manifest.json：格式版本和每个安装包的版本、平台、SHA256 与原始下载地址
+//  2. 各版本和平台的官方安装包，保留官方文件名
+//  3. SHA256SUMS：sha256sum 格式的校验和，便于在没有 gx 的机器上手工校验
+//
+// 安装时先校验集合中的全部安装包，任何一个不匹配都不会安装，然后安装与当前平台匹配的版本。
+package bundle
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// bundler 实现 Bundler 接口
+type bundler struct {
+	versionManager interfaces.VersionManager
+	downloader     interfaces.Downloader
+	platform       interfaces.PlatformAdapter
+}
+
+// NewBundler 创建新的集合管理器
+func NewBundler(versionManager interfaces.VersionManager, downloader interfaces.Downloader, platform interfaces.PlatformAdapter) interfaces.Bundler {
+	return &bundler{
+		versionManager: versionManager,
+		downloader:     downloader,
+		platform:       platform,
+	}
+}
+
+// Create 下载指定版本和平台的安装包并打包为集合
+func (b *bundler) Create(ctx context.Context, opts interfaces.BundleOptions) (*interfaces.BundleManifest, error) {
+	if len(opts.Versions) == 0 {
+		return nil, errors.ErrInvalidInput.WithMessage("no versions given for the bundle")
+	}
+	if opts.OutputPath == "" {
+		return nil, errors.ErrInvalidInput.WithMessage("no output path given for the bundle")
+	}
+
+	platforms := opts.Platforms
+	if len(platforms) == 0 {
+		platforms = []interfaces.PlatformInfo{{OS: b.platform.GetOS(), Arch: b.platform.GetArch()}}
+	}
+
+	versions, err := b.resolveVersions(ctx, opts.Versions)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gx-bundle-*")
+	if err != nil {
+		return nil, errors.ErrOperationFailed.WithCause(err).WithMessage("failed to create temp directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest := &interfaces.BundleManifest{
+		Format:  constants.BundleFormat,
+		Created: time.Now().UTC(),
+	}
+
+	// 先查找所有下载地址，任何版本或平台不可用时不开始下载
+	for _, version := range versions {
+		for _, p := range platforms {
+			url, err := b.downloader.GetDownloadURL(ctx, version, p.OS, p.Arch)
+			if err != nil {
+				return nil, err
+			}
+			manifest.Entries = append(manifest.Entries, interfaces.BundleEntry{
+				Version:   version,
+				OS:        p.OS,
+				Arch:      p.Arch,
+				Filename:  path.Base(url),
+				SourceURL: url,
+			})
+		}
+	}
+
+	for i := range manifest.Entries {
+		if err := b.fetch(ctx, &manifest.Entries[i], tmpDir, opts.Progress); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeBundle(opts.OutputPath, manifest, tmpDir); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Bundle %s created with %d archives", opts.OutputPath, len(manifest.Entries))
+	return manifest, nil
+}
+
+// resolveVersions 将版本说明符解析为远程版本，去重后按版本排序
+func (b *bundler) resolveVersions(ctx context.Context, specs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var versions []string
+	for _, spec := range specs {
+		version, err := b.versionManager.FindRemote(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[version] {
+			seen[version] = true
+			versions = append(versions, version)
+		}
+	}
+	goversion.Sort(versions)
+	return versions, nil
+}
+
+// fetch 下载一个安装包到 dir，并记录其 SHA256、大小和实际提供安装包的发布源
+func (b *bundler) fetch(ctx context.Context, entry *interfaces.BundleEntry, dir string, progress interfaces.BundleProgressCallback) error {
+	archivePath := filepath.Join(dir, entry.Filename)
+	logger.Info("Downloading %s for the bundle", entry.Filename)
+
+	var callback interfaces.ProgressCallback
+	if progress != nil {
+		callback = func(downloaded, total int64) {
+			progress(*entry, downloaded, total)
+		}
+	}
+	result, err := b.downloader.DownloadFor(ctx, entry.Version, entry.OS, entry.Arch, archivePath, callback)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to stat archive").WithContext("path", archivePath)
+	}
+	entry.SHA256 = result.SHA256
+	entry.Size = info.Size()
+	entry.SourceURL = result.URL
+	entry.Source = result.Source
+
+	return nil
+}
+
+// writeBundle 将清单、dir 中的安装包和校验和文件写入集合
+// 先写入临时文件再重命名，失败时不会留下不完整的集合
+func writeBundle(outputPath string, manifest *interfaces.BundleManifest, dir string) error {
+	recovery := errors.NewRecoveryManager()
+	defer func() {
+		if err := recovery.Cleanup(); err != nil {
+			logger.Warn("Bundle cleanup failed: %v", err)
+		}
+	}()
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to create output directory")
+	}
+
+	tmpPath := outputPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to create bundle").WithContext("path", outputPath)
+	}
+	errors.EnsureFileCleanup(recovery, tmpPath)
+
+	if err := writeTar(file, manifest, dir); err != nil {
+		file.Close()
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to write bundle").WithContext("path", outputPath)
+	}
+	if err := file.Close(); err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to write bundle").WithContext("path", outputPath)
+	}
+
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to move bundle into place").WithContext("path", outputPath)
+	}
+
+	recovery.Clear()
+	return nil
+}
+
+// writeTar 按清单、安装包、校验和文件的顺序写入 tar
+func writeTar(w io.Writer, manifest *interfaces.BundleManifest, dir string) error {
+	tw := tar.NewWriter(w)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, constants.BundleManifestFile, data, manifest.Created); err != nil {
+		return err
+	}
+
+	var sums strings.Builder
+	for _, entry := range manifest.Entries {
+		if err := copyTarFile(tw, filepath.Join(dir, entry.Filename), entry.Filename, manifest.Created); err != nil {
+			return err
+		}
+		fmt.Fprintf(&sums, "%s  %s\n", entry.SHA256, entry.Filename)
+	}
+
+	if err := writeTarFile(tw, constants.BundleChecksumFile, []byte(sums.String()), manifest.Created); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// writeTarFile 写入内存中的文件
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+		Format:  tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// copyTarFile 将磁盘上的文件写入 tar
+func copyTarFile(tw *tar.Writer, src string, name string, modTime time.Time) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: modTime,
+		Format:  tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// Inspect 读取集合的清单
+func (b *bundler) Inspect(bundlePath string) (*interfaces.BundleManifest, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, errors.ErrNotFound.WithCause(err).WithMessage(fmt.Sprintf("cannot open bundle %s", bundlePath)).WithContext("path", bundlePath)
+	}
+	defer file.Close()
+
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.ErrArchiveCorrupted.WithCause(err).WithMessage("failed to read bundle").WithContext("path", bundlePath)
+		}
+		if header.Name == constants.BundleManifestFile {
+			return decodeManifest(tr, bundlePath)
+		}
+	}
+
+	return nil, errors.ErrArchiveCorrupted.
+		WithMessage(fmt.Sprintf("%s is not a gx bundle: no %s found", bundlePath, constants.BundleManifestFile)).
+		WithContext("path", bundlePath)
+}
+
+// decodeManifest 解析并检查清单
+func decodeManifest(r io.Reader, bundlePath string) (*interfaces.BundleManifest, error) {
+	var manifest interfaces.BundleManifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, errors.ErrArchiveCorrupted.WithCause(err).WithMessage("failed to parse bundle manifest").WithContext("path", bundlePath)
+	}
+
+	if manifest.Format > constants.BundleFormat {
+		return nil, errors.ErrInvalidInput.
+			WithMessage(fmt.Sprintf("bundle format %d was written by a newer version of gx (this version reads format %d)", manifest.Format, constants.BundleFormat)).
+			WithContext("path", bundlePath)
+	}
+
+	for _, entry := range manifest.Entries {
+		// 文件名会用于拼接临时路径，不允许包含目录
+		if entry.Filename == "" || entry.Filename == "." || entry.Filename == ".." || strings.ContainsAny(entry.Filename, `/\`) {
+			return nil, errors.ErrArchiveCorrupted.
+				WithMessage(fmt.Sprintf("invalid file name %q in bundle manifest", entry.Filename)).
+				WithContext("path", bundlePath)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// Install 校验集合中的所有安装包，并安装与当前平台匹配的版本
+// ctx 被取消时不再安装剩余的版本
+func (b *bundler) Install(ctx context.Context, bundlePath string) (*interfaces.BundleInstallResult, error) {
+	if abs, err := filepath.Abs(bundlePath); err == nil {
+		bundlePath = abs
+	}
+
+	manifest, err := b.Inspect(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &interfaces.BundleInstallResult{Failed: make(map[string]string)}
+
+	local := make(map[string]interfaces.BundleEntry)
+	for _, entry := range manifest.Entries {
+		if entry.OS == b.platform.GetOS() && entry.Arch == b.platform.GetArch() {
+			local[entry.Filename] = entry
+		} else {
+			result.Foreign = append(result.Foreign, entry)
+		}
+	}
+	if len(local) == 0 {
+		return nil, errors.ErrPlatformNotSupported.
+			WithMessage(fmt.Sprintf("bundle %s has no archives for %s/%s", bundlePath, b.platform.GetOS(), b.platform.GetArch())).
+			WithContext("path", bundlePath)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gx-bundle-*")
+	if err != nil {
+		return nil, errors.ErrOperationFailed.WithCause(err).WithMessage("failed to create temp directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractVerified(bundlePath, manifest, local, tmpDir); err != nil {
+		return nil, err
+	}
+
+	filenames := make([]string, 0, len(local))
+	for filename := range local {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		entry := local[filename]
+		_, err := b.versionManager.InstallArchive(ctx, filepath.Join(tmpDir, filename), interfaces.ArchiveOptions{
+			Version: entry.Version,
+			SHA256:  entry.SHA256,
+			Source:  bundlePath + "#" + filename,
+		})
+		switch {
+		case err == nil:
+			result.Installed = append(result.Installed, entry.Version)
+		case errors.IsType(err, errors.ErrVersionAlreadyInstalled):
+			result.Skipped = append(result.Skipped, entry.Version)
+		case errors.IsType(err, errors.ErrCancelled):
+			return result, err
+		default:
+			logger.Error("Failed to install %s from bundle: %v", entry.Version, err)
+			result.Failed[entry.Version] = err.Error()
+		}
+	}
+
+	if len(result.Failed) > 0 {
+		return result, errors.ErrPartialFailure.
+			WithMessage(fmt.Sprintf("%d of %d versions from the bundle failed to install", len(result.Failed), len(local))).
+			WithContext("path", bundlePath)
+	}
+	return result, nil
+}
+
+// extractVerified 校验集合中每个安装包的 SHA256，并将 local 中的安装包解出到 dir
+func extractVerified(bundlePath string, manifest *interfaces.BundleManifest, local map[string]interfaces.BundleEntry, dir string) error {
+	expected := make(map[string]interfaces.BundleEntry, len(manifest.Entries))
+	for _, entry := range manifest.Entries {
+		expected[entry.Filename] = entry
+	}
+
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return errors.ErrNotFound.WithCause(err).WithMessage(fmt.Sprintf("cannot open bundle %s", bundlePath)).WithContext("path", bundlePath)
+	}
+	defer file.Close()
+
+	seen := make(map[string]bool)
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.ErrArchiveCorrupted.WithCause(err).WithMessage("failed to read bundle").WithContext("path", bundlePath)
+		}
+
+		entry, ok := expected[header.Name]
+		if !ok || header.Typeflag != tar.TypeReg {
+			continue
+		}
+		seen[header.Name] = true
+
+		hash := sha256.New()
+		var dest io.Writer = hash
+		var out *os.File
+		if _, ok := local[header.Name]; ok {
+			out, err = os.Create(filepath.Join(dir, header.Name))
+			if err != nil {
+				return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to extract archive from bundle")
+			}
+			dest = io.MultiWriter(hash, out)
+		}
+
+		_, err = io.Copy(dest, tr)
+		if out != nil {
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return errors.ErrArchiveCorrupted.WithCause(err).WithMessage(fmt.Sprintf("failed to read %s from bundle", header.Name)).WithContext("path", bundlePath)
+		}
+
+		actual := hex.EncodeToString(hash.Sum(nil))
+		if !strings.EqualFold(actual, entry.SHA256) {
+			return errors.ErrChecksumMismatch.
+				WithMessage(fmt.Sprintf("checksum mismatch for %s in bundle: expected %s, got %s", header.Name, entry.SHA256, actual)).
+				WithContext("path", bundlePath)
+		}
+		logger.Info("Verified %s from bundle", header.Name)
+	}
+
+	for _, entry := range manifest.Entries {
+		if !seen[entry.Filename] {
+			return errors.ErrArchiveCorrupted.
+				WithMessage(fmt.Sprintf("bundle is missing %s listed in its manifest", entry.Filename)).
+				WithContext("path", bundlePath)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/utils"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// writeTestBundle 用伪造的安装包创建集合，返回集合路径和清单
+func writeTestBundle(t *testing.T) (string, *interfaces.BundleManifest) {
+	t.Helper()
+
+	dir := t.TempDir()
+	manifest := &interfaces.BundleManifest{Format: constants.BundleFormat, Created: time.Now().UTC()}
+	for _, arch := range []string{"amd64", "arm64"} {
+		entry := interfaces.BundleEntry{
+			Version:  "go1.22.3",
+			OS:       "linux",
+			Arch:     arch,
+			Filename: "go1.22.3.linux-" + arch + ".tar.gz",
+		}
+		path := filepath.Join(dir, entry.Filename)
+		if err := os.WriteFile(path, []byte("archive for "+arch), 0644); err != nil {
+			t.Fatal(err)
+		}
+		sum, err := utils.FileSHA256(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.SHA256 = sum
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "out", "go.tar")
+	if err := writeBundle(bundlePath, manifest, dir); err != nil {
+		t.Fatalf("writeBundle() error = %v", err)
+	}
+	return bundlePath, manifest
+}
+
+func TestWriteBundleLayout(t *testing.T) {
+	bundlePath, _ := writeTestBundle(t)
+
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var names []string
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+
+	want := []string{constants.BundleManifestFile, "go1.22.3.linux-amd64.tar.gz", "go1.22.3.linux-arm64.tar.gz", constants.BundleChecksumFile}
+	if len(names) != len(want) {
+		t.Fatalf("bundle contains %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, names[i], want[i])
+		}
+	}
+
+	if _, err := os.Stat(bundlePath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary bundle file was left behind")
+	}
+}
+
+func TestInspect(t *testing.T) {
+	bundlePath, want := writeTestBundle(t)
+
+	got, err := (&bundler{}).Inspect(bundlePath)
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if got.Format != constants.BundleFormat || len(got.Entries) != len(want.Entries) {
+		t.Fatalf("Inspect() = %+v, want %+v", got, want)
+	}
+	for i := range want.Entries {
+		if got.Entries[i] != want.Entries[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got.Entries[i], want.Entries[i])
+		}
+	}
+}
+
+func TestInspectRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     *errors.Error
+	}{
+		{"newer format", `{"format": 99, "entries": []}`, errors.ErrInvalidInput},
+		{"path in file name", `{"format": 1, "entries": [{"filename": "../evil.tar.gz"}]}`, errors.ErrArchiveCorrupted},
+		{"malformed", `{"format":`, errors.ErrArchiveCorrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			if err := writeTarFile(tw, constants.BundleManifestFile, []byte(tt.manifest), time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			tw.Close()
+
+			path := filepath.Join(t.TempDir(), "bundle.tar")
+			if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := (&bundler{}).Inspect(path)
+			if !errors.IsType(err, tt.want) {
+				t.Errorf("Inspect() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractVerified(t *testing.T) {
+	bundlePath, manifest := writeTestBundle(t)
+	local := map[string]interfaces.BundleEntry{manifest.Entries[0].Filename: manifest.Entries[0]}
+
+	dir := t.TempDir()
+	if err := extractVerified(bundlePath, manifest, local, dir); err != nil {
+		t.Fatalf("extractVerified() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, manifest.Entries[0].Filename))
+	if err != nil || string(data) != "archive for amd64" {
+		t.Errorf("extracted archive = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, manifest.Entries[1].Filename)); !os.IsNotExist(err) {
+		t.Errorf("archive for another platform was extracted")
+	}
+}
+
+func TestExtractVerifiedChecksumMismatch(t *testing.T) {
+	bundlePath, manifest := writeTestBundle(t)
+
+	// 其他平台的安装包不匹配时同样拒绝整个集合
+	manifest.Entries[1].SHA256 = "0000000000000000000000000000000000000000000000000000000000000000"
+	local := map[string]interfaces.BundleEntry{manifest.Entries[0].Filename: manifest.Entries[0]}
+
+	err := extractVerified(bundlePath, manifest, local, t.TempDir())
+	if !errors.IsType(err, errors.ErrChecksumMismatch) {
+		t.Errorf("extractVerified() error = %v, want checksum mismatch", err)
+	}
+}
+
+func TestExtractVerifiedMissingArchive(t *testing.T) {
+	bundlePath, manifest := writeTestBundle(t)
+	manifest.Entries = append(manifest.Entries, interfaces.BundleEntry{Filename: "go1.21.0.linux-amd64.tar.gz"})
+
+	err := extractVerified(bundlePath, manifest, nil, t.TempDir())
+	if !errors.IsType(err, errors.ErrArchiveCorrupted) {
+		t.Errorf("extractVerified() error = %v, want archive corrupted", err)
+	}
+}
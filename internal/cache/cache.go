@@ -0,0 +1,467 @@
+// Package cache 按 SHA256 寻址的安装包缓存
+//
+// 目录结构：
+//
+//	<dir>/index.json        各安装包的文件名、来源和使用时间
+//	<dir>/index.json.lock   修改 index.json 时的跨进程锁
+//	<dir>/sha256/<hash>     安装包，以其 SHA256 命名
+//
+// 缓存默认位于 ~/.gx/cache，由多个 GX_HOME 共享。下载器按版本列表中的校验和查找安装包，
+// 取出时重新计算校验和，因此缓存中的文件被篡改或损坏时只会导致重新下载。
+// 总大小超过容量时，按最近使用时间淘汰安装包。
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/gxhome"
+	"github.com/kawaiirei0/gx/internal/lock"
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/settings"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// sha256Pattern 缓存中文件名的格式
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// fileCache 基于目录的缓存实现
+type fileCache struct {
+	dir     string
+	maxSize int64
+}
+
+// indexFile index.json 的内容
+type indexFile struct {
+	Entries map[string]interfaces.CacheEntry `json:"entries"`
+}
+
+// New 创建位于 dir 的缓存，maxSize 为容量（字节），0 表示禁用缓存
+func New(dir string, maxSize int64) interfaces.ArchiveCache {
+	return &fileCache{dir: dir, maxSize: maxSize}
+}
+
+// NewFromSettings 根据 cache.dir 和 cache.max-size 创建缓存，s 为 nil 时使用默认值
+func NewFromSettings(s *interfaces.Settings) (interfaces.ArchiveCache, error) {
+	dir := s.Get(constants.SettingCacheDir)
+	if dir == "" {
+		var err error
+		if dir, err = gxhome.CacheDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	defaultSize, _ := settings.ParseSize(constants.DefaultCacheMaxSize)
+	return New(dir, settings.Size(s, constants.SettingCacheMaxSize, defaultSize)), nil
+}
+
+// Dir 返回缓存目录
+func (c *fileCache) Dir() string {
+	return c.dir
+}
+
+// MaxSize 返回缓存容量
+func (c *fileCache) MaxSize() int64 {
+	return c.maxSize
+}
+
+// Fetch 将校验和为 sha256 的安装包复制到 destPath，复制时重新校验
+func (c *fileCache) Fetch(sum string, destPath string) (*interfaces.CacheEntry, error) {
+	sum = strings.ToLower(sum)
+	if c.maxSize == 0 || !sha256Pattern.MatchString(sum) {
+		return nil, nil
+	}
+
+	idx, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := idx.Entries[sum]
+	if !ok {
+		return nil, nil
+	}
+
+	blob, err := os.Open(c.blobPath(sum))
+	if os.IsNotExist(err) {
+		logger.Warn("Cached archive %s is missing, dropping it from the cache", entry.Filename)
+		c.drop(sum)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to open cached archive").WithContext("cache_dir", c.dir)
+	}
+	defer blob.Close()
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to create destination directory")
+	}
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to create destination file")
+	}
+
+	// 复制的同时重新计算校验和
+	hash := sha256.New()
+	_, copyErr := io.Copy(io.MultiWriter(dest, hash), blob)
+	closeErr := dest.Close()
+	if copyErr != nil || closeErr != nil {
+		os.Remove(destPath)
+		if copyErr == nil {
+			copyErr = closeErr
+		}
+		return nil, errors.ErrStorageFailed.WithCause(copyErr).WithMessage("failed to copy cached archive")
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != sum {
+		logger.Warn("Cached archive %s is corrupted (got %s), dropping it from the cache", entry.Filename, actual)
+		os.Remove(destPath)
+		c.drop(sum)
+		return nil, nil
+	}
+
+	now := time.Now()
+	if err := c.update(func(idx *indexFile) bool {
+		e, ok := idx.Entries[sum]
+		if ok {
+			e.LastUsed = now
+			idx.Entries[sum] = e
+		}
+		return ok
+	}); err != nil {
+		logger.Warn("Failed to record cache use: %v", err)
+	}
+
+	entry.LastUsed = now
+	logger.Info("Using cached archive %s (%s)", entry.Filename, sum)
+	return &entry, nil
+}
+
+// Store 将已校验的安装包加入缓存，超出容量时淘汰最久未使用的安装包
+func (c *fileCache) Store(path string, entry interfaces.CacheEntry) error {
+	entry.SHA256 = strings.ToLower(entry.SHA256)
+	if c.maxSize == 0 || !sha256Pattern.MatchString(entry.SHA256) {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to read archive")
+	}
+	entry.Size = info.Size()
+	if entry.Size > c.maxSize {
+		logger.Info("Not caching %s: larger than %s (%d bytes)", entry.Filename, constants.SettingCacheMaxSize, c.maxSize)
+		return nil
+	}
+	if entry.Version == "" {
+		if parsed, ok := goversion.ParseFilename(entry.Filename); ok {
+			entry.Version = parsed.Version
+		}
+	}
+
+	if err := c.writeBlob(path, entry.SHA256); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return c.update(func(idx *indexFile) bool {
+		entry.Added = now
+		if existing, ok := idx.Entries[entry.SHA256]; ok {
+			entry.Added = existing.Added
+		}
+		entry.LastUsed = now
+		idx.Entries[entry.SHA256] = entry
+		c.evict(idx)
+		return true
+	})
+}
+
+// writeBlob 将文件复制到缓存中，已存在时不重复复制
+func (c *fileCache) writeBlob(path string, sum string) error {
+	blobPath := c.blobPath(sum)
+	if _, err := os.Stat(blobPath); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to create cache directory").WithContext("cache_dir", c.dir)
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to read archive")
+	}
+	defer src.Close()
+
+	// 写入临时文件后重命名，其他进程不会读到写了一半的安装包
+	tmpFile, err := os.CreateTemp(filepath.Dir(blobPath), sum+".*.tmp")
+	if err != nil {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to create cache file").WithContext("cache_dir", c.dir)
+	}
+	tmpPath := tmpFile.Name()
+
+	_, copyErr := io.Copy(tmpFile, src)
+	closeErr := tmpFile.Close()
+	if copyErr != nil || closeErr != nil {
+		os.Remove(tmpPath)
+		if copyErr == nil {
+			copyErr = closeErr
+		}
+		return errors.ErrStorageFailed.WithCause(copyErr).WithMessage("failed to write cache file").WithContext("cache_dir", c.dir)
+	}
+
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to set cache file permissions").WithContext("cache_dir", c.dir)
+	}
+
+	if err := os.Rename(tmpPath, blobPath); err != nil {
+		os.Remove(tmpPath)
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to write cache file").WithContext("cache_dir", c.dir)
+	}
+	return nil
+}
+
+// evict 按最近使用时间从旧到新淘汰安装包，直到总大小不超过容量
+func (c *fileCache) evict(idx *indexFile) {
+	var total int64
+	entries := make([]interfaces.CacheEntry, 0, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		total += entry.Size
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+
+	for _, entry := range entries {
+		if total <= c.maxSize {
+			break
+		}
+		logger.Info("Evicting %s from the cache (last used %s)", entry.Filename, entry.LastUsed.Format(time.RFC3339))
+		if err := os.Remove(c.blobPath(entry.SHA256)); err != nil && !os.IsNotExist(err) {
+			logger.Warn("Failed to evict %s: %v", entry.Filename, err)
+			continue
+		}
+		delete(idx.Entries, entry.SHA256)
+		total -= entry.Size
+	}
+}
+
+// List 返回缓存中的安装包，最近使用的在前
+func (c *fileCache) List() ([]interfaces.CacheEntry, error) {
+	idx, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	return sortedEntries(idx), nil
+}
+
+// Verify 重新计算所有安装包的校验和，删除已损坏的安装包
+// 计算校验和时不持有锁，其他 gx 进程可以同时使用缓存
+func (c *fileCache) Verify() (*interfaces.CacheVerifyResult, error) {
+	entries, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &interfaces.CacheVerifyResult{}
+	for _, entry := range entries {
+		file, err := os.Open(c.blobPath(entry.SHA256))
+		if os.IsNotExist(err) {
+			result.Missing = append(result.Missing, entry)
+			continue
+		}
+		if err != nil {
+			return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to open cached archive").WithContext("cache_dir", c.dir)
+		}
+
+		hash := sha256.New()
+		_, err = io.Copy(hash, file)
+		file.Close()
+		if err != nil {
+			return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to read cached archive").WithContext("cache_dir", c.dir)
+		}
+
+		if hex.EncodeToString(hash.Sum(nil)) == entry.SHA256 {
+			result.Valid = append(result.Valid, entry)
+		} else {
+			result.Corrupted = append(result.Corrupted, entry)
+		}
+	}
+
+	if len(result.Corrupted) == 0 && len(result.Missing) == 0 {
+		return result, nil
+	}
+
+	err = c.update(func(idx *indexFile) bool {
+		for _, entry := range append(append([]interfaces.CacheEntry(nil), result.Corrupted...), result.Missing...) {
+			os.Remove(c.blobPath(entry.SHA256))
+			delete(idx.Entries, entry.SHA256)
+		}
+		return true
+	})
+	return result, err
+}
+
+// Clean 删除指定版本的安装包，versions 为空时清空缓存
+func (c *fileCache) Clean(versions []string) ([]interfaces.CacheEntry, error) {
+	wanted := make(map[string]bool, len(versions))
+	for _, v := range versions {
+		wanted[goversion.Normalize(v)] = true
+	}
+
+	var removed []interfaces.CacheEntry
+	err := c.update(func(idx *indexFile) bool {
+		for _, entry := range sortedEntries(idx) {
+			if len(wanted) > 0 && !wanted[entry.Version] {
+				continue
+			}
+			if err := os.Remove(c.blobPath(entry.SHA256)); err != nil && !os.IsNotExist(err) {
+				logger.Warn("Failed to remove %s from the cache: %v", entry.Filename, err)
+				continue
+			}
+			delete(idx.Entries, entry.SHA256)
+			removed = append(removed, entry)
+		}
+
+		// 清空缓存时一并删除没有记录的文件，例如被中断的写入留下的临时文件
+		if len(wanted) == 0 {
+			files, _ := os.ReadDir(filepath.Join(c.dir, constants.CacheBlobDir))
+			for _, file := range files {
+				if _, ok := idx.Entries[file.Name()]; !ok {
+					os.RemoveAll(filepath.Join(c.dir, constants.CacheBlobDir, file.Name()))
+				}
+			}
+		}
+		return len(removed) > 0
+	})
+	return removed, err
+}
+
+// drop 从缓存中删除一个安装包，失败时只记录日志
+func (c *fileCache) drop(sum string) {
+	err := c.update(func(idx *indexFile) bool {
+		os.Remove(c.blobPath(sum))
+		delete(idx.Entries, sum)
+		return true
+	})
+	if err != nil {
+		logger.Warn("Failed to drop %s from the cache: %v", sum, err)
+	}
+}
+
+// blobPath 返回安装包在缓存中的路径
+func (c *fileCache) blobPath(sum string) string {
+	return filepath.Join(c.dir, constants.CacheBlobDir, sum)
+}
+
+// indexPath 返回 index.json 的路径
+func (c *fileCache) indexPath() string {
+	return filepath.Join(c.dir, constants.CacheIndexFile)
+}
+
+// load 读取 index.json
+// 缓存只是加速手段，index.json 损坏时从空缓存开始，而不是让安装失败
+func (c *fileCache) load() (*indexFile, error) {
+	idx := &indexFile{}
+
+	content, err := os.ReadFile(c.indexPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to read cache index").WithContext("cache_dir", c.dir)
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, idx); err != nil {
+			logger.Warn("Cache index %s is corrupted, starting with an empty cache: %v", c.indexPath(), err)
+			idx = &indexFile{}
+		}
+	}
+
+	if idx.Entries == nil {
+		idx.Entries = make(map[string]interfaces.CacheEntry)
+	}
+	return idx, nil
+}
+
+// update 在跨进程锁内加载 index.json、调用 fn 修改，fn 返回 true 时保存
+func (c *fileCache) update(fn func(idx *indexFile) bool) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to create cache directory").WithContext("cache_dir", c.dir)
+	}
+
+	l, err := lock.Acquire(c.indexPath()+constants.LockFileSuffix, lock.Options{Timeout: constants.ConfigLockTimeout})
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+
+	idx, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	if !fn(idx) {
+		return nil
+	}
+	return c.save(idx)
+}
+
+// save 原子地写入 index.json
+func (c *fileCache) save(idx *indexFile) error {
+	content, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to serialize cache index")
+	}
+
+	tmpFile, err := os.CreateTemp(c.dir, constants.CacheIndexFile+".*.tmp")
+	if err != nil {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to create temporary cache index").WithContext("cache_dir", c.dir)
+	}
+	tmpPath := tmpFile.Name()
+
+	_, writeErr := tmpFile.Write(content)
+	closeErr := tmpFile.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmpPath)
+		if writeErr == nil {
+			writeErr = closeErr
+		}
+		return errors.ErrStorageFailed.WithCause(writeErr).WithMessage("failed to write cache index").WithContext("cache_dir", c.dir)
+	}
+
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to set cache index permissions").WithContext("cache_dir", c.dir)
+	}
+
+	if err := os.Rename(tmpPath, c.indexPath()); err != nil {
+		os.Remove(tmpPath)
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to replace cache index").WithContext("cache_dir", c.dir)
+	}
+	return nil
+}
+
+// sortedEntries 返回按最近使用时间从新到旧排列的安装包
+func sortedEntries(idx *indexFile) []interfaces.CacheEntry {
+	entries := make([]interfaces.CacheEntry, 0, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].LastUsed.Equal(entries[j].LastUsed) {
+			return entries[i].LastUsed.After(entries[j].LastUsed)
+		}
+		return entries[i].Filename < entries[j].Filename
+	})
+	return entries
+}
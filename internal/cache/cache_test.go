@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// writeArchive 写入内容为 content 的安装包，返回路径和校验和
+func writeArchive(t *testing.T, name string, content string) (string, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(content))
+	return path, hex.EncodeToString(sum[:])
+}
+
+func TestStoreFetch(t *testing.T) {
+	c := New(t.TempDir(), 1<<20)
+	path, sum := writeArchive(t, "go1.22.3.linux-amd64.tar.gz", "archive")
+
+	if err := c.Store(path, interfaces.CacheEntry{SHA256: sum, Filename: "go1.22.3.linux-amd64.tar.gz", Source: "mirror"}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "out", "go.tar.gz")
+	entry, err := c.Fetch(sum, dest)
+	if err != nil || entry == nil {
+		t.Fatalf("Fetch() = %v, %v, want a hit", entry, err)
+	}
+	if entry.Version != "go1.22.3" || entry.Size != int64(len("archive")) || entry.Source != "mirror" {
+		t.Errorf("Fetch() = %+v", entry)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "archive" {
+		t.Errorf("fetched %q, want %q", data, "archive")
+	}
+
+	// 未知的校验和不命中
+	if entry, err := c.Fetch(sum[:63]+"0", dest); entry != nil || err != nil {
+		t.Errorf("Fetch(unknown) = %v, %v, want a miss", entry, err)
+	}
+}
+
+func TestFetchCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir, 1<<20)
+	path, sum := writeArchive(t, "go1.22.3.linux-amd64.tar.gz", "archive")
+	if err := c.Store(path, interfaces.CacheEntry{SHA256: sum, Filename: "go1.22.3.linux-amd64.tar.gz"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sha256", sum), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+	if entry, err := c.Fetch(sum, dest); entry != nil || err != nil {
+		t.Fatalf("Fetch() = %v, %v, want a miss", entry, err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("corrupted archive was left at the destination")
+	}
+	if entries, _ := c.List(); len(entries) != 0 {
+		t.Errorf("corrupted archive is still listed: %+v", entries)
+	}
+}
+
+func TestStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New(t.TempDir(), 20)
+	names := []string{"go1.20.14.linux-amd64.tar.gz", "go1.21.12.linux-amd64.tar.gz", "go1.22.5.linux-amd64.tar.gz"}
+	contents := []string{"aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc"}
+	sums := make([]string, len(names))
+
+	for i := range names[:2] {
+		path, sum := writeArchive(t, names[i], contents[i])
+		sums[i] = sum
+		if err := c.Store(path, interfaces.CacheEntry{SHA256: sum, Filename: names[i]}); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 使用较早加入的安装包后，另一个成为最久未使用的
+	if entry, _ := c.Fetch(sums[0], filepath.Join(t.TempDir(), "go.tar.gz")); entry == nil {
+		t.Fatal("Fetch() missed")
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	path, sum := writeArchive(t, names[2], contents[2])
+	sums[2] = sum
+	if err := c.Store(path, interfaces.CacheEntry{SHA256: sum, Filename: names[2]}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].SHA256 != sums[2] || entries[1].SHA256 != sums[0] {
+		t.Errorf("List() = %+v, want go1.22.5 and go1.20.14", entries)
+	}
+}
+
+func TestVerifyAndClean(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir, 1<<20)
+	good, goodSum := writeArchive(t, "go1.22.5.linux-amd64.tar.gz", "good")
+	bad, badSum := writeArchive(t, "go1.21.12.linux-amd64.tar.gz", "bad")
+	for _, a := range []struct{ path, sum, name string }{
+		{good, goodSum, "go1.22.5.linux-amd64.tar.gz"},
+		{bad, badSum, "go1.21.12.linux-amd64.tar.gz"},
+	} {
+		if err := c.Store(a.path, interfaces.CacheEntry{SHA256: a.sum, Filename: a.name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sha256", badSum), []byte("flipped"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(result.Valid) != 1 || len(result.Corrupted) != 1 || result.Corrupted[0].SHA256 != badSum {
+		t.Errorf("Verify() = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sha256", badSum)); !os.IsNotExist(err) {
+		t.Errorf("corrupted archive was not removed")
+	}
+
+	removed, err := c.Clean([]string{"1.21.12"})
+	if err != nil || len(removed) != 0 {
+		t.Errorf("Clean(1.21.12) = %+v, %v, want nothing removed", removed, err)
+	}
+	removed, err = c.Clean([]string{"1.22.5"})
+	if err != nil || len(removed) != 1 || removed[0].SHA256 != goodSum {
+		t.Errorf("Clean(1.22.5) = %+v, %v", removed, err)
+	}
+	if entries, _ := c.List(); len(entries) != 0 {
+		t.Errorf("List() after Clean = %+v", entries)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir, 0)
+	path, sum := writeArchive(t, "go1.22.5.linux-amd64.tar.gz", "archive")
+
+	if err := c.Store(path, interfaces.CacheEntry{SHA256: sum, Filename: "go1.22.5.linux-amd64.tar.gz"}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("disabled cache wrote %d files", len(entries))
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/kawaiirei0/gx/internal/gxhome"
+	"github.com/kawaiirei0/gx/internal/lock"
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/settings"
 	"github.com/kawaiirei0/gx/pkg/constants"
 	"github.com/kawaiirei0/gx/pkg/errors"
 	"github.com/kawaiirei0/gx/pkg/interfaces"
@@ -29,10 +33,17 @@ func NewStore() (interfaces.ConfigStore, error) {
 }
 
 // Load 加载配置文件
+// 旧 schema 的配置会在配置锁内自动迁移并写回；不解析系统和项目配置，
+// shim 等只需要版本信息的调用不受这些文件的影响，配置项通过 Settings 按需解析
 func (s *fileStore) Load() (*interfaces.Config, error) {
+	return s.read(false)
+}
+
+// read 读取配置文件，不解析其他配置层；locked 表示调用方已持有配置锁
+func (s *fileStore) read(locked bool) (*interfaces.Config, error) {
 	// 如果配置文件不存在，返回默认配置
 	if _, err := os.Stat(s.configPath); os.IsNotExist(err) {
-		return s.getDefaultConfig()
+		return s.getDefaultConfig(), nil
 	}
 
 	// 读取配置文件
@@ -45,33 +56,76 @@ func (s *fileStore) Load() (*interfaces.Config, error) {
 	}
 
 	// 解析 JSON
-	var config interfaces.Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
 		// 配置文件损坏，尝试恢复
 		backupPath := s.configPath + ".backup"
-		if _, backupErr := os.Stat(backupPath); backupErr == nil {
-			// 备份文件存在，尝试从备份恢复
-			backupData, backupReadErr := os.ReadFile(backupPath)
-			if backupReadErr == nil {
-				var backupConfig interfaces.Config
-				if backupParseErr := json.Unmarshal(backupData, &backupConfig); backupParseErr == nil {
-					// 备份文件有效，使用备份配置
-					config = backupConfig
-					// 尝试恢复配置文件
-					os.WriteFile(s.configPath, backupData, 0644)
-					
-					if config.Versions == nil {
-						config.Versions = make(map[string]string)
-					}
-					return &config, nil
-				}
+		backupData, backupErr := os.ReadFile(backupPath)
+		if backupErr == nil {
+			backupErr = json.Unmarshal(backupData, &doc)
+		}
+		if backupErr != nil {
+			// 无法恢复，返回错误
+			return nil, errors.ErrConfigCorrupted.
+				WithCause(err).
+				WithMessage("failed to parse config file and no valid backup found").
+				WithContext("config_path", s.configPath).
+				AsRecoverable()
+		}
+
+		// 备份文件有效，使用备份配置并尝试恢复配置文件
+		os.WriteFile(s.configPath, backupData, 0644)
+	}
+
+	version, err := schemaVersionOf(doc)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSchemaVersion(version, s.configPath); err != nil {
+		return nil, err
+	}
+
+	if version < CurrentSchemaVersion {
+		if !locked {
+			l, err := lock.Acquire(s.configPath+constants.LockFileSuffix, lock.Options{Timeout: constants.ConfigLockTimeout})
+			if err != nil {
+				return nil, err
 			}
+			defer l.Release()
+
+			// 等锁期间其他进程可能已经完成迁移，重新读取
+			return s.read(true)
+		}
+
+		if err := migrate(doc, s.configPath); err != nil {
+			return nil, err
+		}
+		config, err := s.decodeConfig(doc)
+		if err != nil {
+			return nil, err
 		}
-		
-		// 无法恢复，返回错误
+		if err := s.Save(config); err != nil {
+			return nil, err
+		}
+		logger.Info("Config migrated to schema version %d", CurrentSchemaVersion)
+		return config, nil
+	}
+
+	return s.decodeConfig(doc)
+}
+
+// decodeConfig 将 JSON 文档转换为 Config
+func (s *fileStore) decodeConfig(doc map[string]interface{}) (*interfaces.Config, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to serialize config")
+	}
+
+	var config interfaces.Config
+	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, errors.ErrConfigCorrupted.
 			WithCause(err).
-			WithMessage("failed to parse config file and no valid backup found").
+			WithMessage("failed to parse config file").
 			WithContext("config_path", s.configPath).
 			AsRecoverable()
 	}
@@ -84,6 +138,43 @@ func (s *fileStore) Load() (*interfaces.Config, error) {
 	return &config, nil
 }
 
+// Settings 返回 dir 所在项目的分层合并后的生效配置
+func (s *fileStore) Settings(dir string) (*interfaces.Settings, error) {
+	config, err := s.read(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings.Resolve(config.Settings, s.configPath, dir)
+}
+
+// UpdateSettings 在配置锁内修改用户层的配置项并保存
+// 不解析其他配置层，因此即使某一层中的配置项无效也可以修改
+func (s *fileStore) UpdateSettings(fn func(settings map[string]string) error) error {
+	l, err := lock.Acquire(s.configPath+constants.LockFileSuffix, lock.Options{Timeout: constants.ConfigLockTimeout})
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+
+	config, err := s.read(true)
+	if err != nil {
+		return err
+	}
+
+	if config.Settings == nil {
+		config.Settings = make(map[string]string)
+	}
+	if err := fn(config.Settings); err != nil {
+		return err
+	}
+	if len(config.Settings) == 0 {
+		config.Settings = nil
+	}
+
+	return s.Save(config)
+}
+
 // Save 保存配置到文件
 func (s *fileStore) Save(config *interfaces.Config) error {
 	// 确保配置目录存在
@@ -91,6 +182,9 @@ func (s *fileStore) Save(config *interfaces.Config) error {
 		return err
 	}
 
+	// 总是以当前 schema 写入
+	config.SchemaVersion = CurrentSchemaVersion
+
 	// 序列化为 JSON
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
@@ -135,6 +229,26 @@ func (s *fileStore) Save(config *interfaces.Config) error {
 	return nil
 }
 
+// Update 在跨进程锁内完成一次加载、修改、保存
+func (s *fileStore) Update(fn func(config *interfaces.Config) error) error {
+	l, err := lock.Acquire(s.configPath+constants.LockFileSuffix, lock.Options{Timeout: constants.ConfigLockTimeout})
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+
+	config, err := s.read(true)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(config); err != nil {
+		return err
+	}
+
+	return s.Save(config)
+}
+
 // EnsureConfigDir 确保配置目录存在
 func (s *fileStore) EnsureConfigDir() error {
 	configDir := filepath.Dir(s.configPath)
@@ -151,53 +265,31 @@ func (s *fileStore) EnsureConfigDir() error {
 }
 
 // getDefaultConfig 获取默认配置
-func (s *fileStore) getDefaultConfig() (*interfaces.Config, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to get home directory")
-	}
-
-	installPath := filepath.Join(homeDir, constants.DefaultInstallDir)
-
+func (s *fileStore) getDefaultConfig() *interfaces.Config {
 	return &interfaces.Config{
+		SchemaVersion:   CurrentSchemaVersion,
 		ActiveVersion:   "",
-		InstallPath:     installPath,
 		Versions:        make(map[string]string),
 		LastUpdateCheck: time.Time{},
-	}, nil
+	}
 }
 
 // GetDefaultConfig 获取默认配置（保留向后兼容）
 func GetDefaultConfig() (*interfaces.Config, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-
-	installPath := filepath.Join(homeDir, constants.DefaultInstallDir)
-
 	return &interfaces.Config{
+		SchemaVersion:   CurrentSchemaVersion,
 		ActiveVersion:   "",
-		InstallPath:     installPath,
 		Versions:        make(map[string]string),
 		LastUpdateCheck: time.Time{},
 	}, nil
 }
 
-// GetConfigDir 获取配置目录路径
+// GetConfigDir 获取 gx 根目录路径（GX_HOME）
 func GetConfigDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(homeDir, constants.ConfigDir), nil
+	return gxhome.Dir()
 }
 
 // GetConfigFilePath 获取配置文件路径
 func GetConfigFilePath() (string, error) {
-	configDir, err := GetConfigDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(configDir, constants.ConfigFileName), nil
+	return gxhome.ConfigFile()
 }
@@ -39,7 +39,6 @@ func TestConfigStore(t *testing.T) {
 	t.Run("Save config", func(t *testing.T) {
 		config := &Config{
 			ActiveVersion:   "1.21.5",
-			InstallPath:     "/home/user/.gx/versions",
 			Versions:        map[string]string{"1.21.5": "/home/user/.gx/versions/go1.21.5"},
 			LastUpdateCheck: time.Now(),
 		}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kawaiirei0/gx/internal/gxhome"
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+)
+
+// migration 将配置从 schema 版本 N 升级到 N+1
+// 迁移作用于原始 JSON 文档，因此可以处理当前 Config 结构中已经不存在的字段
+type migration struct {
+	description string
+	apply       func(doc map[string]interface{}) error
+}
+
+// migrations 按顺序注册的迁移，migrations[i] 将 schema 版本 i 升级到 i+1
+// 新增迁移时只能追加到末尾，已发布的迁移不能修改
+var migrations = []migration{
+	{
+		description: `add the "go" prefix to version numbers`,
+		apply:       migrateGoPrefix,
+	},
+	{
+		description: `move install_path into the install.root setting`,
+		apply:       migrateInstallPath,
+	},
+}
+
+// CurrentSchemaVersion 当前 gx 写入的配置 schema 版本，等于已注册的迁移数
+const CurrentSchemaVersion = 2
+
+func init() {
+	if len(migrations) != CurrentSchemaVersion {
+		panic(fmt.Sprintf("config: %d migrations registered for schema version %d", len(migrations), CurrentSchemaVersion))
+	}
+}
+
+// schemaVersionKey 配置文件中 schema 版本的字段名
+const schemaVersionKey = "schema_version"
+
+// PendingMigrations 返回从 schema 版本 from 升级到当前版本需要执行的迁移说明
+func PendingMigrations(from int) []string {
+	var pending []string
+	for v := from; v >= 0 && v < len(migrations); v++ {
+		pending = append(pending, fmt.Sprintf("v%d → v%d: %s", v, v+1, migrations[v].description))
+	}
+	return pending
+}
+
+// FileSchemaVersion 读取配置文件的 schema 版本而不执行迁移
+// 配置文件不存在时返回当前版本
+func FileSchemaVersion() (int, error) {
+	configPath, err := GetConfigFilePath()
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return CurrentSchemaVersion, nil
+	}
+	if err != nil {
+		return 0, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to read config file").WithContext("config_path", configPath)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return 0, errors.ErrConfigCorrupted.WithCause(err).WithMessage("failed to parse config file").WithContext("config_path", configPath)
+	}
+	return schemaVersionOf(doc)
+}
+
+// SchemaBackupPath 返回迁移 schema 版本 version 之前所做备份的路径
+func SchemaBackupPath(configPath string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", configPath, version)
+}
+
+// schemaVersionOf 读取文档中的 schema 版本，没有该字段的旧配置视为版本 0
+func schemaVersionOf(doc map[string]interface{}) (int, error) {
+	raw, ok := doc[schemaVersionKey]
+	if !ok {
+		return 0, nil
+	}
+
+	number, ok := raw.(float64)
+	if !ok || number < 0 || number != float64(int(number)) {
+		return 0, errors.ErrConfigCorrupted.WithMessage(fmt.Sprintf("invalid %s: %v", schemaVersionKey, raw))
+	}
+	return int(number), nil
+}
+
+// checkSchemaVersion 拒绝由更新的 gx 写入的配置，避免旧版本静默丢弃不认识的字段
+func checkSchemaVersion(version int, configPath string) error {
+	if version <= CurrentSchemaVersion {
+		return nil
+	}
+	return errors.ErrConfigSchemaUnsupported.
+		WithMessage(fmt.Sprintf("%s has schema version %d, but this gx only understands up to version %d; upgrade gx", configPath, version, CurrentSchemaVersion)).
+		WithContext("config_path", configPath).
+		WithContext("schema_version", version)
+}
+
+// migrate 依次执行从 doc 当前版本到 CurrentSchemaVersion 的迁移
+// 每一步执行前把该步的输入写入 SchemaBackupPath，迁移失败时保持原文件不变
+func migrate(doc map[string]interface{}, configPath string) error {
+	from, err := schemaVersionOf(doc)
+	if err != nil {
+		return err
+	}
+	if err := checkSchemaVersion(from, configPath); err != nil {
+		return err
+	}
+
+	for v := from; v < CurrentSchemaVersion; v++ {
+		step := migrations[v]
+
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to serialize config")
+		}
+		backupPath := SchemaBackupPath(configPath, v)
+		if err := os.WriteFile(backupPath, data, 0644); err != nil {
+			return errors.ErrStorageFailed.
+				WithCause(err).
+				WithMessage("failed to back up config before migration").
+				WithContext("backup_path", backupPath)
+		}
+
+		logger.Info("Migrating config schema v%d → v%d: %s (backup: %s)", v, v+1, step.description, backupPath)
+		if err := step.apply(doc); err != nil {
+			return errors.ErrConfigCorrupted.
+				WithCause(err).
+				WithMessage(fmt.Sprintf("config migration v%d → v%d failed", v, v+1)).
+				WithContext("config_path", configPath).
+				WithContext("backup_path", backupPath)
+		}
+		doc[schemaVersionKey] = v + 1
+	}
+
+	return nil
+}
+
+// migrateGoPrefix v0 → v1：早期版本记录的版本号不带 "go" 前缀
+func migrateGoPrefix(doc map[string]interface{}) error {
+	if versions, ok := doc["versions"].(map[string]interface{}); ok {
+		normalized := make(map[string]interface{}, len(versions))
+		for version, path := range versions {
+			normalized[goversion.Normalize(version)] = path
+		}
+		doc["versions"] = normalized
+	}
+
+	if active, ok := doc["active_version"].(string); ok && active != "" {
+		doc["active_version"] = goversion.Normalize(active)
+	}
+
+	return nil
+}
+
+// migrateInstallPath v1 → v2：安装目录改由分层配置的 install.root 决定
+// 与默认值不同的 install_path 保存为用户层的 install.root，默认值直接删除，
+// 以便系统或项目配置中的 install.root 能够生效
+func migrateInstallPath(doc map[string]interface{}) error {
+	installPath, _ := doc["install_path"].(string)
+	delete(doc, "install_path")
+	if installPath == "" {
+		return nil
+	}
+
+	defaultPath, err := gxhome.VersionsDir()
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(installPath) == filepath.Clean(defaultPath) {
+		return nil
+	}
+
+	userSettings, _ := doc["settings"].(map[string]interface{})
+	if userSettings == nil {
+		userSettings = make(map[string]interface{})
+	}
+	userSettings[constants.SettingInstallRoot] = installPath
+	doc["settings"] = userSettings
+	return nil
+}
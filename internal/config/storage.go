@@ -0,0 +1,227 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/kawaiirei0/gx/internal/lock"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// storageFile 版本元数据文件的内容
+type storageFile struct {
+	Versions map[string]interfaces.GoVersion `json:"versions"`
+}
+
+// fileStorage 基于文件的版本元数据存储
+// 版本到路径的映射和激活版本仍由配置文件负责，这里只保存每个版本的安装信息
+type fileStorage struct {
+	storagePath string
+	configStore interfaces.ConfigStore
+}
+
+// NewStorage 创建新的版本元数据存储
+// 激活版本的读写委托给 configStore，避免出现两份记录
+func NewStorage(configStore interfaces.ConfigStore) (interfaces.Storage, error) {
+	storagePath, err := GetStorageFilePath()
+	if err != nil {
+		return nil, errors.ErrStorageFailed.WithCause(err)
+	}
+
+	return &fileStorage{
+		storagePath: storagePath,
+		configStore: configStore,
+	}, nil
+}
+
+// SaveVersion 保存版本信息
+func (s *fileStorage) SaveVersion(version *interfaces.GoVersion) error {
+	record := *version
+	// 激活状态由配置决定，不持久化
+	record.IsActive = false
+
+	return s.update(func(data *storageFile) bool {
+		data.Versions[version.Version] = record
+		return true
+	})
+}
+
+// GetVersion 获取指定版本信息
+func (s *fileStorage) GetVersion(version string) (*interfaces.GoVersion, error) {
+	data, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := data.Versions[version]
+	if !ok {
+		return nil, errors.ErrVersionNotFound.
+			WithMessage("no metadata recorded for version "+version).
+			WithContext("storage_path", s.storagePath)
+	}
+
+	return &record, nil
+}
+
+// GetAllVersions 获取所有已记录的版本，按版本号排序
+func (s *fileStorage) GetAllVersions() ([]interfaces.GoVersion, error) {
+	data, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(data.Versions))
+	for name := range data.Versions {
+		names = append(names, name)
+	}
+	goversion.Sort(names)
+
+	versions := make([]interfaces.GoVersion, 0, len(names))
+	for _, name := range names {
+		versions = append(versions, data.Versions[name])
+	}
+	return versions, nil
+}
+
+// DeleteVersion 删除版本信息，版本不存在时不报错
+func (s *fileStorage) DeleteVersion(version string) error {
+	return s.update(func(data *storageFile) bool {
+		if _, ok := data.Versions[version]; !ok {
+			return false
+		}
+		delete(data.Versions, version)
+		return true
+	})
+}
+
+// SetActiveVersion 设置当前激活版本
+func (s *fileStorage) SetActiveVersion(version string) error {
+	return s.configStore.Update(func(cfg *interfaces.Config) error {
+		cfg.ActiveVersion = version
+		return nil
+	})
+}
+
+// GetActiveVersion 获取当前激活版本
+func (s *fileStorage) GetActiveVersion() (string, error) {
+	cfg, err := s.configStore.Load()
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.ActiveVersion, nil
+}
+
+// load 读取元数据文件，文件不存在时返回空记录
+func (s *fileStorage) load() (*storageFile, error) {
+	data := &storageFile{}
+
+	content, err := os.ReadFile(s.storagePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.ErrStorageFailed.
+			WithCause(err).
+			WithMessage("failed to read version metadata").
+			WithContext("storage_path", s.storagePath)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(content, data); err != nil {
+			return nil, errors.ErrConfigCorrupted.
+				WithCause(err).
+				WithMessage("failed to parse version metadata").
+				WithContext("storage_path", s.storagePath).
+				AsRecoverable()
+		}
+	}
+
+	if data.Versions == nil {
+		data.Versions = make(map[string]interfaces.GoVersion)
+	}
+	return data, nil
+}
+
+// update 在跨进程锁内加载元数据、调用 fn 修改，fn 返回 true 时保存
+func (s *fileStorage) update(fn func(data *storageFile) bool) error {
+	l, err := lock.Acquire(s.storagePath+constants.LockFileSuffix, lock.Options{Timeout: constants.ConfigLockTimeout})
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+
+	data, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if !fn(data) {
+		return nil
+	}
+	return s.save(data)
+}
+
+// save 原子地写入元数据文件
+func (s *fileStorage) save(data *storageFile) error {
+	if err := os.MkdirAll(filepath.Dir(s.storagePath), 0755); err != nil {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to create config directory")
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return errors.ErrStorageFailed.
+			WithCause(err).
+			WithMessage("failed to serialize version metadata").
+			WithContext("storage_path", s.storagePath)
+	}
+
+	// 写入唯一的临时文件后重命名：多个 shim 可能同时记录使用时间，
+	// 读取方不会看到写了一半的文件
+	tmpFile, err := os.CreateTemp(filepath.Dir(s.storagePath), constants.StorageFileName+".*.tmp")
+	if err != nil {
+		return errors.ErrStorageFailed.
+			WithCause(err).
+			WithMessage("failed to create temporary metadata file").
+			WithContext("storage_path", s.storagePath)
+	}
+	tmpPath := tmpFile.Name()
+
+	_, writeErr := tmpFile.Write(content)
+	closeErr := tmpFile.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmpPath)
+		if writeErr == nil {
+			writeErr = closeErr
+		}
+		return errors.ErrStorageFailed.
+			WithCause(writeErr).
+			WithMessage("failed to write version metadata").
+			WithContext("storage_path", s.storagePath)
+	}
+
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to set metadata file permissions")
+	}
+
+	if err := os.Rename(tmpPath, s.storagePath); err != nil {
+		os.Remove(tmpPath)
+		return errors.ErrStorageFailed.
+			WithCause(err).
+			WithMessage("failed to replace version metadata").
+			WithContext("storage_path", s.storagePath)
+	}
+
+	return nil
+}
+
+// GetStorageFilePath 获取版本元数据文件路径
+func GetStorageFilePath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, constants.StorageFileName), nil
+}
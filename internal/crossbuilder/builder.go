@@ -189,9 +189,12 @@ func (cb *crossBuilder) GetSupportedPlatforms() []interfaces.PlatformInfo {
 
 // getGoExecutable 获取当前使用的 Go 可执行文件路径
 func (cb *crossBuilder) getGoExecutable() (string, error) {
-	// 获取当前激活的版本
-	activeVersion, err := cb.versionManager.GetActive()
+	// 按解析链获取当前目录应使用的版本
+	activeVersion, err := cb.versionManager.Resolve("")
 	if err != nil {
+		if errors.IsType(err, errors.ErrVersionNotInstalled) {
+			return "", err
+		}
 		return "", errors.ErrVersionNotFound.WithCause(err).WithMessage("no active Go version found")
 	}
 
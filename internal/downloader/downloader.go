@@ -1,99 +1,309 @@
 package downloader
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/kawaiirei0/gx/internal/gxhome"
+	"github.com/kawaiirei0/gx/internal/lock"
 	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/settings"
 	"github.com/kawaiirei0/gx/pkg/constants"
 	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
 	"github.com/kawaiirei0/gx/pkg/interfaces"
 )
 
-// httpDownloader 基于 HTTP 的下载器实现
-type httpDownloader struct {
-	client  *http.Client
-	baseURL string
-	apiURL  string
+// releaseDownloader 按优先级依次从各个发布源下载的下载器实现
+type releaseDownloader struct {
+	sources    []interfaces.ReleaseSource
+	verify     string
+	cache      interfaces.ArchiveCache
+	partialDir string
+
+	// retries 遇到可恢复的网络错误时的重试次数，retryDelay 为第一次重试前的基准等待时间
+	retries    int
+	retryDelay time.Duration
+	sleep      func(context.Context, time.Duration) error
+
+	// versions 缓存各发布源的版本列表，一次命令中只获取一次
+	versions map[versionsKey][]interfaces.RemoteVersion
+
+	// unavailable 记录获取版本列表失败的发布源，一次命令中不再等待它们超时
+	unavailable map[int]error
+}
+
+// versionsKey 版本列表缓存的键
+type versionsKey struct {
+	source int
+	all    bool
+}
+
+// Options 下载器的选项
+type Options struct {
+	Verify     string                  // 校验策略，见 constants.VerifyStrict 等
+	Cache      interfaces.ArchiveCache // 安装包缓存，为 nil 时不使用缓存
+	PartialDir string                  // 保存未完成下载的目录，为空时不续传
+	Retries    int                     // 遇到可恢复的网络错误时的重试次数，0 表示不重试
+	RetryDelay time.Duration           // 第一次重试前的基准等待时间，之后每次翻倍
 }
 
 // NewDownloader 创建新的下载器
-func NewDownloader() interfaces.Downloader {
-	return &httpDownloader{
-		client: &http.Client{
-			Timeout: 30 * time.Minute, // 下载超时时间
-		},
-		baseURL: constants.GoDownloadURL,
-		apiURL:  constants.GoVersionsAPIURL,
+// 发布源、校验策略、超时时间和重试次数取自生效配置的 download.* 配置项，s 为 nil 时使用默认值
+func NewDownloader(s *interfaces.Settings, cache interfaces.ArchiveCache) interfaces.Downloader {
+	var sources []interfaces.ReleaseSource
+	for _, source := range settings.Sources(s) {
+		sources = append(sources, NewSource(source))
 	}
+
+	partialDir, err := gxhome.DownloadsDir()
+	if err != nil {
+		logger.Warn("Interrupted downloads will not be resumed: %v", err)
+	}
+
+	return NewDownloaderWithSources(sources, Options{
+		Verify:     s.Get(constants.SettingDownloadVerify),
+		Cache:      cache,
+		PartialDir: partialDir,
+		Retries:    settings.Int(s, constants.SettingDownloadRetries, constants.DownloadRetries),
+		RetryDelay: constants.RetryBaseDelay,
+	})
 }
 
-// GetDownloadURL 获取指定版本和平台的下载 URL
-func (d *httpDownloader) GetDownloadURL(version string, os string, arch string) (string, error) {
-	// 规范化版本号（确保有 "go" 前缀）
-	if !strings.HasPrefix(version, "go") {
-		version = "go" + version
+// NewDownloaderWithSources 创建使用指定发布源的下载器，sources 按优先级排列
+func NewDownloaderWithSources(sources []interfaces.ReleaseSource, opts Options) interfaces.Downloader {
+	return &releaseDownloader{
+		sources:     sources,
+		verify:      opts.Verify,
+		cache:       opts.Cache,
+		partialDir:  opts.PartialDir,
+		retries:     opts.Retries,
+		retryDelay:  opts.RetryDelay,
+		sleep:       sleepContext,
+		versions:    make(map[versionsKey][]interfaces.RemoteVersion),
+		unavailable: make(map[int]error),
 	}
+}
 
-	logger.Debug("Getting download URL for %s (%s/%s)", version, os, arch)
-	
-	// 查询远程版本信息
-	versions, err := d.fetchVersions()
+// Versions 获取版本列表，依次尝试各个发布源，返回第一个可用的发布源的列表
+func (d *releaseDownloader) Versions(ctx context.Context, all bool) ([]interfaces.RemoteVersion, error) {
+	var failures []string
+	for i, source := range d.sources {
+		versions, err := d.sourceVersions(ctx, i, all)
+		if err == nil {
+			return versions, nil
+		}
+		logger.Warn("Release source %s is unavailable: %v", source.Name(), err)
+		failures = append(failures, fmt.Sprintf("%s: %v", source.Name(), err))
+	}
+	return nil, errors.ErrNetworkError.
+		WithMessage("failed to fetch version list from any release source: " + strings.Join(failures, "; "))
+}
+
+// sourceVersions 获取第 i 个发布源的版本列表
+func (d *releaseDownloader) sourceVersions(ctx context.Context, i int, all bool) ([]interfaces.RemoteVersion, error) {
+	key := versionsKey{source: i, all: all}
+	if versions, ok := d.versions[key]; ok {
+		return versions, nil
+	}
+	if err, ok := d.unavailable[i]; ok {
+		return nil, err
+	}
+
+	logger.Debug("Fetching version list from %s (all=%v)", d.sources[i].Name(), all)
+	var versions []interfaces.RemoteVersion
+	err := d.retry(ctx, "Fetching the version list from "+d.sources[i].Name(), func() error {
+		var err error
+		versions, err = d.sources[i].Versions(ctx, all)
+		return err
+	})
 	if err != nil {
-		logger.Error("Failed to fetch versions: %v", err)
-		return "", errors.ErrNetworkError.WithCause(err).WithMessage("failed to fetch version list")
+		// 取消不代表发布源不可用
+		if ctx.Err() == nil {
+			d.unavailable[i] = err
+		}
+		return nil, err
 	}
+	d.versions[key] = versions
+	return versions, nil
+}
 
-	// 查找匹配的版本和文件
-	for _, v := range versions {
-		if v.Version != version {
-			continue
+// lookup 在第 i 个发布源中查找指定版本和平台的文件，没有时返回 nil
+// 默认列表只包含当前支持的版本，找不到时再查询完整的历史版本列表
+func (d *releaseDownloader) lookup(ctx context.Context, i int, version string, os string, arch string) (*interfaces.File, error) {
+	for _, all := range []bool{false, true} {
+		versions, err := d.sourceVersions(ctx, i, all)
+		if err != nil {
+			return nil, err
 		}
 
-		// 查找匹配平台的文件
-		for _, file := range v.Files {
-			if file.OS == os && file.Arch == arch {
-				return d.baseURL + file.Filename, nil
+		for _, v := range versions {
+			if v.Version != version {
+				continue
+			}
+
+			// 查找匹配平台的文件
+			for _, file := range v.Files {
+				if file.OS == os && file.Arch == arch {
+					return &file, nil
+				}
 			}
 		}
 	}
+	return nil, nil
+}
+
+// GetDownloadURL 获取指定版本和平台的下载 URL，来自第一个提供该文件的发布源
+func (d *releaseDownloader) GetDownloadURL(ctx context.Context, version string, os string, arch string) (string, error) {
+	// 规范化版本号（确保有 "go" 前缀）
+	version = goversion.Normalize(version)
+
+	logger.Debug("Getting download URL for %s (%s/%s)", version, os, arch)
+
+	var failures []string
+	for i, source := range d.sources {
+		file, err := d.lookup(ctx, i, version, os, arch)
+		if err != nil {
+			logger.Warn("Release source %s is unavailable: %v", source.Name(), err)
+			failures = append(failures, fmt.Sprintf("%s: %v", source.Name(), err))
+			continue
+		}
+		if file != nil {
+			return source.URL(*file), nil
+		}
+	}
+
+	if len(failures) == len(d.sources) {
+		return "", errors.ErrNetworkError.
+			WithMessage("failed to fetch version list from any release source: " + strings.Join(failures, "; "))
+	}
+	return "", notFoundError(version, os, arch)
+}
+
+// notFoundError 构建所有发布源都没有该文件的错误
+func notFoundError(version string, os string, arch string) error {
+	if os == "" && arch == "" {
+		return errors.ErrVersionNotFound.WithMessage(fmt.Sprintf("no source archive found for version %s", version))
+	}
+	return errors.ErrVersionNotFound.WithMessage(fmt.Sprintf("version %s not found for %s/%s", version, os, arch))
+}
+
+// Download 下载指定版本的 Go 安装包
+func (d *releaseDownloader) Download(ctx context.Context, version string, destPath string, progress interfaces.ProgressCallback) (*interfaces.DownloadResult, error) {
+	return d.download(ctx, version, runtime.GOOS, runtime.GOARCH, destPath, progress)
+}
+
+// DownloadFor 下载指定版本和平台的 Go 安装包
+func (d *releaseDownloader) DownloadFor(ctx context.Context, version string, os string, arch string, destPath string, progress interfaces.ProgressCallback) (*interfaces.DownloadResult, error) {
+	return d.download(ctx, version, os, arch, destPath, progress)
+}
+
+// DownloadSource 下载指定版本的 Go 源码包
+// 版本列表中源码包的 os 和 arch 为空
+func (d *releaseDownloader) DownloadSource(ctx context.Context, version string, destPath string, progress interfaces.ProgressCallback) (*interfaces.DownloadResult, error) {
+	return d.download(ctx, version, "", "", destPath, progress)
+}
+
+// download 下载指定版本和平台的文件，goos 和 goarch 为空时下载源码包
+// 依次尝试提供该文件的发布源；缓存中有校验和相同的安装包时直接使用，不再下载；
+// 校验和不匹配或 ctx 被取消时不再尝试其他发布源
+func (d *releaseDownloader) download(ctx context.Context, version string, goos string, goarch string, destPath string, progress interfaces.ProgressCallback) (*interfaces.DownloadResult, error) {
+	version = goversion.Normalize(version)
+	logger.Info("Starting download of Go version %s", version)
+
+	var lastErr error
+	for i, source := range d.sources {
+		file, err := d.lookup(ctx, i, version, goos, goarch)
+		if ctx.Err() != nil {
+			return nil, errors.ErrCancelled.WithMessage(fmt.Sprintf("download of Go %s was interrupted", goversion.Display(version)))
+		}
+		if err != nil {
+			logger.Warn("Release source %s is unavailable: %v", source.Name(), err)
+			lastErr = markRecoverable(errors.ErrNetworkError.WithCause(err)).WithMessage("failed to fetch version list").WithContext("source", source.Name())
+			continue
+		}
+		if file == nil {
+			logger.Info("Release source %s does not provide %s for %s/%s", source.Name(), version, goos, goarch)
+			continue
+		}
+
+		if result := d.fetchCached(source, file, destPath, progress); result != nil {
+			return result, nil
+		}
+
+		result, err := d.downloadFrom(ctx, source, file, destPath, progress)
+		if err == nil {
+			d.storeCached(source, file, destPath, result)
+			return result, nil
+		}
+		if errors.IsType(err, errors.ErrChecksumMismatch) || errors.IsType(err, errors.ErrCancelled) {
+			return nil, err
+		}
+		logger.Warn("Download from %s failed: %v", source.Name(), err)
+		lastErr = err
+	}
 
-	return "", errors.ErrVersionNotFound.WithMessage(fmt.Sprintf("version %s not found for %s/%s", version, os, arch))
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, notFoundError(version, goos, goarch)
 }
 
-// fetchVersions 从 Go 官方 API 获取版本列表
-func (d *httpDownloader) fetchVersions() ([]interfaces.RemoteVersion, error) {
-	resp, err := d.client.Get(d.apiURL)
+// fetchCached 从缓存中取出与版本列表校验和相同的安装包，未命中时返回 nil
+// 发布源没有公布校验和时无法确认缓存中的文件就是该安装包，因此不使用缓存
+func (d *releaseDownloader) fetchCached(source interfaces.ReleaseSource, file *interfaces.File, destPath string, progress interfaces.ProgressCallback) *interfaces.DownloadResult {
+	if d.cache == nil || file.SHA256 == "" {
+		return nil
+	}
+
+	entry, err := d.cache.Fetch(file.SHA256, destPath)
 	if err != nil {
-		return nil, err
+		logger.Warn("Failed to read %s from the cache: %v", file.Filename, err)
+		return nil
+	}
+	if entry == nil {
+		return nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if progress != nil {
+		progress(entry.Size, entry.Size)
+	}
+	return &interfaces.DownloadResult{
+		Source: constants.CacheSourceName,
+		URL:    source.URL(*file),
+		SHA256: entry.SHA256,
 	}
+}
 
-	var versions []interfaces.RemoteVersion
-	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
-		return nil, err
+// storeCached 将下载的安装包加入缓存，失败时只记录日志
+func (d *releaseDownloader) storeCached(source interfaces.ReleaseSource, file *interfaces.File, destPath string, result *interfaces.DownloadResult) {
+	if d.cache == nil {
+		return
 	}
 
-	return versions, nil
+	err := d.cache.Store(destPath, interfaces.CacheEntry{
+		SHA256:   result.SHA256,
+		Filename: file.Filename,
+		Source:   source.Name(),
+		URL:      result.URL,
+	})
+	if err != nil {
+		logger.Warn("Failed to cache %s: %v", file.Filename, err)
+	}
 }
 
-// Download 下载指定版本的 Go 安装包
-func (d *httpDownloader) Download(version string, destPath string, progress interfaces.ProgressCallback) error {
-	logger.Info("Starting download of Go version %s", version)
-	
+// downloadFrom 从发布源下载文件并校验
+// 发布源公布了校验和时，文件先下载到 partialDir 中以校验和命名的固定位置，
+// 中断后保留已下载的部分，下次下载同一文件时（可以来自其他发布源）从中断处续传
+func (d *releaseDownloader) downloadFrom(ctx context.Context, source interfaces.ReleaseSource, file *interfaces.File, destPath string, progress interfaces.ProgressCallback) (*interfaces.DownloadResult, error) {
 	// 创建恢复管理器
 	recovery := errors.NewRecoveryManager()
 	defer func() {
@@ -101,171 +311,228 @@ func (d *httpDownloader) Download(version string, destPath string, progress inte
 			logger.Warn("Download cleanup failed: %v", err)
 		}
 	}()
-	
-	// 获取下载 URL
-	url, err := d.GetDownloadURL(version, runtime.GOOS, runtime.GOARCH)
-	if err != nil {
-		logger.Error("Failed to get download URL: %v", err)
-		return err
+
+	url := source.URL(*file)
+	logger.Info("Download URL: %s (from %s)", url, source.Name())
+	logger.Info("Expected file size: %d bytes, SHA256: %s", file.Size, file.SHA256)
+
+	// strict 策略下没有校验和时不下载
+	if d.verify == constants.VerifyStrict && file.SHA256 == "" {
+		return nil, errors.ErrDownloadFailed.
+			WithMessage(fmt.Sprintf("no published checksum for %s and %s is %s", file.Filename, constants.SettingDownloadVerify, constants.VerifyStrict)).
+			WithContext("url", url)
 	}
-	logger.Info("Download URL: %s", url)
 
-	// 获取文件信息（包括 SHA256）
-	fileInfo, err := d.getFileInfo(version, runtime.GOOS, runtime.GOARCH)
-	if err != nil {
-		logger.Error("Failed to get file info: %v", err)
-		// 优雅降级：如果无法获取文件信息，继续下载但跳过校验
-		logger.Warn("Continuing download without checksum verification")
-		fileInfo = nil
+	path, release := d.acquirePartial(file)
+	resumable := path != ""
+	if resumable {
+		defer release()
 	} else {
-		logger.Info("Expected file size: %d bytes, SHA256: %s", fileInfo.Size, fileInfo.SHA256)
-	}
+		// 无法续传时下载到临时文件
+		tmpFile, err := os.CreateTemp("", "gx-download-*")
+		if err != nil {
+			return nil, errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to create temp file")
+		}
+		path = tmpFile.Name()
+		tmpFile.Close()
 
-	// 创建临时文件
-	tmpFile, err := os.CreateTemp("", "gx-download-*")
-	if err != nil {
-		return errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to create temp file")
+		// 注册临时文件清理
+		errors.EnsureFileCleanup(recovery, path)
 	}
-	tmpPath := tmpFile.Name()
-	
-	// 注册临时文件清理
-	errors.EnsureFileCleanup(recovery, tmpPath)
 
-	// 下载文件
-	logger.Info("Downloading to temporary file: %s", tmpPath)
-	expectedSize := int64(0)
-	if fileInfo != nil {
-		expectedSize = fileInfo.Size
-	}
-	
-	if err := d.downloadFile(url, tmpFile, expectedSize, progress); err != nil {
-		tmpFile.Close()
+	// 下载文件，同时计算整个文件的 SHA256
+	logger.Info("Downloading to %s", path)
+	actual, resumed, err := d.receiveWithRetry(ctx, source, file, path, resumable, resumable, progress)
+	if err != nil {
 		logger.Error("Download failed: %v", err)
-		return err
+		if gxErr, ok := err.(*errors.Error); ok && resumable {
+			logger.Info("Partial download kept at %s, it will be resumed on the next attempt", path)
+			return nil, gxErr.WithContext("partial_download", path)
+		}
+		return nil, err
 	}
-	tmpFile.Close()
 	logger.Info("Download completed")
 
-	// 验证 SHA256（如果有文件信息）
-	if fileInfo != nil && fileInfo.SHA256 != "" {
-		logger.Info("Verifying checksum...")
-		if err := d.verifyChecksum(tmpPath, fileInfo.SHA256); err != nil {
-			logger.Error("Checksum verification failed: %v", err)
-			return err
+	// 续传得到的文件校验失败时，之前下载的部分可能已损坏，从头重新下载一次
+	err = d.verifyChecksum(source, file, actual, url)
+	if err != nil && resumed {
+		logger.Warn("Resumed download of %s failed verification, downloading it again from the start", file.Filename)
+		if actual, _, err = d.receiveWithRetry(ctx, source, file, path, false, resumable, progress); err != nil {
+			return nil, err
 		}
-		logger.Info("Checksum verified successfully")
-	} else {
-		logger.Warn("Skipping checksum verification (file info not available)")
+		err = d.verifyChecksum(source, file, actual, url)
+	}
+	if err != nil {
+		os.Remove(path)
+		logger.Error("Checksum verification failed: %v", err)
+		return nil, err
 	}
 
 	// 确保目标目录存在
 	destDir := filepath.Dir(destPath)
 	if err := os.MkdirAll(destDir, 0755); err != nil {
 		logger.Error("Failed to create destination directory: %v", err)
-		return errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to create destination directory")
+		return nil, errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to create destination directory")
 	}
 
 	// 移动文件到目标位置
 	logger.Info("Moving file to destination: %s", destPath)
-	if err := os.Rename(tmpPath, destPath); err != nil {
+	if err := os.Rename(path, destPath); err != nil {
 		// 如果 Rename 失败（可能跨文件系统），尝试复制
 		logger.Warn("Rename failed, trying copy: %v", err)
-		if err := d.copyFile(tmpPath, destPath); err != nil {
+		if err := d.copyFile(path, destPath); err != nil {
 			logger.Error("Failed to copy file: %v", err)
-			return errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to move file to destination")
+			return nil, errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to move file to destination")
 		}
 		// 复制成功后删除临时文件
-		os.Remove(tmpPath)
+		os.Remove(path)
 	}
 
 	logger.Info("Download completed successfully: %s", destPath)
-	return nil
+	return &interfaces.DownloadResult{
+		Source: source.Name(),
+		URL:    url,
+		SHA256: actual,
+	}, nil
 }
 
-// getFileInfo 获取指定版本和平台的文件信息
-func (d *httpDownloader) getFileInfo(version string, os string, arch string) (*interfaces.File, error) {
-	// 规范化版本号
-	if !strings.HasPrefix(version, "go") {
-		version = "go" + version
+// acquirePartial 返回文件续传时使用的固定路径，并锁定它以免两个 gx 进程同时写入
+// 没有校验和（无法确认续传得到的文件完整）、未设置 partialDir 或文件正被其他进程下载时返回空路径
+func (d *releaseDownloader) acquirePartial(file *interfaces.File) (string, func()) {
+	if d.partialDir == "" || len(file.SHA256) < 16 {
+		return "", nil
+	}
+	if err := os.MkdirAll(d.partialDir, 0755); err != nil {
+		logger.Warn("Failed to create %s, the download will not be resumable: %v", d.partialDir, err)
+		return "", nil
 	}
 
-	versions, err := d.fetchVersions()
+	path := filepath.Join(d.partialDir, file.Filename+"-"+strings.ToLower(file.SHA256[:16])+constants.PartialFileSuffix)
+	l, err := lock.Acquire(path+constants.LockFileSuffix, lock.Options{})
 	if err != nil {
-		return nil, errors.ErrNetworkError.WithCause(err).WithMessage("failed to fetch version list")
+		logger.Warn("%s is being downloaded by another gx process, the download will not be resumable: %v", file.Filename, err)
+		return "", nil
 	}
+	return path, func() { l.Release() }
+}
 
-	for _, v := range versions {
-		if v.Version != version {
-			continue
-		}
+// receiveWithRetry 调用 receive，遇到可恢复的网络错误时重试
+// 第一次按 resume 决定是否续传；之后的重试在 resumable 为 true 时从中断处续传，否则从头下载
+// ctx 被取消时返回 ErrCancelled
+func (d *releaseDownloader) receiveWithRetry(ctx context.Context, source interfaces.ReleaseSource, file *interfaces.File, path string, resume bool, resumable bool, progress interfaces.ProgressCallback) (string, bool, error) {
+	var actual string
+	var resumed bool
+	err := d.retry(ctx, fmt.Sprintf("Download of %s from %s", file.Filename, source.Name()), func() error {
+		var err error
+		actual, resumed, err = d.receive(ctx, source, file, path, resume, progress)
+		resume = resumable
+		return err
+	})
+	if err != nil && ctx.Err() != nil {
+		return "", false, errors.ErrCancelled.WithMessage(fmt.Sprintf("download of %s was interrupted", file.Filename))
+	}
+	return actual, resumed, err
+}
 
-		for _, file := range v.Files {
-			if file.OS == os && file.Arch == arch {
-				return &file, nil
-			}
+// receive 将文件下载到 path，返回整个文件的 SHA256 以及是否续传了已有的内容
+// resume 为 true 时从 path 中已有内容的末尾续传；发布源不支持续传时清空 path 从头写入
+func (d *releaseDownloader) receive(ctx context.Context, source interfaces.ReleaseSource, file *interfaces.File, path string, resume bool, progress interfaces.ProgressCallback) (string, bool, error) {
+	out, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return "", false, errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to open download file").WithContext("path", path)
+	}
+	defer out.Close()
+
+	var offset int64
+	if resume {
+		if info, err := out.Stat(); err == nil {
+			offset = info.Size()
+		}
+		if file.Size > 0 && offset > file.Size {
+			offset = 0
 		}
 	}
 
-	return nil, errors.ErrVersionNotFound.WithMessage(fmt.Sprintf("file info not found for %s/%s", os, arch))
-}
+	var stream *interfaces.FileStream
+	if offset > 0 && offset == file.Size {
+		// 上次已下载完整，只需校验
+		logger.Info("%s was already downloaded completely", file.Filename)
+		stream = &interfaces.FileStream{Body: io.NopCloser(strings.NewReader("")), Offset: offset, Size: file.Size}
+	} else {
+		stream, err = source.Open(ctx, *file, offset)
+		if err != nil {
+			return "", false, markRecoverable(errors.ErrDownloadFailed.WithCause(err)).WithMessage("failed to start download").WithContext("source", source.Name())
+		}
+	}
+	defer stream.Body.Close()
 
-// downloadFile 下载文件并显示进度
-func (d *httpDownloader) downloadFile(url string, dest *os.File, expectedSize int64, progress interfaces.ProgressCallback) error {
-	resp, err := d.client.Get(url)
-	if err != nil {
-		return errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to start download")
+	if stream.Offset != offset {
+		logger.Info("%s does not support resuming, restarting the download of %s", source.Name(), file.Filename)
+	} else if offset > 0 {
+		logger.Info("Resuming download of %s at byte %d", file.Filename, offset)
 	}
-	defer resp.Body.Close()
+	offset = stream.Offset
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.ErrDownloadFailed.WithMessage(fmt.Sprintf("unexpected status code: %d", resp.StatusCode))
+	// 已下载的部分计入校验和，之后的内容从 offset 处写入
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(out, 0, offset)); err != nil {
+		return "", false, errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to read partial download").WithContext("path", path)
+	}
+	if err := out.Truncate(offset); err != nil {
+		return "", false, errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to truncate download file").WithContext("path", path)
+	}
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		return "", false, errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to seek download file").WithContext("path", path)
 	}
 
-	// 使用响应头中的 Content-Length，如果没有则使用预期大小
-	totalSize := resp.ContentLength
+	// 优先使用发布源报告的大小，没有时使用版本列表中的大小
+	totalSize := stream.Size
 	if totalSize <= 0 {
-		totalSize = expectedSize
+		totalSize = file.Size
 	}
 
-	// 创建进度读取器
+	// 创建进度读取器，进度从已下载的部分开始
 	reader := &progressReader{
-		reader:   resp.Body,
+		reader:   &contextReader{ctx: ctx, reader: stream.Body},
 		total:    totalSize,
+		current:  offset,
 		callback: progress,
 	}
 
 	// 复制数据
-	_, err = io.Copy(dest, reader)
-	if err != nil {
-		return errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to write file")
+	if _, err := io.Copy(io.MultiWriter(out, hash), reader); err != nil {
+		return "", offset > 0, markRecoverable(errors.ErrDownloadFailed.WithCause(err)).WithMessage("failed to write file").WithContext("source", source.Name())
+	}
+	if err := out.Close(); err != nil {
+		return "", offset > 0, errors.ErrDownloadFailed.WithCause(err).WithMessage("failed to write file").WithContext("path", path)
 	}
 
-	return nil
+	return hex.EncodeToString(hash.Sum(nil)), offset > 0, nil
 }
 
-// verifyChecksum 验证文件的 SHA256 校验和
-func (d *httpDownloader) verifyChecksum(filePath string, expectedChecksum string) error {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return errors.ErrChecksumMismatch.WithCause(err).WithMessage("failed to open file for verification")
+// verifyChecksum 按校验策略校验下载的文件
+func (d *releaseDownloader) verifyChecksum(source interfaces.ReleaseSource, file *interfaces.File, actual string, url string) error {
+	if d.verify == constants.VerifyOff {
+		logger.Warn("Skipping checksum verification (%s = %s)", constants.SettingDownloadVerify, constants.VerifyOff)
+		return nil
 	}
-	defer file.Close()
-
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return errors.ErrChecksumMismatch.WithCause(err).WithMessage("failed to calculate checksum")
+	if file.SHA256 == "" {
+		logger.Warn("Skipping checksum verification (%s publishes no checksum for %s)", source.Name(), file.Filename)
+		return nil
 	}
 
-	actualChecksum := hex.EncodeToString(hash.Sum(nil))
-	if actualChecksum != expectedChecksum {
-		return errors.ErrChecksumMismatch.WithMessage(fmt.Sprintf("checksum mismatch: expected %s, got %s", expectedChecksum, actualChecksum))
+	logger.Info("Verifying checksum...")
+	if !strings.EqualFold(actual, file.SHA256) {
+		return errors.ErrChecksumMismatch.
+			WithMessage(fmt.Sprintf("checksum mismatch: expected %s, got %s", file.SHA256, actual)).
+			WithContext("url", url)
 	}
-
+	logger.Info("Checksum verified successfully")
 	return nil
 }
 
 // copyFile 复制文件（用于跨文件系统移动）
-func (d *httpDownloader) copyFile(src, dst string) error {
+func (d *releaseDownloader) copyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
 	if err != nil {
 		return err
@@ -285,6 +552,19 @@ func (d *httpDownloader) copyFile(src, dst string) error {
 	return destFile.Sync()
 }
 
+// contextReader 在 ctx 取消后停止读取，本地发布源的文件读取不会随 ctx 中断
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.reader.Read(p)
+}
+
 // progressReader 包装 io.Reader 以提供进度回调
 type progressReader struct {
 	reader   io.Reader
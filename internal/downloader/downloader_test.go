@@ -0,0 +1,395 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/cache"
+	"github.com/kawaiirei0/gx/internal/settings"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// fakeSource 内存中的发布源
+type fakeSource struct {
+	name     string
+	files    map[string]string // 文件名 -> 内容
+	sums     map[string]string // 文件名 -> 公布的校验和，未设置时使用内容的校验和
+	down     bool              // 版本列表不可用
+	broken   bool              // 版本列表可用但无法下载
+	requests int               // 获取版本列表的次数
+}
+
+func (s *fakeSource) Name() string { return s.name }
+
+func (s *fakeSource) Versions(ctx context.Context, all bool) ([]interfaces.RemoteVersion, error) {
+	s.requests++
+	if s.down {
+		return nil, fmt.Errorf("connection refused")
+	}
+	var versions []interfaces.RemoteVersion
+	for name, content := range s.files {
+		sum, ok := s.sums[name]
+		if !ok {
+			sum = sha256Hex(content)
+		}
+		parsed, _ := goversion.ParseFilename(name)
+		versions = append(versions, interfaces.RemoteVersion{
+			Version: parsed.Version,
+			Stable:  true,
+			Files:   []interfaces.File{{Filename: name, OS: runtime.GOOS, Arch: runtime.GOARCH, SHA256: sum, Size: int64(len(content))}},
+		})
+	}
+	return versions, nil
+}
+
+func (s *fakeSource) URL(file interfaces.File) string {
+	return "fake://" + s.name + "/" + file.Filename
+}
+
+func (s *fakeSource) Open(ctx context.Context, file interfaces.File, offset int64) (*interfaces.FileStream, error) {
+	if s.broken {
+		return nil, fmt.Errorf("connection reset")
+	}
+	content := s.files[file.Filename]
+	return &interfaces.FileStream{Body: io.NopCloser(strings.NewReader(content)), Size: int64(len(content))}, nil
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func archiveName(version string) string {
+	return version + "." + runtime.GOOS + "-" + runtime.GOARCH + constants.ArchiveExtTarGz
+}
+
+func TestDownloadUsesFirstSourceWithTheFile(t *testing.T) {
+	corp := &fakeSource{name: "corp", down: true}
+	share := &fakeSource{name: "share", files: map[string]string{archiveName("go1.21.5"): "old"}}
+	mirror := &fakeSource{name: "mirror", files: map[string]string{archiveName("go1.22.3"): "new"}}
+	d := NewDownloaderWithSources([]interfaces.ReleaseSource{corp, share, mirror}, Options{Verify: constants.VerifyAuto})
+
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+	result, err := d.Download(context.Background(), "1.22.3", dest, nil)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if result.Source != "mirror" || result.SHA256 != sha256Hex("new") {
+		t.Errorf("Download() = %+v, want the file from mirror", result)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "new" {
+		t.Errorf("downloaded %q, want %q", data, "new")
+	}
+
+	// 不可用的发布源在同一个下载器中只尝试一次
+	if _, err := d.GetDownloadURL(context.Background(), "1.21.5", runtime.GOOS, runtime.GOARCH); err != nil {
+		t.Fatalf("GetDownloadURL() error = %v", err)
+	}
+	if corp.requests != 1 {
+		t.Errorf("unavailable source was asked %d times, want 1", corp.requests)
+	}
+}
+
+func TestDownloadFallsBackWhenSourceFails(t *testing.T) {
+	name := archiveName("go1.22.3")
+	share := &fakeSource{name: "share", files: map[string]string{name: "content"}, broken: true}
+	mirror := &fakeSource{name: "mirror", files: map[string]string{name: "content"}}
+	d := NewDownloaderWithSources([]interfaces.ReleaseSource{share, mirror}, Options{Verify: constants.VerifyAuto})
+
+	result, err := d.Download(context.Background(), "go1.22.3", filepath.Join(t.TempDir(), "go.tar.gz"), nil)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if result.Source != "mirror" {
+		t.Errorf("Download() served by %s, want mirror", result.Source)
+	}
+}
+
+func TestDownloadChecksumMismatchStops(t *testing.T) {
+	name := archiveName("go1.22.3")
+	share := &fakeSource{name: "share", files: map[string]string{name: "tampered"}, sums: map[string]string{name: sha256Hex("content")}}
+	mirror := &fakeSource{name: "mirror", files: map[string]string{name: "content"}}
+	d := NewDownloaderWithSources([]interfaces.ReleaseSource{share, mirror}, Options{Verify: constants.VerifyAuto})
+
+	_, err := d.Download(context.Background(), "go1.22.3", filepath.Join(t.TempDir(), "go.tar.gz"), nil)
+	if !errors.IsType(err, errors.ErrChecksumMismatch) {
+		t.Errorf("Download() error = %v, want checksum mismatch", err)
+	}
+}
+
+func TestDownloadNotFound(t *testing.T) {
+	mirror := &fakeSource{name: "mirror", files: map[string]string{archiveName("go1.22.3"): "content"}}
+	d := NewDownloaderWithSources([]interfaces.ReleaseSource{mirror}, Options{Verify: constants.VerifyAuto})
+
+	_, err := d.Download(context.Background(), "go1.17.1", filepath.Join(t.TempDir(), "go.tar.gz"), nil)
+	if !errors.IsType(err, errors.ErrVersionNotFound) {
+		t.Errorf("Download() error = %v, want version not found", err)
+	}
+}
+
+func TestVersionsAllSourcesDown(t *testing.T) {
+	d := NewDownloaderWithSources([]interfaces.ReleaseSource{
+		&fakeSource{name: "corp", down: true},
+		&fakeSource{name: "mirror", down: true},
+	}, Options{Verify: constants.VerifyAuto})
+
+	_, err := d.Versions(context.Background(), false)
+	if !errors.IsType(err, errors.ErrNetworkError) {
+		t.Fatalf("Versions() error = %v, want network error", err)
+	}
+	if !strings.Contains(err.Error(), "corp") || !strings.Contains(err.Error(), "mirror") {
+		t.Errorf("Versions() error = %v, want both sources named", err)
+	}
+}
+
+func TestDirSource(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go1.22.3.linux-amd64.tar.gz":  "a",
+		"go1.22.3.src.tar.gz":          "b",
+		"go1.23rc1.linux-amd64.tar.gz": "c",
+		"notes.txt":                    "d",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go1.22.3.linux-amd64.tar.gz.sha256"), []byte(sha256Hex("a")+"  go1.22.3.linux-amd64.tar.gz\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := (&dirSource{name: "share", dir: dir}).Versions(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != "go1.23rc1" || versions[0].Stable || versions[1].Version != "go1.22.3" {
+		t.Fatalf("Versions() = %+v, want go1.23rc1 then go1.22.3", versions)
+	}
+	if len(versions[1].Files) != 2 {
+		t.Fatalf("go1.22.3 files = %+v, want the archive and the source", versions[1].Files)
+	}
+	for _, file := range versions[1].Files {
+		if file.OS == "linux" && file.SHA256 != sha256Hex("a") {
+			t.Errorf("SHA256 = %q, want the sidecar checksum", file.SHA256)
+		}
+	}
+}
+
+func TestDownloadUsesCache(t *testing.T) {
+	name := archiveName("go1.22.3")
+	mirror := &fakeSource{name: "mirror", files: map[string]string{name: "content"}}
+	archives := cache.New(t.TempDir(), 1<<20)
+
+	first := NewDownloaderWithSources([]interfaces.ReleaseSource{mirror}, Options{Verify: constants.VerifyAuto, Cache: archives})
+	if result, err := first.Download(context.Background(), "go1.22.3", filepath.Join(t.TempDir(), "go.tar.gz"), nil); err != nil || result.Source != "mirror" {
+		t.Fatalf("Download() = %+v, %v, want the file from mirror", result, err)
+	}
+
+	// 发布源无法下载时仍可从缓存取出
+	mirror.broken = true
+	second := NewDownloaderWithSources([]interfaces.ReleaseSource{mirror}, Options{Verify: constants.VerifyAuto, Cache: archives})
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+	result, err := second.Download(context.Background(), "go1.22.3", dest, nil)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if result.Source != constants.CacheSourceName || result.SHA256 != sha256Hex("content") {
+		t.Errorf("Download() = %+v, want the cached file", result)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "content" {
+		t.Errorf("downloaded %q, want %q", data, "content")
+	}
+
+	// 版本列表中的校验和不同时不使用缓存
+	mirror.sums = map[string]string{name: sha256Hex("other")}
+	third := NewDownloaderWithSources([]interfaces.ReleaseSource{mirror}, Options{Verify: constants.VerifyAuto, Cache: archives})
+	if _, err := third.Download(context.Background(), "go1.22.3", filepath.Join(t.TempDir(), "go.tar.gz"), nil); err == nil {
+		t.Error("Download() used a cached archive with a different checksum")
+	}
+}
+
+// flakyServer 提供一个版本的 HTTP 镜像，可以在传输中途断开连接或忽略 Range
+type flakyServer struct {
+	content     []byte
+	checksum    string // 版本列表中公布的校验和，为空时使用内容的校验和
+	ignoreRange bool
+	dropAfter   int      // 下一次下载写入多少字节后断开连接，0 表示不断开
+	ranges      []string // 每次下载请求的 Range 头
+	statuses    []int    // 接下来的请求（包括版本列表）依次返回的错误状态码
+	retryAfter  string   // 返回错误状态码时的 Retry-After 头
+	requests    int      // 收到的请求数（包括版本列表）
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests++
+	if len(f.statuses) > 0 {
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		w.WriteHeader(f.statuses[0])
+		f.statuses = f.statuses[1:]
+		return
+	}
+
+	if r.URL.Query().Get("mode") == "json" {
+		checksum := f.checksum
+		if checksum == "" {
+			sum := sha256.Sum256(f.content)
+			checksum = hex.EncodeToString(sum[:])
+		}
+		json.NewEncoder(w).Encode([]interfaces.RemoteVersion{{
+			Version: "go1.22.3",
+			Stable:  true,
+			Files: []interfaces.File{{
+				Filename: archiveName("go1.22.3"),
+				OS:       runtime.GOOS,
+				Arch:     runtime.GOARCH,
+				SHA256:   checksum,
+				Size:     int64(len(f.content)),
+			}},
+		}})
+		return
+	}
+
+	f.ranges = append(f.ranges, r.Header.Get("Range"))
+	start := 0
+	if rng := r.Header.Get("Range"); rng != "" && !f.ignoreRange {
+		fmt.Sscanf(rng, "bytes=%d-", &start)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(f.content)-1, len(f.content)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(f.content)-start))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.Header().Set("Content-Length", strconv.Itoa(len(f.content)))
+	}
+
+	body := f.content[start:]
+	if f.dropAfter > 0 {
+		w.Write(body[:f.dropAfter])
+		f.dropAfter = 0
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.Write(body)
+}
+
+// newFlakyDownloader 启动 flakyServer 并创建使用它的下载器
+func newFlakyDownloader(t *testing.T, server *flakyServer, partialDir string) interfaces.Downloader {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	source := NewSource(settings.Source{Kind: settings.SourceHTTP, Location: ts.URL + "/", Name: "mirror", Timeout: 10 * time.Second, IndexTimeout: 10 * time.Second})
+	return NewDownloaderWithSources([]interfaces.ReleaseSource{source}, Options{Verify: constants.VerifyAuto, PartialDir: partialDir})
+}
+
+// testContent 生成不重复的测试内容
+func testContent(size int) []byte {
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i * 7 % 251)
+	}
+	return content
+}
+
+// partialFiles 返回目录中未完成的下载
+func partialFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+constants.PartialFileSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestDownloadResumesAfterDroppedConnection(t *testing.T) {
+	partialDir := t.TempDir()
+	server := &flakyServer{content: testContent(256 << 10), dropAfter: 100 << 10}
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+
+	if _, err := newFlakyDownloader(t, server, partialDir).Download(context.Background(), "go1.22.3", dest, nil); !errors.IsType(err, errors.ErrDownloadFailed) {
+		t.Fatalf("Download() error = %v, want the dropped connection to fail the download", err)
+	}
+	partials := partialFiles(t, partialDir)
+	if len(partials) != 1 {
+		t.Fatalf("partial downloads = %v, want one", partials)
+	}
+	info, err := os.Stat(partials[0])
+	if err != nil || info.Size() == 0 {
+		t.Fatalf("partial download is empty: %v", err)
+	}
+
+	// 重新运行时从中断处续传
+	result, err := newFlakyDownloader(t, server, partialDir).Download(context.Background(), "go1.22.3", dest, nil)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if want := fmt.Sprintf("bytes=%d-", info.Size()); server.ranges[1] != want {
+		t.Errorf("resumed with Range %q, want %q", server.ranges[1], want)
+	}
+	if data, _ := os.ReadFile(dest); !bytes.Equal(data, server.content) {
+		t.Errorf("resumed download differs from the original (%d of %d bytes)", len(data), len(server.content))
+	}
+	if sum := sha256.Sum256(server.content); result.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("SHA256 = %s, want the checksum of the whole file", result.SHA256)
+	}
+	if partials := partialFiles(t, partialDir); len(partials) != 0 {
+		t.Errorf("partial downloads left behind: %v", partials)
+	}
+}
+
+func TestDownloadRestartsWhenRangeIgnored(t *testing.T) {
+	partialDir := t.TempDir()
+	server := &flakyServer{content: testContent(256 << 10), dropAfter: 100 << 10, ignoreRange: true}
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+
+	if _, err := newFlakyDownloader(t, server, partialDir).Download(context.Background(), "go1.22.3", dest, nil); err == nil {
+		t.Fatal("Download() succeeded, want the dropped connection to fail the download")
+	}
+	if _, err := newFlakyDownloader(t, server, partialDir).Download(context.Background(), "go1.22.3", dest, nil); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if server.ranges[1] == "" {
+		t.Error("second download did not ask to resume")
+	}
+	if data, _ := os.ReadFile(dest); !bytes.Equal(data, server.content) {
+		t.Errorf("restarted download differs from the original (%d of %d bytes)", len(data), len(server.content))
+	}
+}
+
+func TestDownloadDiscardsCorruptPartial(t *testing.T) {
+	partialDir := t.TempDir()
+	server := &flakyServer{content: testContent(64 << 10)}
+	sum := sha256.Sum256(server.content)
+	partial := filepath.Join(partialDir, archiveName("go1.22.3")+"-"+hex.EncodeToString(sum[:])[:16]+constants.PartialFileSuffix)
+	if err := os.WriteFile(partial, bytes.Repeat([]byte{0xff}, 1000), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+	if _, err := newFlakyDownloader(t, server, partialDir).Download(context.Background(), "go1.22.3", dest, nil); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if len(server.ranges) != 2 || server.ranges[0] != "bytes=1000-" || server.ranges[1] != "" {
+		t.Errorf("requests = %q, want a resume followed by a full download", server.ranges)
+	}
+	if data, _ := os.ReadFile(dest); !bytes.Equal(data, server.content) {
+		t.Error("download differs from the original")
+	}
+}
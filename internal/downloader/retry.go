@@ -0,0 +1,118 @@
+package downloader
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+// retry 执行 fn，遇到可恢复的错误时等待一段时间后重试，最多重试 d.retries 次
+// 等待时间按指数退避并加入随机抖动；服务器通过 Retry-After 要求更久时按服务器的要求等待，
+// 要求的时间超过 RetryMaxDelay 时不再重试，由调用方改用其他发布源；ctx 取消后不再重试
+func (d *releaseDownloader) retry(ctx context.Context, what string, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= d.retries || ctx.Err() != nil || !recoverable(err) {
+			return err
+		}
+
+		wait := backoff(d.retryDelay, attempt)
+		if after := retryAfter(err); after > wait {
+			if after > constants.RetryMaxDelay {
+				logger.Warn("%s failed and the server asked to retry in %v, giving up: %v", what, after, err)
+				return err
+			}
+			wait = after
+		}
+
+		logger.Warn("%s failed (attempt %d of %d), retrying in %v: %v", what, attempt+1, d.retries+1, wait.Round(time.Millisecond), err)
+		if err := d.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepContext 等待 wait，ctx 先被取消时提前返回 ctx.Err()
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoff 返回第 attempt 次重试（从 0 开始）前的等待时间
+// 基准时间每次翻倍，不超过 RetryMaxDelay，实际等待其中随机的后一半，避免多个 gx 进程同时重试
+func backoff(base time.Duration, attempt int) time.Duration {
+	delay := constants.RetryMaxDelay
+	if attempt < 16 && base<<attempt < delay {
+		delay = base << attempt
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// retryAfter 返回服务器通过 Retry-After 要求的等待时间，未指定时返回 0
+func retryAfter(err error) time.Duration {
+	var status *statusError
+	if stderrors.As(err, &status) {
+		return status.retryAfter
+	}
+	return 0
+}
+
+// recoverable 判断错误能否通过重试恢复：服务器暂时不可用（5xx、429、408）、连接失败或中断、超时
+// 校验和不匹配、404 等其他状态码、域名不存在、本地文件错误以及取消重试也不会成功
+func recoverable(err error) bool {
+	if err == nil || errors.IsType(err, errors.ErrChecksumMismatch) || errors.IsType(err, errors.ErrCancelled) {
+		return false
+	}
+	if stderrors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var gxErr *errors.Error
+	if stderrors.As(err, &gxErr) && gxErr.IsRecoverable() {
+		return true
+	}
+
+	var status *statusError
+	if stderrors.As(err, &status) {
+		return status.temporary()
+	}
+
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+
+	var opErr *net.OpError
+	var netErr net.Error
+	switch {
+	case stderrors.As(err, &opErr):
+		return true
+	case stderrors.As(err, &netErr) && netErr.Timeout():
+		return true
+	}
+	return stderrors.Is(err, io.ErrUnexpectedEOF) || stderrors.Is(err, syscall.ECONNRESET)
+}
+
+// markRecoverable 错误可以通过重试恢复时将其标记为可恢复
+func markRecoverable(err *errors.Error) *errors.Error {
+	if recoverable(err.Cause) {
+		return err.AsRecoverable()
+	}
+	return err
+}
@@ -0,0 +1,193 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/settings"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// newRetryingDownloader 启动 flakyServer 并创建最多重试 retries 次的下载器
+// 下载器不实际等待，而是记录每次重试前的等待时间
+func newRetryingDownloader(t *testing.T, server *flakyServer, retries int) (*releaseDownloader, *[]time.Duration) {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	source := NewSource(settings.Source{Kind: settings.SourceHTTP, Location: ts.URL + "/", Name: "mirror", Timeout: 10 * time.Second, IndexTimeout: 10 * time.Second})
+	d := NewDownloaderWithSources([]interfaces.ReleaseSource{source}, Options{
+		Verify:     constants.VerifyAuto,
+		PartialDir: t.TempDir(),
+		Retries:    retries,
+		RetryDelay: time.Second,
+	}).(*releaseDownloader)
+
+	var waits []time.Duration
+	d.sleep = func(ctx context.Context, wait time.Duration) error {
+		waits = append(waits, wait)
+		return nil
+	}
+	return d, &waits
+}
+
+func TestDownloadRetriesServerErrors(t *testing.T) {
+	// 版本列表和安装包各失败一次
+	server := &flakyServer{content: testContent(64 << 10), statuses: []int{http.StatusServiceUnavailable}}
+	d, waits := newRetryingDownloader(t, server, 3)
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+
+	if _, err := d.Versions(context.Background(), false); err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	server.statuses = []int{http.StatusBadGateway, http.StatusGatewayTimeout}
+	if _, err := d.Download(context.Background(), "go1.22.3", dest, nil); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if data, _ := os.ReadFile(dest); !bytes.Equal(data, server.content) {
+		t.Errorf("downloaded file differs from the original")
+	}
+
+	// 等待时间指数增长，每次在 [delay/2, delay] 之间
+	if len(*waits) != 3 {
+		t.Fatalf("retried %d times, want 3", len(*waits))
+	}
+	for i, want := range []time.Duration{time.Second, time.Second, 2 * time.Second} {
+		if wait := (*waits)[i]; wait < want/2 || wait > want {
+			t.Errorf("wait %d = %v, want between %v and %v", i, wait, want/2, want)
+		}
+	}
+}
+
+func TestDownloadRetryResumesDroppedConnection(t *testing.T) {
+	server := &flakyServer{content: testContent(256 << 10), dropAfter: 100 << 10}
+	d, waits := newRetryingDownloader(t, server, 1)
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+
+	if _, err := d.Download(context.Background(), "go1.22.3", dest, nil); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if len(*waits) != 1 || len(server.ranges) != 2 || server.ranges[1] == "" {
+		t.Errorf("retries = %d, ranges = %q, want one resumed retry", len(*waits), server.ranges)
+	}
+	if data, _ := os.ReadFile(dest); !bytes.Equal(data, server.content) {
+		t.Errorf("downloaded file differs from the original")
+	}
+}
+
+func TestDownloadCancelled(t *testing.T) {
+	server := &flakyServer{content: testContent(1 << 20)}
+	d, waits := newRetryingDownloader(t, server, 3)
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+
+	// 收到第一段数据后取消，不再重试，已下载的部分保留以便续传
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := d.Download(ctx, "go1.22.3", dest, func(downloaded, total int64) {
+		if downloaded > 0 {
+			cancel()
+		}
+	})
+	if !errors.IsType(err, errors.ErrCancelled) {
+		t.Fatalf("Download() error = %v, want cancelled", err)
+	}
+	if gxErr, ok := err.(*errors.Error); !ok {
+		t.Errorf("Download() error = %T, want *errors.Error", err)
+	} else if _, ok := gxErr.GetContext("partial_download"); !ok {
+		t.Errorf("cancelled download did not keep the partial file")
+	}
+	if len(*waits) != 0 || len(server.ranges) != 1 {
+		t.Errorf("cancelled download was retried: waits = %v, downloads = %d", *waits, len(server.ranges))
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("cancelled download was moved to the destination")
+	}
+
+	// 再次下载时从中断处续传
+	if _, err := d.Download(context.Background(), "go1.22.3", dest, nil); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if len(server.ranges) != 2 || server.ranges[1] == "" {
+		t.Errorf("ranges = %q, want the second download resumed", server.ranges)
+	}
+	if data, _ := os.ReadFile(dest); !bytes.Equal(data, server.content) {
+		t.Errorf("downloaded file differs from the original")
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	server := &flakyServer{content: testContent(1 << 10), statuses: []int{http.StatusTooManyRequests}, retryAfter: "7"}
+	d, waits := newRetryingDownloader(t, server, 3)
+
+	if _, err := d.Versions(context.Background(), false); err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Errorf("waits = %v, want the 7s from Retry-After", *waits)
+	}
+
+	// 服务器要求的等待时间过长时不再重试
+	d, waits = newRetryingDownloader(t, server, 3)
+	server.statuses = []int{http.StatusServiceUnavailable}
+	server.retryAfter = "3600"
+	if _, err := d.Versions(context.Background(), false); err == nil {
+		t.Fatal("Versions() succeeded, want the failure without waiting an hour")
+	}
+	if len(*waits) != 0 {
+		t.Errorf("waits = %v, want no retry", *waits)
+	}
+}
+
+func TestDownloadDoesNotRetryPermanentErrors(t *testing.T) {
+	// 404 不重试
+	server := &flakyServer{content: testContent(1 << 10)}
+	d, waits := newRetryingDownloader(t, server, 3)
+	if _, err := d.Versions(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	server.statuses = []int{http.StatusNotFound}
+	if _, err := d.Download(context.Background(), "go1.22.3", filepath.Join(t.TempDir(), "go.tar.gz"), nil); !errors.IsType(err, errors.ErrDownloadFailed) {
+		t.Errorf("Download() error = %v, want download failed", err)
+	}
+	if len(*waits) != 0 || len(server.statuses) != 0 {
+		t.Errorf("404 was retried %d times", len(*waits))
+	}
+
+	// 校验和不匹配不重试
+	server = &flakyServer{content: testContent(1 << 10), checksum: sha256Hex("other")}
+	d, waits = newRetryingDownloader(t, server, 3)
+	if _, err := d.Download(context.Background(), "go1.22.3", filepath.Join(t.TempDir(), "go.tar.gz"), nil); !errors.IsType(err, errors.ErrChecksumMismatch) {
+		t.Errorf("Download() error = %v, want checksum mismatch", err)
+	}
+	if len(*waits) != 0 || len(server.ranges) != 1 {
+		t.Errorf("checksum mismatch was retried: waits = %v, downloads = %d", *waits, len(server.ranges))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{"Sat, 01 Jun 2024 12:00:30 GMT", 30 * time.Second},
+		{"Sat, 01 Jun 2024 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
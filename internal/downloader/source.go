@@ -0,0 +1,350 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/settings"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// NewSource 根据配置创建发布源
+func NewSource(s settings.Source) interfaces.ReleaseSource {
+	switch s.Kind {
+	case settings.SourceDir:
+		return &dirSource{name: s.Name, dir: s.Location}
+	case settings.SourceIndex:
+		return &indexSource{name: s.Name, path: s.Location}
+	default:
+		return &httpSource{
+			name:        s.Name,
+			baseURL:     s.Location,
+			client:      &http.Client{Timeout: s.Timeout},
+			indexClient: &http.Client{Timeout: s.IndexTimeout},
+		}
+	}
+}
+
+// httpSource 与 go.dev/dl 布局相同的 HTTP(S) 镜像
+type httpSource struct {
+	name        string
+	baseURL     string       // 以 / 结尾
+	client      *http.Client // 下载安装包
+	indexClient *http.Client // 获取版本列表
+}
+
+// Name 返回发布源的名称
+func (s *httpSource) Name() string {
+	return s.name
+}
+
+// Versions 从镜像获取版本列表
+// 默认列表只包含当前支持的两个版本线，all 为 true 时获取包含历史版本的完整列表
+func (s *httpSource) Versions(ctx context.Context, all bool) ([]interfaces.RemoteVersion, error) {
+	apiURL := s.baseURL + constants.VersionsIndexQuery
+	if all {
+		apiURL = s.baseURL + constants.AllVersionsIndexQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.indexClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(apiURL, resp)
+	}
+
+	var versions []interfaces.RemoteVersion
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("failed to parse version list from %s: %w", apiURL, err)
+	}
+	return versions, nil
+}
+
+// URL 返回文件的下载地址
+func (s *httpSource) URL(file interfaces.File) string {
+	return s.baseURL + file.Filename
+}
+
+// Open 开始下载文件，offset 大于 0 时使用 Range 请求续传
+// 服务器忽略 Range（返回 200）、返回的范围与请求不符或拒绝该范围（416）时从头下载
+func (s *httpSource) Open(ctx context.Context, file interfaces.File, offset int64) (*interfaces.FileStream, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL(file), nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return &interfaces.FileStream{Body: resp.Body, Offset: 0, Size: resp.ContentLength}, nil
+
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		if start, total, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && start == offset {
+			return &interfaces.FileStream{Body: resp.Body, Offset: offset, Size: total}, nil
+		}
+		resp.Body.Close()
+		return s.Open(ctx, file, 0)
+
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		return s.Open(ctx, file, 0)
+	}
+
+	resp.Body.Close()
+	return nil, newStatusError(req.URL.String(), resp)
+}
+
+// statusError 服务器返回了非预期的状态码
+type statusError struct {
+	url        string
+	statusCode int
+	retryAfter time.Duration // 服务器通过 Retry-After 要求的等待时间，未指定时为 0
+}
+
+// newStatusError 根据响应创建 statusError
+func newStatusError(url string, resp *http.Response) *statusError {
+	return &statusError{
+		url:        url,
+		statusCode: resp.StatusCode,
+		retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// Error 实现 error 接口
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code from %s: %d", e.url, e.statusCode)
+}
+
+// temporary 报告该状态码是否表示服务器暂时不可用，稍后重试可能成功
+// 404 等其他 4xx 状态码重试也不会改变结果
+func (e *statusError) temporary() bool {
+	switch e.statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+		return false
+	}
+	return e.statusCode >= 500
+}
+
+// parseRetryAfter 解析 Retry-After 头，值可以是秒数或 HTTP 日期，无法解析或已过期时返回 0
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// parseContentRange 解析 "bytes 100-999/1000" 形式的 Content-Range，总大小未知（*）时为 -1
+func parseContentRange(value string) (start int64, total int64, ok bool) {
+	rest, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	span, size, found := strings.Cut(rest, "/")
+	if !found {
+		return 0, 0, false
+	}
+	first, _, found := strings.Cut(span, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	total = -1
+	if size != "*" {
+		if total, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, total, true
+}
+
+// indexSource 本地 JSON 版本列表，格式与 go.dev/dl/?mode=json 相同，文件与列表位于同一目录
+type indexSource struct {
+	name string
+	path string
+}
+
+// Name 返回发布源的名称
+func (s *indexSource) Name() string {
+	return s.name
+}
+
+// Versions 读取版本列表，不区分默认列表和完整列表
+func (s *indexSource) Versions(ctx context.Context, all bool) ([]interfaces.RemoteVersion, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []interfaces.RemoteVersion
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse version list %s: %w", s.path, err)
+	}
+	return versions, nil
+}
+
+// URL 返回文件的 file:// 地址
+func (s *indexSource) URL(file interfaces.File) string {
+	return fileURL(filepath.Join(filepath.Dir(s.path), file.Filename))
+}
+
+// Open 打开文件
+func (s *indexSource) Open(ctx context.Context, file interfaces.File, offset int64) (*interfaces.FileStream, error) {
+	return openLocal(filepath.Join(filepath.Dir(s.path), file.Filename), offset)
+}
+
+// dirSource 存放官方安装包的本地目录，例如挂载的共享目录
+// 版本列表由目录中符合官方命名的文件生成，安装包旁的 .sha256 文件提供校验和
+type dirSource struct {
+	name string
+	dir  string
+}
+
+// Name 返回发布源的名称
+func (s *dirSource) Name() string {
+	return s.name
+}
+
+// Versions 扫描目录生成版本列表，按版本从新到旧排列
+func (s *dirSource) Versions(ctx context.Context, all bool) ([]interfaces.RemoteVersion, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]*interfaces.RemoteVersion)
+	for _, entry := range entries {
+		parsed, ok := goversion.ParseFilename(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		file := interfaces.File{
+			Filename: entry.Name(),
+			OS:       parsed.OS,
+			Arch:     parsed.Arch,
+			Size:     info.Size(),
+			SHA256:   readChecksum(filepath.Join(s.dir, entry.Name()+constants.ChecksumFileSuffix)),
+		}
+
+		v, ok := byVersion[parsed.Version]
+		if !ok {
+			version, err := goversion.Parse(parsed.Version)
+			if err != nil {
+				continue
+			}
+			v = &interfaces.RemoteVersion{Version: parsed.Version, Stable: !version.IsPrerelease()}
+			byVersion[parsed.Version] = v
+		}
+		v.Files = append(v.Files, file)
+	}
+
+	names := make([]string, 0, len(byVersion))
+	for name := range byVersion {
+		names = append(names, name)
+	}
+	goversion.Sort(names)
+
+	// 与官方列表一致，最新的版本在前
+	versions := make([]interfaces.RemoteVersion, 0, len(names))
+	for i := len(names) - 1; i >= 0; i-- {
+		versions = append(versions, *byVersion[names[i]])
+	}
+	return versions, nil
+}
+
+// URL 返回文件的 file:// 地址
+func (s *dirSource) URL(file interfaces.File) string {
+	return fileURL(filepath.Join(s.dir, file.Filename))
+}
+
+// Open 打开文件
+func (s *dirSource) Open(ctx context.Context, file interfaces.File, offset int64) (*interfaces.FileStream, error) {
+	return openLocal(filepath.Join(s.dir, file.Filename), offset)
+}
+
+// readChecksum 读取 .sha256 文件中的校验和，文件不存在或为空时返回空字符串
+func readChecksum(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// openLocal 打开本地文件并定位到 offset，offset 超出文件大小时从头读取
+func openLocal(path string, offset int64) (*interfaces.FileStream, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if offset > info.Size() {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &interfaces.FileStream{Body: file, Offset: offset, Size: info.Size()}, nil
+}
+
+// fileURL 将本地路径转换为 file:// 地址
+func fileURL(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return "file://" + path
+}
@@ -0,0 +1,87 @@
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+// SwitchCurrent 将 current 指针原子地切换到指定 GOROOT
+func (m *manager) SwitchCurrent(goRoot string) error {
+	if goRoot == "" {
+		return errors.ErrInvalidInput.WithMessage("GOROOT cannot be empty")
+	}
+
+	// Windows 上创建符号链接通常需要管理员权限，继续使用持久化环境变量
+	if m.platform.GetOS() == constants.OSWindows {
+		if err := m.SetGoRoot(goRoot); err != nil {
+			return err
+		}
+		return m.UpdatePath(goRoot)
+	}
+
+	if err := m.swapCurrentLink(goRoot); err != nil {
+		return err
+	}
+
+	// shell 配置文件只在第一次切换时写入，之后始终引用 current
+	if m.shellReferencesCurrent() {
+		logger.Debug("Shell configuration already references %s", m.currentPath)
+		os.Setenv(constants.EnvGoRoot, m.currentPath)
+		return nil
+	}
+
+	logger.Info("Pointing shell configuration at %s", m.currentPath)
+	if err := m.SetGoRoot(m.currentPath); err != nil {
+		return err
+	}
+	return m.UpdatePath(m.currentPath)
+}
+
+// swapCurrentLink 原子地替换 current 符号链接
+// 先在同一目录创建临时链接，再通过 rename 覆盖，读取方不会看到链接缺失的状态
+func (m *manager) swapCurrentLink(goRoot string) error {
+	if _, err := os.Stat(goRoot); err != nil {
+		return errors.ErrInvalidInput.
+			WithCause(err).
+			WithMessage(fmt.Sprintf("GOROOT path does not exist: %s", goRoot)).
+			WithContext("path", goRoot)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.currentPath), 0755); err != nil {
+		return errors.ErrEnvironmentSetupFailed.WithCause(err).WithMessage("failed to create config directory")
+	}
+
+	// 旧版本可能在此位置留下了真实目录，拒绝覆盖
+	if info, err := os.Lstat(m.currentPath); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		return errors.ErrEnvironmentSetupFailed.
+			WithMessage(fmt.Sprintf("%s exists and is not a symlink", m.currentPath)).
+			WithContext("path", m.currentPath)
+	}
+
+	// 临时链接名包含 PID，避免并发切换互相干扰
+	tmpLink := fmt.Sprintf("%s.tmp-%d", m.currentPath, os.Getpid())
+	os.Remove(tmpLink)
+
+	if err := os.Symlink(goRoot, tmpLink); err != nil {
+		return errors.ErrEnvironmentSetupFailed.
+			WithCause(err).
+			WithMessage("failed to create current link").
+			WithContext("target", goRoot)
+	}
+
+	if err := os.Rename(tmpLink, m.currentPath); err != nil {
+		os.Remove(tmpLink)
+		return errors.ErrEnvironmentSetupFailed.
+			WithCause(err).
+			WithMessage("failed to replace current link").
+			WithContext("path", m.currentPath)
+	}
+
+	logger.Info("Current link %s now points to %s", m.currentPath, goRoot)
+	return nil
+}
@@ -0,0 +1,170 @@
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kawaiirei0/gx/internal/platform"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+// newCurrentTestManager 创建使用临时 HOME 和 GX_HOME 的环境管理器
+// 返回管理器和一个已存在的 ~/.bashrc 路径
+func newCurrentTestManager(t *testing.T) (*manager, string) {
+	t.Helper()
+
+	if runtime.GOOS == constants.OSWindows {
+		t.Skip("the current link is not used on Windows")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SHELL", "/bin/bash")
+	t.Setenv(constants.EnvGxHome, filepath.Join(home, ".gx"))
+	// SwitchCurrent 会修改当前进程的 GOROOT 和 PATH，测试结束后恢复
+	t.Setenv(constants.EnvGoRoot, os.Getenv(constants.EnvGoRoot))
+	t.Setenv(constants.EnvPath, os.Getenv(constants.EnvPath))
+
+	bashrc := filepath.Join(home, ".bashrc")
+	if err := os.WriteFile(bashrc, []byte("# user settings\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewManager(platform.NewAdapter()).(*manager), bashrc
+}
+
+// goRoots 在临时目录中创建指定名称的 GOROOT 目录
+func goRoots(t *testing.T, names ...string) []string {
+	t.Helper()
+
+	root := t.TempDir()
+	var dirs []string
+	for _, name := range names {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Join(dir, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+func TestSwitchCurrentReplacesLink(t *testing.T) {
+	m, _ := newCurrentTestManager(t)
+	dirs := goRoots(t, "go1.21.5", "go1.22.3")
+
+	for _, dir := range dirs {
+		if err := m.SwitchCurrent(dir); err != nil {
+			t.Fatalf("SwitchCurrent(%s) error = %v", dir, err)
+		}
+
+		target, err := os.Readlink(m.currentPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if target != dir {
+			t.Errorf("current -> %s, want %s", target, dir)
+		}
+	}
+
+	// 替换完成后不应留下临时链接
+	leftovers, err := filepath.Glob(m.currentPath + ".tmp-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leftovers) != 0 {
+		t.Errorf("temporary links left behind: %v", leftovers)
+	}
+}
+
+func TestSwitchCurrentRefusesNonSymlink(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func(path string) error
+	}{
+		{
+			name:   "directory",
+			create: func(path string) error { return os.MkdirAll(filepath.Join(path, "bin"), 0755) },
+		},
+		{
+			name:   "file",
+			create: func(path string) error { return os.WriteFile(path, []byte("keep me"), 0644) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newCurrentTestManager(t)
+			dir := goRoots(t, "go1.22.3")[0]
+
+			if err := os.MkdirAll(filepath.Dir(m.currentPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.create(m.currentPath); err != nil {
+				t.Fatal(err)
+			}
+			before, err := os.Lstat(m.currentPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = m.SwitchCurrent(dir)
+			if !errors.IsType(err, errors.ErrEnvironmentSetupFailed) {
+				t.Fatalf("SwitchCurrent() error = %v, want ErrEnvironmentSetupFailed", err)
+			}
+
+			after, err := os.Lstat(m.currentPath)
+			if err != nil {
+				t.Fatalf("current was removed: %v", err)
+			}
+			if after.Mode() != before.Mode() || after.Size() != before.Size() {
+				t.Errorf("current was modified: mode %v -> %v", before.Mode(), after.Mode())
+			}
+		})
+	}
+}
+
+func TestSwitchCurrentWritesShellConfigOnce(t *testing.T) {
+	m, bashrc := newCurrentTestManager(t)
+	dirs := goRoots(t, "go1.21.5", "go1.22.3")
+
+	if err := m.SwitchCurrent(dirs[0]); err != nil {
+		t.Fatalf("SwitchCurrent() error = %v", err)
+	}
+
+	data, err := os.ReadFile(bashrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, key := range []string{constants.EnvGoRoot, constants.EnvPath} {
+		if n := strings.Count(content, "# gx managed "+key+"\n"); n != 1 {
+			t.Errorf("%s entry written %d times:\n%s", key, n, content)
+		}
+	}
+	if !strings.Contains(content, m.buildExportLine(constants.EnvGoRoot, m.currentPath)) {
+		t.Errorf("GOROOT does not point at %s:\n%s", m.currentPath, content)
+	}
+
+	// 用户在 gx 的条目之后追加的内容：如果再次写入，gx 的条目会被移到末尾
+	content += "alias gt='go test ./...'\n"
+	if err := os.WriteFile(bashrc, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.SwitchCurrent(dirs[1]); err != nil {
+		t.Fatalf("SwitchCurrent() error = %v", err)
+	}
+
+	data, err = os.ReadFile(bashrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("second switch rewrote %s:\n%s", bashrc, data)
+	}
+}
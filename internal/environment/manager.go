@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/kawaiirei0/gx/internal/gxhome"
 	"github.com/kawaiirei0/gx/internal/logger"
 	"github.com/kawaiirei0/gx/pkg/constants"
 	"github.com/kawaiirei0/gx/pkg/errors"
@@ -16,16 +17,22 @@ import (
 type manager struct {
 	platform interfaces.PlatformAdapter
 	backupPath string
+	currentPath string
+	versionsDir string
 }
 
 // NewManager 创建新的环境变量管理器
 func NewManager(platform interfaces.PlatformAdapter) interfaces.EnvironmentManager {
-	homeDir, _ := platform.GetHomeDir()
-	backupPath := filepath.Join(homeDir, constants.ConfigDir, "env_backup.json")
+	gxHome, _ := gxhome.Dir()
+	backupPath := filepath.Join(gxHome, "env_backup.json")
+	currentPath := filepath.Join(gxHome, constants.CurrentLinkName)
+	versionsDir := filepath.Join(gxHome, constants.VersionsDirName)
 	
 	return &manager{
 		platform: platform,
 		backupPath: backupPath,
+		currentPath: currentPath,
+		versionsDir: versionsDir,
 	}
 }
 
@@ -81,8 +88,47 @@ func (m *manager) SetGoPath(path string) error {
 
 // UpdatePath 更新 PATH 环境变量，添加 Go bin 目录
 func (m *manager) UpdatePath(goRoot string) error {
+	// 获取当前 PATH
+	currentPath := os.Getenv(constants.EnvPath)
+
+	newPath, err := m.buildPath(goRoot, currentPath)
+	if err != nil {
+		return err
+	}
+
+	// 持久化 PATH 更新
+	if err := m.setEnvPersistent(constants.EnvPath, newPath); err != nil {
+		return errors.Wrap(err, "ENVIRONMENT_SETUP_FAILED", "failed to update PATH").
+			WithContext("old_path", currentPath).
+			WithContext("new_path", newPath).
+			WithContext("go_bin", filepath.Join(goRoot, "bin"))
+	}
+	
+	return nil
+}
+
+// SessionEnv 计算指向指定 GOROOT 的会话级环境变量
+// 与 SetGoRoot/UpdatePath 使用相同的路径逻辑，但不做任何持久化修改
+func (m *manager) SessionEnv(goRoot string) (map[string]string, error) {
 	if goRoot == "" {
-		return errors.ErrInvalidInput.WithMessage("GOROOT cannot be empty")
+		return nil, errors.ErrInvalidInput.WithMessage("GOROOT cannot be empty")
+	}
+
+	newPath, err := m.buildPath(goRoot, os.Getenv(constants.EnvPath))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		constants.EnvGoRoot: m.platform.NormalizePath(goRoot),
+		constants.EnvPath:   newPath,
+	}, nil
+}
+
+// buildPath 构建新的 PATH：移除旧的 gx 管理的 Go bin 目录，并将新的 bin 目录放在开头
+func (m *manager) buildPath(goRoot string, currentPath string) (string, error) {
+	if goRoot == "" {
+		return "", errors.ErrInvalidInput.WithMessage("GOROOT cannot be empty")
 	}
 
 	// 构建 Go bin 目录路径
@@ -90,15 +136,12 @@ func (m *manager) UpdatePath(goRoot string) error {
 
 	// 验证 bin 目录存在
 	if _, err := os.Stat(goBinPath); os.IsNotExist(err) {
-		return errors.ErrInvalidInput.
+		return "", errors.ErrInvalidInput.
 			WithMessage(fmt.Sprintf("Go bin directory does not exist: %s", goBinPath)).
 			WithContext("go_root", goRoot).
 			WithContext("bin_path", goBinPath)
 	}
 
-	// 获取当前 PATH
-	currentPath := os.Getenv(constants.EnvPath)
-
 	// 检查是否已经在 PATH 中
 	pathSeparator := m.getPathSeparator()
 	paths := strings.Split(currentPath, pathSeparator)
@@ -107,25 +150,15 @@ func (m *manager) UpdatePath(goRoot string) error {
 	var newPaths []string
 	for _, p := range paths {
 		normalizedP := m.platform.NormalizePath(p)
-		// 跳过包含 .gx/versions 的路径
-		if !strings.Contains(normalizedP, constants.DefaultInstallDir) {
+		// 跳过 gx 安装目录下的路径以及已存在的同一 bin 目录
+		if !strings.HasPrefix(normalizedP, m.platform.NormalizePath(m.versionsDir)) && normalizedP != m.platform.NormalizePath(goBinPath) {
 			newPaths = append(newPaths, p)
 		}
 	}
 
 	// 将新的 Go bin 路径添加到开头
 	newPaths = append([]string{goBinPath}, newPaths...)
-	newPath := strings.Join(newPaths, pathSeparator)
-
-	// 持久化 PATH 更新
-	if err := m.setEnvPersistent(constants.EnvPath, newPath); err != nil {
-		return errors.Wrap(err, "ENVIRONMENT_SETUP_FAILED", "failed to update PATH").
-			WithContext("old_path", currentPath).
-			WithContext("new_path", newPath).
-			WithContext("go_bin", goBinPath)
-	}
-	
-	return nil
+	return strings.Join(newPaths, pathSeparator), nil
 }
 
 // GetGoRoot 获取当前 GOROOT
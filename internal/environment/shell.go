@@ -0,0 +1,130 @@
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+// 支持生成环境变量脚本的 shell
+const (
+	ShellBash       = "bash"
+	ShellZsh        = "zsh"
+	ShellFish       = "fish"
+	ShellSh         = "sh"
+	ShellPowerShell = "powershell"
+)
+
+// SupportedShells 返回支持的 shell 列表
+func SupportedShells() []string {
+	return []string{ShellBash, ShellZsh, ShellFish, ShellSh, ShellPowerShell}
+}
+
+// DetectShell 根据 SHELL 环境变量推断当前 shell
+// 无法识别时 Unix 返回 sh，Windows 返回 powershell
+func DetectShell() string {
+	name := strings.TrimSuffix(filepath.Base(os.Getenv("SHELL")), ".exe")
+	for _, shell := range SupportedShells() {
+		if name == shell {
+			return shell
+		}
+	}
+
+	if os.PathSeparator == '\\' {
+		return ShellPowerShell
+	}
+	return ShellSh
+}
+
+// FormatExports 将环境变量格式化为可以被指定 shell eval 的脚本
+func FormatExports(shell string, vars map[string]string) (string, error) {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		value := vars[key]
+
+		switch shell {
+		case ShellBash, ShellZsh, ShellSh:
+			fmt.Fprintf(&b, "export %s=%s\n", key, quotePosix(value))
+
+		case ShellFish:
+			// fish 中 PATH 是列表，需要逐项传入
+			if key == constants.EnvPath {
+				var parts []string
+				for _, p := range filepath.SplitList(value) {
+					parts = append(parts, quotePosix(p))
+				}
+				fmt.Fprintf(&b, "set -gx %s %s;\n", key, strings.Join(parts, " "))
+			} else {
+				fmt.Fprintf(&b, "set -gx %s %s;\n", key, quotePosix(value))
+			}
+
+		case ShellPowerShell:
+			fmt.Fprintf(&b, "$env:%s = '%s'\n", key, strings.ReplaceAll(value, "'", "''"))
+
+		default:
+			return "", errors.ErrInvalidInput.
+				WithMessage(fmt.Sprintf("unsupported shell: %s (supported: %s)", shell, strings.Join(SupportedShells(), ", "))).
+				WithContext("shell", shell)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// MergeEnv 用 vars 覆盖 base（KEY=VALUE 形式）中的同名变量
+func MergeEnv(base []string, vars map[string]string) []string {
+	merged := make([]string, 0, len(base)+len(vars))
+	for _, entry := range base {
+		key := entry
+		if idx := strings.Index(entry, "="); idx >= 0 {
+			key = entry[:idx]
+		}
+		if _, overridden := lookupEnvKey(vars, key); overridden {
+			continue
+		}
+		merged = append(merged, entry)
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		merged = append(merged, key+"="+vars[key])
+	}
+
+	return merged
+}
+
+// lookupEnvKey 查找环境变量名（Windows 上不区分大小写）
+func lookupEnvKey(vars map[string]string, key string) (string, bool) {
+	if value, ok := vars[key]; ok {
+		return value, true
+	}
+	if os.PathSeparator == '\\' {
+		for k, v := range vars {
+			if strings.EqualFold(k, key) {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
+
+// quotePosix 使用单引号转义值，适用于 POSIX shell 和 fish
+func quotePosix(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,85 @@
+package environment
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// trickyVars 包含单引号、双引号、空格和 $ 的环境变量
+var trickyVars = map[string]string{
+	"GOROOT":     `/opt/it's "my" go`,
+	"GX_VERSION": "1.22.3",
+	"PATH":       "/opt/go $HOME/bin" + string(filepath.ListSeparator) + "/usr/bin",
+}
+
+func TestFormatExports(t *testing.T) {
+	sep := string(filepath.ListSeparator)
+	posix := "export GOROOT='/opt/it'\\''s \"my\" go'\n" +
+		"export GX_VERSION='1.22.3'\n" +
+		"export PATH='/opt/go $HOME/bin" + sep + "/usr/bin'\n"
+
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{shell: ShellBash, want: posix},
+		{shell: ShellZsh, want: posix},
+		{shell: ShellSh, want: posix},
+		{
+			// PATH 作为列表逐项传入
+			shell: ShellFish,
+			want: "set -gx GOROOT '/opt/it'\\''s \"my\" go';\n" +
+				"set -gx GX_VERSION '1.22.3';\n" +
+				"set -gx PATH '/opt/go $HOME/bin' '/usr/bin';\n",
+		},
+		{
+			// 单引号字符串中 $ 不展开，单引号写作两个单引号
+			shell: ShellPowerShell,
+			want: "$env:GOROOT = '/opt/it''s \"my\" go'\n" +
+				"$env:GX_VERSION = '1.22.3'\n" +
+				"$env:PATH = '/opt/go $HOME/bin" + sep + "/usr/bin'\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			got, err := FormatExports(tt.shell, trickyVars)
+			if err != nil {
+				t.Fatalf("FormatExports() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatExports() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatExportsUnsupportedShell(t *testing.T) {
+	if _, err := FormatExports("tcsh", trickyVars); err == nil {
+		t.Fatal("FormatExports() accepted an unsupported shell")
+	}
+}
+
+func TestFormatExportsPosixRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+
+	script, err := FormatExports(ShellSh, trickyVars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 在 shell 中执行脚本后，变量值应与原值完全相同
+	for _, key := range []string{"GOROOT", "PATH"} {
+		out, err := exec.Command(sh, "-c", script+`printf '%s' "$`+key+`"`).Output()
+		if err != nil {
+			t.Fatalf("sh failed: %v", err)
+		}
+		if string(out) != trickyVars[key] {
+			t.Errorf("%s = %q after eval, want %q", key, out, trickyVars[key])
+		}
+	}
+}
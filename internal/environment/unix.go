@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/kawaiirei0/gx/pkg/constants"
 	"github.com/kawaiirei0/gx/pkg/errors"
 )
 
@@ -30,28 +31,7 @@ func (m *manager) setEnvUnix(key, value string) error {
 		}
 	}
 
-	// 检测使用的 shell
-	shell := os.Getenv("SHELL")
-	var rcFiles []string
-
-	if strings.Contains(shell, "zsh") {
-		rcFiles = []string{
-			filepath.Join(homeDir, ".zshrc"),
-			filepath.Join(homeDir, ".zprofile"),
-		}
-	} else if strings.Contains(shell, "bash") {
-		rcFiles = []string{
-			filepath.Join(homeDir, ".bashrc"),
-			filepath.Join(homeDir, ".bash_profile"),
-			filepath.Join(homeDir, ".profile"),
-		}
-	} else {
-		// 默认尝试常见的配置文件
-		rcFiles = []string{
-			filepath.Join(homeDir, ".profile"),
-			filepath.Join(homeDir, ".bashrc"),
-		}
-	}
+	rcFiles := shellRCFiles(homeDir)
 
 	// 尝试更新每个存在的配置文件
 	updated := false
@@ -76,6 +56,56 @@ func (m *manager) setEnvUnix(key, value string) error {
 	return nil
 }
 
+// shellRCFiles 根据当前 shell 返回需要维护的配置文件列表
+func shellRCFiles(homeDir string) []string {
+	shell := os.Getenv("SHELL")
+
+	if strings.Contains(shell, "zsh") {
+		return []string{
+			filepath.Join(homeDir, ".zshrc"),
+			filepath.Join(homeDir, ".zprofile"),
+		}
+	}
+
+	if strings.Contains(shell, "bash") {
+		return []string{
+			filepath.Join(homeDir, ".bashrc"),
+			filepath.Join(homeDir, ".bash_profile"),
+			filepath.Join(homeDir, ".profile"),
+		}
+	}
+
+	// 默认尝试常见的配置文件
+	return []string{
+		filepath.Join(homeDir, ".profile"),
+		filepath.Join(homeDir, ".bashrc"),
+	}
+}
+
+// shellReferencesCurrent 检查 shell 配置文件是否已经通过 current 链接设置 GOROOT 和 PATH
+func (m *manager) shellReferencesCurrent() bool {
+	homeDir, err := m.platform.GetHomeDir()
+	if err != nil {
+		return false
+	}
+
+	goRootLine := m.buildExportLine(constants.EnvGoRoot, m.currentPath)
+	pathLine := m.buildExportLine(constants.EnvPath, filepath.Join(m.currentPath, "bin"))
+
+	for _, rcFile := range shellRCFiles(homeDir) {
+		data, err := os.ReadFile(rcFile)
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		if strings.Contains(content, goRootLine) && strings.Contains(content, pathLine) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // updateShellRC 更新 shell 配置文件中的环境变量
 func (m *manager) updateShellRC(rcFile, key, value string) error {
 	// 读取现有内容
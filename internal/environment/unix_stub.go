@@ -10,3 +10,8 @@ import (
 func (m *manager) setEnvUnix(key, value string) error {
 	return errors.ErrPlatformNotSupported.WithMessage("Unix environment management not available on Windows")
 }
+
+// shellReferencesCurrent is a stub for Windows builds
+func (m *manager) shellReferencesCurrent() bool {
+	return false
+}
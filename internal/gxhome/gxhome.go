@@ -0,0 +1,121 @@
+// Package gxhome 解析 gx 的根目录（GX_HOME）以及其中各文件的位置
+//
+// 配置、日志、版本、shims、current 链接、环境变量备份等所有状态都位于根目录下，
+// 各子系统都通过本包获取路径，因此只需设置 GX_HOME 或 --config 即可整体迁移。
+// 唯一的例外是安装包缓存（CacheDir），它固定位于 ~/.gx 下，由多个根目录共享。
+//
+// 根目录的解析顺序：GX_HOME 环境变量 > --config 指定文件所在的目录 > ~/.gx
+// 配置文件的解析顺序：--config > GX_CONFIG 环境变量 > <根目录>/config.json
+package gxhome
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kawaiirei0/gx/pkg/constants"
+)
+
+var (
+	mu         sync.RWMutex
+	configFlag string // --config 指定的配置文件（绝对路径）
+)
+
+// SetConfigFile 使用命令行 --config 指定的配置文件
+// 未设置 GX_HOME 时，根目录随之变为该文件所在的目录
+func SetConfigFile(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	configFlag = abs
+	mu.Unlock()
+	return nil
+}
+
+// Dir 返回 gx 根目录
+func Dir() (string, error) {
+	if dir := os.Getenv(constants.EnvGxHome); dir != "" {
+		return filepath.Abs(dir)
+	}
+
+	mu.RLock()
+	flag := configFlag
+	mu.RUnlock()
+	if flag != "" {
+		return filepath.Dir(flag), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, constants.ConfigDir), nil
+}
+
+// Path 返回根目录下的路径
+func Path(elem ...string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{dir}, elem...)...), nil
+}
+
+// ConfigFile 返回配置文件路径
+func ConfigFile() (string, error) {
+	mu.RLock()
+	flag := configFlag
+	mu.RUnlock()
+	if flag != "" {
+		return flag, nil
+	}
+
+	if path := os.Getenv(constants.EnvGxConfig); path != "" {
+		return filepath.Abs(path)
+	}
+
+	return Path(constants.ConfigFileName)
+}
+
+// VersionsDir 返回默认的版本安装目录
+func VersionsDir() (string, error) {
+	return Path(constants.VersionsDirName)
+}
+
+// DownloadsDir 返回保存未完成下载的目录
+func DownloadsDir() (string, error) {
+	return Path(constants.DownloadsDirName)
+}
+
+// CacheDir 返回默认的安装包缓存目录 ~/.gx/cache
+// 缓存按校验和寻址，内容与根目录无关，因此不随 GX_HOME 和 --config 变化
+func CacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, constants.ConfigDir, constants.CacheDirName), nil
+}
+
+// Export 将解析结果写入当前进程的环境变量
+// 子进程（gx exec、gx shell、shims 以及其中再次调用的 gx）因此使用同一个根目录和配置文件
+func Export() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.Setenv(constants.EnvGxHome, dir); err != nil {
+		return err
+	}
+
+	mu.RLock()
+	flag := configFlag
+	mu.RUnlock()
+	if flag != "" {
+		return os.Setenv(constants.EnvGxConfig, flag)
+	}
+	return nil
+}
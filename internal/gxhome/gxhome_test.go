@@ -0,0 +1,128 @@
+package gxhome
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolution(t *testing.T) {
+	home := t.TempDir()
+	custom := filepath.Join(t.TempDir(), "gx")
+	flagDir := filepath.Join(t.TempDir(), "ci")
+
+	tests := []struct {
+		name       string
+		gxHome     string
+		gxConfig   string
+		configFlag string
+		wantDir    string
+		wantConfig string
+	}{
+		{
+			name:       "default",
+			wantDir:    filepath.Join(home, ".gx"),
+			wantConfig: filepath.Join(home, ".gx", "config.json"),
+		},
+		{
+			name:       "GX_HOME",
+			gxHome:     custom,
+			wantDir:    custom,
+			wantConfig: filepath.Join(custom, "config.json"),
+		},
+		{
+			name:       "GX_CONFIG only moves the config file",
+			gxConfig:   filepath.Join(flagDir, "gx.json"),
+			wantDir:    filepath.Join(home, ".gx"),
+			wantConfig: filepath.Join(flagDir, "gx.json"),
+		},
+		{
+			name:       "--config moves the home",
+			configFlag: filepath.Join(flagDir, "config.json"),
+			wantDir:    flagDir,
+			wantConfig: filepath.Join(flagDir, "config.json"),
+		},
+		{
+			name:       "GX_HOME wins over the --config directory",
+			gxHome:     custom,
+			configFlag: filepath.Join(flagDir, "config.json"),
+			wantDir:    custom,
+			wantConfig: filepath.Join(flagDir, "config.json"),
+		},
+		{
+			name:       "--config wins over GX_CONFIG",
+			gxConfig:   filepath.Join(custom, "other.json"),
+			configFlag: filepath.Join(flagDir, "config.json"),
+			wantDir:    flagDir,
+			wantConfig: filepath.Join(flagDir, "config.json"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+			t.Setenv("GX_HOME", tt.gxHome)
+			t.Setenv("GX_CONFIG", tt.gxConfig)
+
+			configFlag = ""
+			t.Cleanup(func() { configFlag = "" })
+			if tt.configFlag != "" {
+				if err := SetConfigFile(tt.configFlag); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dir, err := Dir()
+			if err != nil {
+				t.Fatalf("Dir() error = %v", err)
+			}
+			if dir != tt.wantDir {
+				t.Errorf("Dir() = %q, want %q", dir, tt.wantDir)
+			}
+
+			config, err := ConfigFile()
+			if err != nil {
+				t.Fatalf("ConfigFile() error = %v", err)
+			}
+			if config != tt.wantConfig {
+				t.Errorf("ConfigFile() = %q, want %q", config, tt.wantConfig)
+			}
+
+			versions, _ := VersionsDir()
+			if want := filepath.Join(tt.wantDir, "versions"); versions != want {
+				t.Errorf("VersionsDir() = %q, want %q", versions, want)
+			}
+
+			// 缓存由所有根目录共享
+			cache, _ := CacheDir()
+			if want := filepath.Join(home, ".gx", "cache"); cache != want {
+				t.Errorf("CacheDir() = %q, want %q", cache, want)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	flagDir := t.TempDir()
+	t.Setenv("GX_HOME", "")
+	t.Setenv("GX_CONFIG", "")
+
+	configFlag = ""
+	t.Cleanup(func() { configFlag = "" })
+	if err := SetConfigFile(filepath.Join(flagDir, "config.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Export(); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	// 子进程看不到 --config，只能通过环境变量得到相同的结果
+	configFlag = ""
+	if dir, _ := Dir(); dir != flagDir {
+		t.Errorf("Dir() after Export() = %q, want %q", dir, flagDir)
+	}
+	if config, _ := ConfigFile(); config != filepath.Join(flagDir, "config.json") {
+		t.Errorf("ConfigFile() after Export() = %q", config)
+	}
+}
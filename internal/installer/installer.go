@@ -4,17 +4,18 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"strings"
 
 	"github.com/kawaiirei0/gx/pkg/constants"
 	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
 	"github.com/kawaiirei0/gx/pkg/interfaces"
 )
 
@@ -31,7 +32,26 @@ func NewInstaller(platform interfaces.PlatformAdapter) interfaces.Installer {
 }
 
 // Install 安装指定版本到目标路径
-func (i *goInstaller) Install(archivePath string, version string, destPath string) error {
+func (i *goInstaller) Install(ctx context.Context, archivePath string, version string, destPath string) error {
+	if err := i.Extract(ctx, archivePath, destPath); err != nil {
+		return err
+	}
+
+	// 验证安装
+	if err := i.Verify(destPath, version); err != nil {
+		// 验证失败，清理安装目录
+		errors.SafeRemoveAll(destPath)
+		return errors.Wrap(err, "INSTALL_FAILED", "installation verification failed").
+			WithContext("dest_path", destPath).
+			WithContext("version", version)
+	}
+
+	return nil
+}
+
+// Extract 将安装包或源码包解压到目标路径
+// 每解压一个文件前检查 ctx，取消时删除目标目录并返回 ErrCancelled
+func (i *goInstaller) Extract(ctx context.Context, archivePath string, destPath string) error {
 	// 创建恢复管理器
 	recovery := errors.NewRecoveryManager()
 	
@@ -43,13 +63,13 @@ func (i *goInstaller) Install(archivePath string, version string, destPath strin
 			WithContext("dest_path", destPath)
 	}
 
-	// 注册清理函数：如果安装失败，删除目标目录
+	// 注册清理函数：如果解压失败，删除目标目录
 	errors.EnsureDirectoryCleanup(recovery, destPath)
 
 	// 根据文件扩展名选择解压方法
 	var extractErr error
 	if strings.HasSuffix(archivePath, constants.ArchiveExtZip) {
-		extractErr = i.extractZip(archivePath, destPath)
+		extractErr = i.extractZip(ctx, archivePath, destPath)
 		if extractErr != nil {
 			extractErr = errors.ErrInstallFailed.
 				WithCause(extractErr).
@@ -58,7 +78,7 @@ func (i *goInstaller) Install(archivePath string, version string, destPath strin
 				WithContext("dest_path", destPath)
 		}
 	} else if strings.HasSuffix(archivePath, constants.ArchiveExtTarGz) {
-		extractErr = i.extractTarGz(archivePath, destPath)
+		extractErr = i.extractTarGz(ctx, archivePath, destPath)
 		if extractErr != nil {
 			extractErr = errors.ErrInstallFailed.
 				WithCause(extractErr).
@@ -75,25 +95,21 @@ func (i *goInstaller) Install(archivePath string, version string, destPath strin
 	if extractErr != nil {
 		// 执行清理
 		recovery.Cleanup()
+		if ctx.Err() != nil {
+			return errors.ErrCancelled.
+				WithMessage("extraction was interrupted").
+				WithContext("archive_path", archivePath)
+		}
 		return extractErr
 	}
 
-	// 验证安装
-	if err := i.Verify(destPath, version); err != nil {
-		// 验证失败，清理安装目录
-		recovery.Cleanup()
-		return errors.Wrap(err, "INSTALL_FAILED", "installation verification failed").
-			WithContext("dest_path", destPath).
-			WithContext("version", version)
-	}
-
-	// 安装成功，清除清理函数（不需要清理）
+	// 解压成功，清除清理函数（不需要清理）
 	recovery.Clear()
 	return nil
 }
 
 // extractZip 解压 ZIP 文件
-func (i *goInstaller) extractZip(archivePath string, destPath string) error {
+func (i *goInstaller) extractZip(ctx context.Context, archivePath string, destPath string) error {
 	reader, err := zip.OpenReader(archivePath)
 	if err != nil {
 		return err
@@ -101,6 +117,9 @@ func (i *goInstaller) extractZip(archivePath string, destPath string) error {
 	defer reader.Close()
 
 	for _, file := range reader.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := i.extractZipFile(file, destPath); err != nil {
 			return err
 		}
@@ -149,7 +168,7 @@ func (i *goInstaller) extractZipFile(file *zip.File, destPath string) error {
 }
 
 // extractTarGz 解压 tar.gz 文件
-func (i *goInstaller) extractTarGz(archivePath string, destPath string) error {
+func (i *goInstaller) extractTarGz(ctx context.Context, archivePath string, destPath string) error {
 	file, err := os.Open(archivePath)
 	if err != nil {
 		return err
@@ -165,6 +184,9 @@ func (i *goInstaller) extractTarGz(archivePath string, destPath string) error {
 	tarReader := tar.NewReader(gzReader)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		header, err := tarReader.Next()
 		if err == io.EOF {
 			break
@@ -220,6 +242,89 @@ func (i *goInstaller) extractTarFile(header *tar.Header, reader io.Reader, destP
 	}
 }
 
+// ArchiveVersion 读取安装包中 VERSION 文件记录的版本号
+// 较新的 VERSION 文件在版本号之后还有构建时间等信息，只取第一行
+func (i *goInstaller) ArchiveVersion(archivePath string) (string, error) {
+	var content []byte
+	var err error
+	switch {
+	case strings.HasSuffix(archivePath, constants.ArchiveExtZip):
+		content, err = i.readZipVersion(archivePath)
+	case strings.HasSuffix(archivePath, constants.ArchiveExtTarGz):
+		content, err = i.readTarGzVersion(archivePath)
+	default:
+		return "", errors.ErrInvalidInput.
+			WithMessage("unsupported archive format").
+			WithContext("archive_path", archivePath)
+	}
+	if err != nil {
+		return "", errors.ErrInvalidVersion.
+			WithCause(err).
+			WithMessage("failed to read VERSION from archive").
+			WithContext("archive_path", archivePath)
+	}
+
+	line, _, _ := strings.Cut(string(content), "\n")
+	version, err := goversion.Parse(strings.TrimSpace(line))
+	if err != nil {
+		return "", errors.ErrInvalidVersion.
+			WithMessage(fmt.Sprintf("unrecognized version %q in the VERSION file of %s", strings.TrimSpace(line), archivePath)).
+			WithContext("archive_path", archivePath)
+	}
+	return version.String(), nil
+}
+
+// readTarGzVersion 读取 tar.gz 安装包中的 VERSION 文件
+func (i *goInstaller) readTarGzVersion(archivePath string) ([]byte, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found", constants.VersionFileInArchive)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && i.stripTopDir(header.Name) == constants.VersionFileInArchive {
+			return io.ReadAll(io.LimitReader(tarReader, 4096))
+		}
+	}
+}
+
+// readZipVersion 读取 zip 安装包中的 VERSION 文件
+func (i *goInstaller) readZipVersion(archivePath string) ([]byte, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || i.stripTopDir(file.Name) != constants.VersionFileInArchive {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, 4096))
+	}
+	return nil, fmt.Errorf("%s not found", constants.VersionFileInArchive)
+}
+
 // stripTopDir 去掉路径中的顶层目录
 // 例如: "go/bin/go" -> "bin/go"
 func (i *goInstaller) stripTopDir(path string) string {
@@ -250,7 +355,8 @@ func (i *goInstaller) Verify(installPath string, version string) error {
 	}
 
 	// 确保 go 可执行文件有执行权限（Unix 系统）
-	if runtime.GOOS != constants.OSWindows {
+	// 已可执行时不再修改，外部安装（如 /usr/local/go）通常属于其他用户
+	if runtime.GOOS != constants.OSWindows && !i.platform.IsExecutable(goPath) {
 		if err := i.platform.MakeExecutable(goPath); err != nil {
 			return errors.ErrInstallFailed.WithCause(err).WithMessage("failed to set executable permission")
 		}
@@ -263,19 +369,19 @@ func (i *goInstaller) Verify(installPath string, version string) error {
 		return errors.ErrInstallFailed.WithCause(err).WithMessage("failed to execute go version")
 	}
 
-	// 解析版本号
-	versionStr := string(output)
-	versionRegex := regexp.MustCompile(`go version go(\d+\.\d+(?:\.\d+)?)`)
-	matches := versionRegex.FindStringSubmatch(versionStr)
-	if len(matches) < 2 {
-		return errors.ErrInstallFailed.WithMessage("failed to parse go version output")
+	// 解析版本号（支持 rc/beta 预发布版本）
+	installedVersion, err := goversion.ParseVersionOutput(string(output))
+	if err != nil {
+		return errors.ErrInstallFailed.WithCause(err).WithMessage("failed to parse go version output")
 	}
 
-	installedVersion := matches[1]
-	expectedVersion := strings.TrimPrefix(version, "go")
+	expectedVersion, err := goversion.Parse(version)
+	if err != nil {
+		return errors.ErrInstallFailed.WithCause(err).WithMessage("invalid expected version")
+	}
 
-	if installedVersion != expectedVersion {
-		return errors.ErrInstallFailed.WithMessage(fmt.Sprintf("version mismatch: expected %s, got %s", expectedVersion, installedVersion))
+	if installedVersion.Compare(expectedVersion) != 0 {
+		return errors.ErrInstallFailed.WithMessage(fmt.Sprintf("version mismatch: expected %s, got %s", expectedVersion.Short(), installedVersion.Short()))
 	}
 
 	return nil
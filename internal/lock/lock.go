@@ -0,0 +1,351 @@
+// Package lock 提供跨进程的咨询式文件锁
+//
+// 锁以独占创建的锁文件实现，文件中记录持有者的 PID、主机名和获取时间。
+// 持有期间定期刷新锁文件的修改时间；持有者进程已退出（同一主机）或锁文件
+// 长时间未刷新（任何主机，包括 PID 已被其他进程复用的情况）时，锁被视为失效并可被接管。
+// 接管通过独占创建的 <锁文件>.takeover 串行进行，避免多个进程同时删除锁文件。
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+const (
+	// DefaultStaleAfter 锁文件超过该时长未刷新即视为失效
+	DefaultStaleAfter = 2 * time.Minute
+
+	// minPollInterval 和 maxPollInterval 等待锁时的轮询间隔范围
+	minPollInterval = 50 * time.Millisecond
+	maxPollInterval = 500 * time.Millisecond
+)
+
+// staleHook 判定锁失效后、接管之前调用，测试中用于让多个调用者同时接管同一个失效的锁
+var staleHook = func() {}
+
+// Holder 锁持有者信息
+type Holder struct {
+	PID      int       `json:"pid"`
+	Hostname string    `json:"hostname"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// String 返回持有者的可读描述
+func (h Holder) String() string {
+	if h.PID == 0 {
+		return "an unknown process"
+	}
+	if h.Hostname != "" && h.Hostname != hostname() {
+		return fmt.Sprintf("PID %d on %s", h.PID, h.Hostname)
+	}
+	return fmt.Sprintf("PID %d", h.PID)
+}
+
+// same 判断两个持有者信息是否为同一次加锁
+func (h Holder) same(other Holder) bool {
+	return h.PID == other.PID && h.Hostname == other.Hostname && h.Acquired.Equal(other.Acquired)
+}
+
+// Options 获取锁的选项
+type Options struct {
+	// Timeout 最长等待时间，0 表示只尝试一次
+	Timeout time.Duration
+
+	// StaleAfter 锁文件超过该时长未刷新即视为失效，0 表示使用 DefaultStaleAfter
+	StaleAfter time.Duration
+
+	// OnWait 锁被占用、开始等待时调用一次
+	OnWait func(holder Holder)
+
+	// Context 取消时停止等待并返回 ErrCancelled，nil 表示只受 Timeout 限制
+	Context context.Context
+}
+
+// Lock 已获取的文件锁
+type Lock struct {
+	path   string
+	holder Holder
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// Acquire 获取 path 对应的锁，锁被占用时最多等待 opts.Timeout
+// 超时返回 ErrLocked，错误信息中包含持有者的 PID；等待期间 opts.Context 被取消时返回 ErrCancelled
+func Acquire(path string, opts Options) (*Lock, error) {
+	staleAfter := opts.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.ErrStorageFailed.
+			WithCause(err).
+			WithMessage("failed to create lock directory").
+			WithContext("lock_path", path)
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	deadline := time.Now().Add(opts.Timeout)
+	interval := minPollInterval
+	waiting := false
+
+	for attempt := 0; ; attempt++ {
+		l, err := tryCreate(path)
+		if err == nil {
+			l.startHeartbeat(staleAfter)
+			return l, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.ErrStorageFailed.
+				WithCause(err).
+				WithMessage("failed to create lock file").
+				WithContext("lock_path", path)
+		}
+
+		holder, stale := inspect(path, staleAfter)
+		if stale {
+			staleHook()
+			retry, err := takeOver(path, holder, staleAfter)
+			if err != nil {
+				return nil, errors.ErrStorageFailed.
+					WithCause(err).
+					WithMessage("failed to remove stale lock file").
+					WithContext("lock_path", path)
+			}
+			if retry {
+				continue
+			}
+			// 其他进程正在接管，按锁被占用处理
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, lockedError(path, holder, opts.Timeout)
+		}
+
+		// 锁文件可能刚创建、内容尚未写入，此时等一轮再通知，以便给出持有者的 PID
+		if !waiting && (holder.PID != 0 || attempt > 0) {
+			waiting = true
+			logger.Info("Waiting for lock %s held by %s", path, holder)
+			if opts.OnWait != nil {
+				opts.OnWait(holder)
+			}
+		}
+
+		sleep := interval
+		if remaining := time.Until(deadline); remaining < sleep {
+			sleep = remaining
+		}
+		timer := time.NewTimer(sleep)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.ErrCancelled.
+				WithCause(ctx.Err()).
+				WithMessage(fmt.Sprintf("stopped waiting for %s held by %s", filepath.Base(path), holder)).
+				WithContext("lock_path", path)
+		}
+		if interval *= 2; interval > maxPollInterval {
+			interval = maxPollInterval
+		}
+	}
+}
+
+// Release 释放锁
+// 锁文件已被其他进程接管（例如本进程被误判为失效）时不删除它
+func (l *Lock) Release() error {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+	})
+
+	current, err := readHolder(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to read lock file").WithContext("lock_path", l.path)
+	}
+	if !current.same(l.holder) {
+		logger.Warn("Lock %s was taken over by %s", l.path, current)
+		return nil
+	}
+
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to remove lock file").WithContext("lock_path", l.path)
+	}
+	return nil
+}
+
+// Path 返回锁文件路径
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// tryCreate 独占创建锁文件并写入当前进程信息
+func tryCreate(path string) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	holder := Holder{
+		PID:      os.Getpid(),
+		Hostname: hostname(),
+		Acquired: time.Now(),
+	}
+
+	data, _ := json.Marshal(holder)
+	_, writeErr := f.Write(data)
+	closeErr := f.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(path)
+		if writeErr != nil {
+			return nil, writeErr
+		}
+		return nil, closeErr
+	}
+
+	return &Lock{
+		path:   path,
+		holder: holder,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// startHeartbeat 定期刷新锁文件的修改时间，表明持有者仍然存活
+func (l *Lock) startHeartbeat(staleAfter time.Duration) {
+	go func() {
+		defer close(l.done)
+
+		ticker := time.NewTicker(staleAfter / 4)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				now := time.Now()
+				if err := os.Chtimes(l.path, now, now); err != nil {
+					logger.Warn("Failed to refresh lock %s: %v", l.path, err)
+				}
+			case <-l.stop:
+				return
+			}
+		}
+	}()
+}
+
+// inspect 读取锁的持有者并判断锁是否已失效
+func inspect(path string, staleAfter time.Duration) (Holder, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		// 锁刚被释放，下一轮重试即可
+		return Holder{}, false
+	}
+
+	holder, err := readHolder(path)
+
+	// 持有者会定期刷新锁文件，长时间未刷新说明持有者已不在运行，
+	// 即使同一主机上该 PID 仍然存活（PID 可能已被其他进程复用）
+	if time.Since(info.ModTime()) > staleAfter {
+		return holder, true
+	}
+
+	// 同一主机上还可以直接检查进程是否存活，无需等到 staleAfter；
+	// 其他主机的锁，或者内容尚未写入/已损坏的锁文件，只能依据刷新时间判断
+	if err == nil && holder.PID > 0 && holder.Hostname == hostname() {
+		return holder, !processAlive(holder.PID)
+	}
+	return holder, false
+}
+
+// takeOver 删除已判定失效的锁文件，返回是否应立即重新尝试获取锁
+// 多个进程可能同时判定同一个锁失效：只有独占创建 takeover 文件的进程可以删除锁文件，
+// 并且删除前重新读取持有者，确认锁文件仍是 stale 对应的那一个，而不是其他进程刚获取的新锁
+func takeOver(path string, stale Holder, staleAfter time.Duration) (bool, error) {
+	guard := path + ".takeover"
+
+	f, err := os.OpenFile(guard, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if !os.IsExist(err) {
+			return false, err
+		}
+		// 接管的进程在删除 takeover 文件前退出时，超过 staleAfter 后清理它
+		if info, err := os.Stat(guard); err == nil && time.Since(info.ModTime()) > staleAfter {
+			os.Remove(guard)
+		}
+		return false, nil
+	}
+	f.Close()
+	defer os.Remove(guard)
+
+	holder, stillStale := inspect(path, staleAfter)
+	if !stillStale || !holder.same(stale) {
+		// 锁已被释放或被其他进程接管，重新尝试获取即可
+		return true, nil
+	}
+
+	logger.Warn("Removing stale lock %s held by %s", path, holder)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// readHolder 读取锁文件中的持有者信息
+func readHolder(path string) (Holder, error) {
+	var holder Holder
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return holder, err
+	}
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return holder, err
+	}
+	return holder, nil
+}
+
+// lockedError 构建等待锁超时的错误
+func lockedError(path string, holder Holder, timeout time.Duration) error {
+	msg := fmt.Sprintf("%s is locked by %s", filepath.Base(path), holder)
+	if !holder.Acquired.IsZero() {
+		msg += fmt.Sprintf(" since %s", holder.Acquired.Format(time.RFC3339))
+	}
+	if timeout > 0 {
+		msg += fmt.Sprintf(" (gave up after %s)", timeout)
+	}
+
+	return errors.ErrLocked.
+		WithMessage(msg).
+		WithContext("lock_path", path).
+		WithContext("holder_pid", holder.PID)
+}
+
+var (
+	hostnameOnce  sync.Once
+	hostnameValue string
+)
+
+// hostname 返回当前主机名，获取失败时返回空字符串
+func hostname() string {
+	hostnameOnce.Do(func() {
+		hostnameValue, _ = os.Hostname()
+	})
+	return hostnameValue
+}
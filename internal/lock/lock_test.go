@@ -0,0 +1,295 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+// writeHolder 伪造一个由其他进程持有的锁文件
+func writeHolder(t *testing.T, path string, holder Holder, modTime time.Time) {
+	t.Helper()
+
+	data, _ := json.Marshal(holder)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// exitedPID 返回一个已经退出的进程的 PID
+func exitedPID(t *testing.T) int {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestAcquireRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "config.json.lock")
+
+	l, err := Acquire(path, Options{})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	holder, err := readHolder(path)
+	if err != nil {
+		t.Fatalf("readHolder() error = %v", err)
+	}
+	if holder.PID != os.Getpid() {
+		t.Errorf("holder PID = %d, want %d", holder.PID, os.Getpid())
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("lock file still exists after Release()")
+	}
+
+	// 释放后可以再次获取
+	l, err = Acquire(path, Options{})
+	if err != nil {
+		t.Fatalf("second Acquire() error = %v", err)
+	}
+	l.Release()
+}
+
+func TestAcquireTimeoutNamesHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go1.22.5.lock")
+
+	l, err := Acquire(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Release()
+
+	start := time.Now()
+	_, err = Acquire(path, Options{Timeout: 200 * time.Millisecond})
+	if err == nil {
+		t.Fatal("Acquire() on a held lock succeeded")
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Errorf("Acquire() returned before the timeout")
+	}
+	if !errors.IsType(err, errors.ErrLocked) {
+		t.Errorf("error = %v, want ErrLocked", err)
+	}
+	if want := "PID " + strconv.Itoa(os.Getpid()); !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not mention %q", err, want)
+	}
+}
+
+func TestAcquireWaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go1.22.5.lock")
+
+	l, err := Acquire(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		l.Release()
+	}()
+
+	waited := 0
+	l2, err := Acquire(path, Options{
+		Timeout: 5 * time.Second,
+		OnWait:  func(Holder) { waited++ },
+	})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer l2.Release()
+
+	if waited != 1 {
+		t.Errorf("OnWait called %d times, want 1", waited)
+	}
+}
+
+func TestAcquireCancelledWhileWaiting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go1.22.5.lock")
+
+	l, err := Acquire(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	_, err = Acquire(path, Options{
+		Timeout: time.Minute,
+		Context: ctx,
+		OnWait:  func(Holder) { cancel() },
+	})
+	if !errors.IsType(err, errors.ErrCancelled) {
+		t.Fatalf("Acquire() error = %v, want ErrCancelled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Acquire() kept waiting for %s after cancel", elapsed)
+	}
+}
+
+func TestAcquireStaleLock(t *testing.T) {
+	// 当前测试进程代表一个仍然存活的进程，例如复用了持有者 PID 的无关进程
+	live := Holder{PID: os.Getpid(), Hostname: hostname()}
+
+	tests := []struct {
+		name       string
+		holder     Holder
+		age        time.Duration
+		wantErr    bool
+		wantHolder string
+	}{
+		{
+			name:   "exited process on this host",
+			holder: Holder{PID: exitedPID(t), Hostname: hostname()},
+		},
+		{
+			name:   "live process on this host, not refreshed",
+			holder: live,
+			age:    time.Hour,
+		},
+		{
+			name:       "live process on this host, recently refreshed",
+			holder:     live,
+			wantErr:    true,
+			wantHolder: live.String(),
+		},
+		{
+			name:   "other host, not refreshed",
+			holder: Holder{PID: 1, Hostname: "build-agent-7"},
+			age:    time.Hour,
+		},
+		{
+			name:       "other host, recently refreshed",
+			holder:     Holder{PID: 1, Hostname: "build-agent-7"},
+			wantErr:    true,
+			wantHolder: "PID 1 on build-agent-7",
+		},
+		{
+			name: "unreadable lock file, not refreshed",
+			age:  time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json.lock")
+			writeHolder(t, path, tt.holder, time.Now().Add(-tt.age))
+
+			l, err := Acquire(path, Options{StaleAfter: time.Minute})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Acquire() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantHolder) {
+					t.Errorf("error %q does not name the holder", err)
+				}
+				return
+			}
+			l.Release()
+		})
+	}
+}
+
+func TestAcquireConcurrentTakeover(t *testing.T) {
+	const workers = 4
+
+	path := filepath.Join(t.TempDir(), "config.json.lock")
+	writeHolder(t, path, Holder{PID: 1, Hostname: "build-agent-7"}, time.Now().Add(-time.Hour))
+
+	// 等所有调用者都判定同一个锁失效后再依次放行：先接管的调用者已经获取新锁时，
+	// 后面的调用者不能再把它当作失效的锁删除
+	var arrived int32
+	allArrived := make(chan struct{})
+	staleHook = func() {
+		n := atomic.AddInt32(&arrived, 1)
+		if n > workers {
+			return
+		}
+		if n == workers {
+			close(allArrived)
+		}
+		<-allArrived
+		time.Sleep(time.Duration(n) * 5 * time.Millisecond)
+	}
+	defer func() { staleHook = func() {} }()
+
+	var inside, overlaps int32
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			l, err := Acquire(path, Options{Timeout: 10 * time.Second, StaleAfter: time.Minute})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if atomic.AddInt32(&inside, 1) > 1 {
+				atomic.AddInt32(&overlaps, 1)
+			}
+			time.Sleep(50 * time.Millisecond)
+			atomic.AddInt32(&inside, -1)
+			l.Release()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if overlaps > 0 {
+		t.Errorf("lock held by more than one caller %d times", overlaps)
+	}
+	if _, err := os.Stat(path + ".takeover"); !os.IsNotExist(err) {
+		t.Errorf("takeover file left behind")
+	}
+}
+
+func TestReleaseKeepsTakenOverLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json.lock")
+
+	l, err := Acquire(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟锁被判定失效后由其他主机上的进程接管
+	other := Holder{PID: 4242, Hostname: "build-agent-7", Acquired: time.Now()}
+	writeHolder(t, path, other, time.Now())
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	holder, err := readHolder(path)
+	if err != nil {
+		t.Fatalf("lock file removed by Release(): %v", err)
+	}
+	if !holder.same(other) {
+		t.Errorf("holder = %+v, want %+v", holder, other)
+	}
+}
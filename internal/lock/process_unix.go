@@ -0,0 +1,12 @@
+//go:build linux || darwin
+
+package lock
+
+import "syscall"
+
+// processAlive 检查进程是否存在
+// 信号 0 只做权限和存在性检查；EPERM 表示进程存在但属于其他用户
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kawaiirei0/gx/internal/gxhome"
 	"github.com/kawaiirei0/gx/pkg/constants"
 )
 
@@ -180,11 +181,7 @@ func (l *fileLogger) Close() error {
 
 // GetLogDir 获取日志目录路径
 func GetLogDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(homeDir, constants.ConfigDir, "logs"), nil
+	return gxhome.Path(constants.LogDirName)
 }
 
 // GetLogFilePath 获取日志文件路径
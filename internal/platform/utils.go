@@ -3,9 +3,9 @@ package platform
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"runtime"
 
+	"github.com/kawaiirei0/gx/internal/gxhome"
 	"github.com/kawaiirei0/gx/pkg/constants"
 )
 
@@ -25,22 +25,22 @@ func GetArchiveExtension() string {
 	return constants.ArchiveExtTarGz
 }
 
-// GetConfigDir 获取配置目录的完整路径
+// GetConfigDir 获取 gx 根目录（GX_HOME）的完整路径
 func GetConfigDir() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := gxhome.Dir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", fmt.Errorf("failed to get gx home directory: %w", err)
 	}
-	return filepath.Join(home, constants.ConfigDir), nil
+	return dir, nil
 }
 
-// GetInstallDir 获取安装目录的完整路径
+// GetInstallDir 获取默认安装目录的完整路径
 func GetInstallDir() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := gxhome.VersionsDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", fmt.Errorf("failed to get gx home directory: %w", err)
 	}
-	return filepath.Join(home, constants.DefaultInstallDir), nil
+	return dir, nil
 }
 
 // EnsureDir 确保目录存在，如果不存在则创建
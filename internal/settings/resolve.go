@@ -0,0 +1,282 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// layer 一个配置层：来自同一个文件的配置项
+type layer struct {
+	scope  interfaces.SettingScope
+	path   string
+	values map[string]string
+}
+
+// Resolve 合并各层配置
+// user 为用户配置文件 userPath 中的 settings；dir 为查找项目配置的起始目录，为空时使用当前工作目录
+// 系统和项目配置无法读取、解析或取值无效时跳过对应的文件或配置项并记录警告，
+// 以免一个有问题的 gx.toml 使所有命令失败；用户配置中的无效值仍然返回错误
+func Resolve(user map[string]string, userPath string, dir string) (*interfaces.Settings, error) {
+	settings := interfaces.NewSettings()
+	for _, def := range definitions {
+		value, err := def.Default()
+		if err != nil {
+			return nil, errors.ErrStorageFailed.
+				WithCause(err).
+				WithMessage(fmt.Sprintf("failed to determine the default of %s", def.Key))
+		}
+		settings.Set(interfaces.Setting{
+			Key:   def.Key,
+			Value: value,
+			Scope: interfaces.ScopeDefault,
+		})
+	}
+
+	system, err := loadSystem()
+	if err != nil {
+		warn(settings, "Ignoring the system config %s: %v", system.path, err)
+	}
+
+	project, err := loadProject(dir)
+	if err != nil {
+		warn(settings, "Ignoring the project config %s: %v", project.path, err)
+	}
+
+	layers := []layer{system, {scope: interfaces.ScopeUser, path: userPath, values: user}, project}
+	for _, l := range layers {
+		for _, key := range sortedKeys(l.values) {
+			value := l.values[key]
+			def, ok := Lookup(key)
+			if !ok {
+				logger.Warn("Unknown setting %s in %s", key, l.path)
+			} else {
+				// 项目配置随仓库分发，不能决定下载来源、校验策略和安装位置
+				if l.scope == interfaces.ScopeProject && !def.Project {
+					warn(settings, "Ignoring %s in %s: it can only be set in the system or user config", key, l.path)
+					continue
+				}
+				normalized, err := def.Validate(value)
+				if err != nil && l.scope != interfaces.ScopeUser {
+					warn(settings, "Ignoring %s in %s: %v", key, l.path, err)
+					continue
+				}
+				if err != nil {
+					return nil, InvalidValueError(key, l.path, err)
+				}
+				value = normalized
+				if def.Type == TypePath {
+					value = resolvePath(value, filepath.Dir(l.path))
+				}
+			}
+			settings.Set(interfaces.Setting{
+				Key:    key,
+				Value:  value,
+				Scope:  l.scope,
+				Origin: l.path,
+			})
+		}
+	}
+
+	return settings, nil
+}
+
+// warn 记录并保存一条合并配置时的警告
+func warn(settings *interfaces.Settings, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	logger.Warn("%s", message)
+	settings.Warn(message)
+}
+
+// sortedKeys 返回按名称排序的配置项名称，使警告的顺序固定
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// InvalidValueError 构建配置项取值无效的错误，path 为值所在的文件，未知时为空
+func InvalidValueError(key string, path string, err error) *errors.Error {
+	if path == "" {
+		return errors.ErrInvalidSetting.
+			WithCause(err).
+			WithMessage(fmt.Sprintf("invalid value for %s", key)).
+			WithContext("key", key)
+	}
+	return errors.ErrInvalidSetting.
+		WithCause(err).
+		WithMessage(fmt.Sprintf("invalid value for %s in %s", key, path)).
+		WithContext("key", key).
+		WithContext("config_path", path)
+}
+
+// UnknownKeyError 构建配置项不存在的错误
+func UnknownKeyError(key string) *errors.Error {
+	return errors.ErrInvalidSetting.
+		WithMessage(fmt.Sprintf("unknown setting %q; run 'gx config list' to see all settings", key)).
+		WithContext("key", key)
+}
+
+// SystemConfigFile 返回系统级配置文件路径
+func SystemConfigFile() string {
+	if path := os.Getenv(constants.EnvGxSystemConfig); path != "" {
+		return path
+	}
+	return defaultSystemConfigFile()
+}
+
+// FindProjectFile 从 dir 开始向上查找项目配置文件，找不到时返回空字符串
+// 同一目录下同时存在 gx.toml 和 .gx.json 时使用 gx.toml
+func FindProjectFile(dir string) (string, error) {
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		dir = wd
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range []string{constants.ProjectConfigFileName, constants.ProjectConfigJSONFileName} {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				if name == constants.ProjectConfigFileName {
+					if _, err := os.Stat(filepath.Join(dir, constants.ProjectConfigJSONFileName)); err == nil {
+						logger.Warn("Both %s and %s exist in %s; ignoring %s",
+							constants.ProjectConfigFileName, constants.ProjectConfigJSONFileName, dir, constants.ProjectConfigJSONFileName)
+					}
+				}
+				return path, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// loadSystem 读取系统级配置，文件不存在时返回空层
+// 文件无法读取或解析时返回的层没有配置项
+func loadSystem() (layer, error) {
+	path := SystemConfigFile()
+	l := layer{scope: interfaces.ScopeSystem, path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return l, err
+	}
+
+	values, err := ParseTOML(data)
+	if err != nil {
+		return l, err
+	}
+	l.values = values
+	return l, nil
+}
+
+// loadProject 读取项目配置，找不到项目配置文件时返回空层
+// 文件无法读取或解析时返回的层没有配置项
+func loadProject(dir string) (layer, error) {
+	l := layer{scope: interfaces.ScopeProject}
+
+	path, err := FindProjectFile(dir)
+	if err != nil || path == "" {
+		return l, err
+	}
+	l.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return l, err
+	}
+
+	var values map[string]string
+	if filepath.Base(path) == constants.ProjectConfigJSONFileName {
+		values, err = parseJSON(data)
+	} else {
+		values, err = ParseTOML(data)
+	}
+	if err != nil {
+		return l, err
+	}
+	l.values = values
+	return l, nil
+}
+
+// parseJSON 解析 JSON 格式的配置，嵌套对象展开为 "a.b" 形式的键
+func parseJSON(data []byte) (map[string]string, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	if err := flatten("", doc, values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// flatten 将嵌套的 JSON 对象展开到 values
+func flatten(prefix string, doc map[string]interface{}, values map[string]string) error {
+	for key, raw := range doc {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := raw.(type) {
+		case map[string]interface{}:
+			if err := flatten(key, v, values); err != nil {
+				return err
+			}
+		case string:
+			values[key] = v
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		case float64:
+			values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case nil:
+			// null 等同于未设置
+		default:
+			return fmt.Errorf("%s: unsupported value %v", key, raw)
+		}
+	}
+	return nil
+}
+
+// resolvePath 将配置文件中的路径转换为绝对路径
+// ~ 开头的路径相对于用户主目录，其他相对路径相对于配置文件所在的目录
+func resolvePath(value string, base string) string {
+	if value == "~" || strings.HasPrefix(value, "~/") || strings.HasPrefix(value, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, value[1:])
+		}
+	}
+	if filepath.IsAbs(value) || base == "" || base == "." {
+		return filepath.Clean(value)
+	}
+	return filepath.Join(base, value)
+}
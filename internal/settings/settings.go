@@ -0,0 +1,311 @@
+// Package settings 解析 gx 的分层配置
+//
+// 配置项按以下层级合并，后面的层级覆盖前面的：
+//
+//  1. 内置默认值
+//  2. 系统配置：/etc/gx/config（TOML，Windows 上为 %ProgramData%\gx\config），路径可由 GX_SYSTEM_CONFIG 覆盖
+//  3. 用户配置：$GX_HOME/config.json 中的 settings
+//  4. 项目配置：从当前目录向上查找到的第一个 gx.toml 或 .gx.json
+//
+// 组织可以在系统配置中统一下发镜像地址和校验策略，用户和项目再按需覆盖。
+// 项目配置随仓库分发，不可信，只能设置 Definition.Project 为 true 的配置项；
+// 下载来源、校验策略和安装位置只能由系统和用户配置决定。
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/gxhome"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// Type 配置项的值类型
+type Type string
+
+const (
+	TypeString   Type = "string"
+	TypeURL      Type = "url"      // http 或 https 地址
+	TypeEnum     Type = "enum"     // Definition.Values 中的一个
+	TypeBool     Type = "bool"     // true 或 false
+	TypeInt      Type = "int"      // 非负整数
+	TypeDuration Type = "duration" // 正的时长，例如 30s、5m
+	TypePath     Type = "path"     // 相对路径相对于所在配置文件的目录解析，支持以 ~ 开头
+	TypeSources  Type = "sources"  // 逗号分隔的发布源列表，见 ParseSources
+	TypeSize     Type = "size"     // 字节数，可带 K、M、G、T 后缀（1024 进制），例如 500M、2G
+)
+
+// Definition 已知配置项的定义
+type Definition struct {
+	Key         string
+	Type        Type
+	Values      []string // TypeEnum 的可选值
+	Description string
+
+	// Project 项目配置（gx.toml、.gx.json）能否设置该配置项
+	// 影响下载来源、校验、文件写入位置或会删除文件的配置项不能由仓库决定
+	Project bool
+
+	// Default 返回内置默认值
+	Default func() (string, error)
+}
+
+// definitions 已知配置项，按名称排序
+var definitions = []Definition{
+	{
+		Key:         constants.SettingCacheDir,
+		Type:        TypePath,
+		Description: "Directory of the download cache shared by all gx homes",
+		Default:     gxhome.CacheDir,
+	},
+	{
+		Key:         constants.SettingCacheMaxSize,
+		Type:        TypeSize,
+		Description: "Size limit of the download cache; least recently used archives are evicted, 0 disables the cache",
+		Default:     constant(constants.DefaultCacheMaxSize),
+	},
+	{
+		Key:         constants.SettingDownloadIndexTimeout,
+		Type:        TypeDuration,
+		Description: "Timeout for fetching the Go version index",
+		Project:     true,
+		Default:     constant(constants.IndexTimeout.String()),
+	},
+	{
+		Key:         constants.SettingDownloadMirror,
+		Type:        TypeURL,
+		Description: "Base URL that serves the Go version index and release archives (ignored when download.sources is set)",
+		Default:     constant(constants.GoDownloadURL),
+	},
+	{
+		Key:         constants.SettingDownloadRetries,
+		Type:        TypeInt,
+		Description: "How many times to retry a version index fetch or archive download after a recoverable network error; 0 disables retries",
+		Project:     true,
+		Default:     constant(strconv.Itoa(constants.DownloadRetries)),
+	},
+	{
+		Key:         constants.SettingDownloadSources,
+		Type:        TypeSources,
+		Description: "Release sources tried in order: http(s) mirrors, local directories or file:// indexes, each with optional name=, timeout= and index-timeout=",
+		Default:     constant(""),
+	},
+	{
+		Key:         constants.SettingDownloadTimeout,
+		Type:        TypeDuration,
+		Description: "Timeout for downloading a release archive",
+		Project:     true,
+		Default:     constant(constants.DownloadTimeout.String()),
+	},
+	{
+		Key:         constants.SettingDownloadVerify,
+		Type:        TypeEnum,
+		Values:      []string{constants.VerifyStrict, constants.VerifyAuto, constants.VerifyOff},
+		Description: "Checksum policy for downloaded archives",
+		Default:     constant(constants.VerifyAuto),
+	},
+	{
+		Key:         constants.SettingInstallLockTimeout,
+		Type:        TypeDuration,
+		Description: "How long to wait for another gx process installing the same version",
+		Project:     true,
+		Default:     constant(constants.InstallLockTimeout.String()),
+	},
+	{
+		Key:         constants.SettingInstallRoot,
+		Type:        TypePath,
+		Description: "Directory that new Go versions are installed into",
+		Default:     gxhome.VersionsDir,
+	},
+	{
+		Key:         constants.SettingSourceRepository,
+		Type:        TypeString,
+		Description: "Git repository cloned by 'gx install --from-source' for tip and git refs",
+		Default:     constant(constants.GoSourceRepository),
+	},
+	{
+		Key:         constants.SettingUpdateSwitch,
+		Type:        TypeBool,
+		Description: "Default for 'gx update --switch': switch to the newly installed version",
+		Project:     true,
+		Default:     constant("false"),
+	},
+	{
+		Key:         constants.SettingUpgradeRemoveOld,
+		Type:        TypeBool,
+		Description: "Default for 'gx upgrade --remove-old': uninstall superseded patch versions",
+		Default:     constant("false"),
+	},
+}
+
+// constant 返回固定的默认值
+func constant(value string) func() (string, error) {
+	return func() (string, error) {
+		return value, nil
+	}
+}
+
+// Definitions 返回所有已知配置项的定义
+func Definitions() []Definition {
+	return append([]Definition(nil), definitions...)
+}
+
+// Keys 返回所有已知配置项的名称
+func Keys() []string {
+	keys := make([]string, 0, len(definitions))
+	for _, def := range definitions {
+		keys = append(keys, def.Key)
+	}
+	return keys
+}
+
+// Lookup 查找配置项的定义
+func Lookup(key string) (Definition, bool) {
+	for _, def := range definitions {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Validate 检查值是否符合配置项的类型，返回规范化后的值
+func (d Definition) Validate(value string) (string, error) {
+	switch d.Type {
+	case TypeURL:
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("%q is not an http(s) URL", value)
+		}
+		return value, nil
+
+	case TypeEnum:
+		for _, allowed := range d.Values {
+			if value == allowed {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("%q is not one of %s", value, strings.Join(d.Values, ", "))
+
+	case TypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not true or false", value)
+		}
+		return strconv.FormatBool(b), nil
+
+	case TypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("%q is not a non-negative integer", value)
+		}
+		return strconv.Itoa(n), nil
+
+	case TypeDuration:
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return "", fmt.Errorf("%q is not a positive duration such as 30s or 5m", value)
+		}
+		return value, nil
+
+	case TypePath:
+		if value == "" {
+			return "", fmt.Errorf("path must not be empty")
+		}
+		return value, nil
+
+	case TypeSources:
+		if _, err := ParseSources(value); err != nil {
+			return "", err
+		}
+		return value, nil
+
+	case TypeSize:
+		if _, err := ParseSize(value); err != nil {
+			return "", err
+		}
+		return value, nil
+	}
+
+	return value, nil
+}
+
+// TypeDescription 返回值类型的可读描述，例如 "one of strict, auto, off"
+func (d Definition) TypeDescription() string {
+	if d.Type == TypeEnum {
+		return "one of " + strings.Join(d.Values, ", ")
+	}
+	if d.Type == TypeSources {
+		return "comma-separated list of sources"
+	}
+	return string(d.Type)
+}
+
+// Bool 返回布尔配置项的值，无法解析时返回 false
+func Bool(s *interfaces.Settings, key string) bool {
+	b, _ := strconv.ParseBool(s.Get(key))
+	return b
+}
+
+// Int 返回整数配置项的值，无法解析时返回 fallback
+func Int(s *interfaces.Settings, key string, fallback int) int {
+	n, err := strconv.Atoi(s.Get(key))
+	if err != nil || n < 0 {
+		return fallback
+	}
+	return n
+}
+
+// Duration 返回时长配置项的值，无法解析时返回 fallback
+func Duration(s *interfaces.Settings, key string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(s.Get(key))
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+	return duration
+}
+
+// Size 返回大小配置项的字节数，无法解析时返回 fallback
+func Size(s *interfaces.Settings, key string, fallback int64) int64 {
+	size, err := ParseSize(s.Get(key))
+	if err != nil {
+		return fallback
+	}
+	return size
+}
+
+// ParseSize 解析字节数，可带 K、M、G、T 后缀（1024 进制，后缀后可跟 B 或 iB），例如 0、500M、2GiB
+func ParseSize(value string) (int64, error) {
+	number := strings.ToUpper(strings.TrimSpace(value))
+	number = strings.TrimSuffix(strings.TrimSuffix(number, "B"), "I")
+
+	multiplier := int64(1)
+	if number != "" {
+		if shift := strings.IndexByte("KMGT", number[len(number)-1]); shift >= 0 {
+			multiplier = 1 << (10 * (shift + 1))
+			number = number[:len(number)-1]
+		}
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 || n > (1<<62)/multiplier {
+		return 0, fmt.Errorf("%q is not a size such as 500M or 2G", value)
+	}
+	return n * multiplier, nil
+}
+
+// Mirror 返回下载镜像地址，保证以 / 结尾
+func Mirror(s *interfaces.Settings) string {
+	mirror := s.Get(constants.SettingDownloadMirror)
+	if mirror == "" {
+		mirror = constants.GoDownloadURL
+	}
+	if !strings.HasSuffix(mirror, "/") {
+		mirror += "/"
+	}
+	return mirror
+}
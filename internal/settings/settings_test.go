@@ -0,0 +1,374 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "tables and comments",
+			input: `# organisation defaults
+[download]
+mirror = "https://mirrors.example.com/golang/" # internal mirror
+verify = 'strict'
+
+[install]
+root = "/opt/go-versions"
+`,
+			want: map[string]string{
+				"download.mirror": "https://mirrors.example.com/golang/",
+				"download.verify": "strict",
+				"install.root":    "/opt/go-versions",
+			},
+		},
+		{
+			name:  "dotted keys, bools and numbers",
+			input: "download.verify = \"off\"\r\nretry.enabled = true\nretry.attempts = 1_000\nretry.factor = 1.5\n",
+			want: map[string]string{
+				"download.verify": "off",
+				"retry.enabled":   "true",
+				"retry.attempts":  "1000",
+				"retry.factor":    "1.5",
+			},
+		},
+		{
+			name:  "escapes",
+			input: `path = "C:\\Go \"tip\""`,
+			want:  map[string]string{"path": `C:\Go "tip"`},
+		},
+		{name: "missing equals", input: "[download]\nmirror\n", wantErr: true},
+		{name: "unterminated string", input: `mirror = "https://`, wantErr: true},
+		{name: "unquoted string", input: `verify = strict`, wantErr: true},
+		{name: "duplicate key", input: "[download]\nverify = \"off\"\nverify = \"auto\"\n", wantErr: true},
+		{name: "array", input: `mirrors = ["a", "b"]`, wantErr: true},
+		{name: "array of tables", input: "[[mirror]]\n", wantErr: true},
+		{name: "trailing garbage", input: `verify = "off" "auto"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTOML([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTOML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTOML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// setupLayers 准备系统配置和一个嵌套的项目目录，返回项目中的子目录
+func setupLayers(t *testing.T, system string, projectName string, project string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	t.Setenv(constants.EnvGxHome, filepath.Join(root, "home"))
+
+	systemPath := filepath.Join(root, "etc", "config")
+	if system != "" {
+		if err := os.MkdirAll(filepath.Dir(systemPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(systemPath, []byte(system), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(constants.EnvGxSystemConfig, systemPath)
+
+	projectDir := filepath.Join(root, "repo")
+	subDir := filepath.Join(projectDir, "cmd", "tool")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if project != "" {
+		if err := os.WriteFile(filepath.Join(projectDir, projectName), []byte(project), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return subDir
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	dir := setupLayers(t,
+		"[download]\nmirror = \"https://mirror.corp/go/\"\nverify = \"strict\"\n",
+		constants.ProjectConfigFileName,
+		"[download]\ntimeout = \"30m\"\n",
+	)
+	projectDir := filepath.Dir(filepath.Dir(dir))
+	userPath := filepath.Join(os.Getenv(constants.EnvGxHome), "config.json")
+
+	s, err := Resolve(map[string]string{constants.SettingDownloadVerify: "off"}, userPath, dir)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		scope  interfaces.SettingScope
+		origin string
+	}{
+		{constants.SettingDownloadMirror, "https://mirror.corp/go/", interfaces.ScopeSystem, SystemConfigFile()},
+		{constants.SettingDownloadVerify, "off", interfaces.ScopeUser, userPath},
+		{constants.SettingDownloadTimeout, "30m", interfaces.ScopeProject, filepath.Join(projectDir, constants.ProjectConfigFileName)},
+	}
+	for _, tt := range tests {
+		got, ok := s.Lookup(tt.key)
+		if !ok {
+			t.Errorf("%s not set", tt.key)
+			continue
+		}
+		if got.Value != tt.value || got.Scope != tt.scope || got.Origin != tt.origin {
+			t.Errorf("%s = %+v, want %s from %s (%s)", tt.key, got, tt.value, tt.origin, tt.scope)
+		}
+	}
+}
+
+func TestResolveDefaults(t *testing.T) {
+	dir := setupLayers(t, "", "", "")
+
+	s, err := Resolve(nil, "", dir)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	for _, def := range Definitions() {
+		got, ok := s.Lookup(def.Key)
+		if !ok || got.Scope != interfaces.ScopeDefault || got.Origin != "" {
+			t.Errorf("%s = %+v, want the default", def.Key, got)
+		}
+	}
+	if want := filepath.Join(os.Getenv(constants.EnvGxHome), constants.VersionsDirName); s.Get(constants.SettingInstallRoot) != want {
+		t.Errorf("install.root = %s, want %s", s.Get(constants.SettingInstallRoot), want)
+	}
+}
+
+func TestResolveProjectJSON(t *testing.T) {
+	dir := setupLayers(t, "", constants.ProjectConfigJSONFileName,
+		`{"download": {"retries": 5}, "experimental.flag": true}`)
+
+	s, err := Resolve(nil, "", dir)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if got := Int(s, constants.SettingDownloadRetries, 0); got != 5 {
+		t.Errorf("download.retries = %d, want 5", got)
+	}
+	// 未知的配置项同样保留，便于 gx config list 显示
+	if got := s.Get("experimental.flag"); got != "true" {
+		t.Errorf("experimental.flag = %q, want true", got)
+	}
+}
+
+func TestResolveProjectAllowList(t *testing.T) {
+	// 克隆下来的仓库不能改变下载来源、校验策略和安装位置
+	dir := setupLayers(t, "", constants.ProjectConfigFileName, `[download]
+verify = "off"
+mirror = "https://attacker.example.com/"
+sources = "https://attacker.example.com/"
+timeout = "20m"
+
+[install]
+root = "/tmp/evil"
+
+[source]
+repository = "https://attacker.example.com/go.git"
+
+[cache]
+dir = ".cache"
+`)
+
+	s, err := Resolve(map[string]string{constants.SettingDownloadVerify: constants.VerifyStrict}, "", dir)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	for _, key := range []string{
+		constants.SettingDownloadVerify,
+		constants.SettingDownloadMirror,
+		constants.SettingDownloadSources,
+		constants.SettingInstallRoot,
+		constants.SettingSourceRepository,
+		constants.SettingCacheDir,
+	} {
+		if got, _ := s.Lookup(key); got.Scope == interfaces.ScopeProject {
+			t.Errorf("%s = %+v, want the project value ignored", key, got)
+		}
+	}
+	if got := s.Get(constants.SettingDownloadVerify); got != constants.VerifyStrict {
+		t.Errorf("download.verify = %s, want the user value strict", got)
+	}
+	if got, _ := s.Lookup(constants.SettingDownloadTimeout); got.Scope != interfaces.ScopeProject || got.Value != "20m" {
+		t.Errorf("download.timeout = %+v, want 20m from the project", got)
+	}
+	if warnings := s.Warnings(); len(warnings) != 6 {
+		t.Errorf("Warnings() = %q, want one per ignored setting", warnings)
+	}
+}
+
+func TestResolveInvalidLayers(t *testing.T) {
+	// 无法解析的系统或项目配置被跳过，不影响其他层
+	dir := setupLayers(t, "[download\n", constants.ProjectConfigFileName, "timeout = \"30m\"\nretries = 5\n[download\n")
+
+	s, err := Resolve(map[string]string{constants.SettingDownloadVerify: constants.VerifyStrict}, "", dir)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v, want the broken layers skipped", err)
+	}
+	if got := s.Get(constants.SettingDownloadVerify); got != constants.VerifyStrict {
+		t.Errorf("download.verify = %s, want the user value strict", got)
+	}
+	if got, _ := s.Lookup(constants.SettingDownloadTimeout); got.Scope != interfaces.ScopeDefault {
+		t.Errorf("download.timeout = %+v, want the default", got)
+	}
+	if warnings := s.Warnings(); len(warnings) != 2 {
+		t.Errorf("Warnings() = %q, want one for each broken file", warnings)
+	}
+}
+
+func TestFindProjectFilePrefersTOML(t *testing.T) {
+	dir := setupLayers(t, "", constants.ProjectConfigJSONFileName, `{}`)
+	projectDir := filepath.Dir(filepath.Dir(dir))
+	if err := os.WriteFile(filepath.Join(projectDir, constants.ProjectConfigFileName), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FindProjectFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(projectDir, constants.ProjectConfigFileName); got != want {
+		t.Errorf("FindProjectFile() = %s, want %s", got, want)
+	}
+}
+
+func TestDefinitionValidate(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{constants.SettingDownloadMirror, "https://mirrors.example.com/golang/", "https://mirrors.example.com/golang/", false},
+		{constants.SettingDownloadMirror, "ftp://mirrors.example.com/golang/", "", true},
+		{constants.SettingDownloadMirror, "mirrors.example.com", "", true},
+		{constants.SettingDownloadVerify, "strict", "strict", false},
+		{constants.SettingDownloadVerify, "sometimes", "", true},
+		{constants.SettingDownloadTimeout, "90s", "90s", false},
+		{constants.SettingDownloadTimeout, "0s", "", true},
+		{constants.SettingDownloadTimeout, "ten minutes", "", true},
+		{constants.SettingUpdateSwitch, "1", "true", false},
+		{constants.SettingUpdateSwitch, "yes", "", true},
+		{constants.SettingInstallRoot, "", "", true},
+		{constants.SettingCacheMaxSize, "0", "0", false},
+		{constants.SettingCacheMaxSize, "512M", "512M", false},
+		{constants.SettingCacheMaxSize, "-1G", "", true},
+		{constants.SettingCacheMaxSize, "lots", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			def, ok := Lookup(tt.key)
+			if !ok {
+				t.Fatalf("%s is not a known setting", tt.key)
+			}
+			got, err := def.Validate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"0":       0,
+		"1048576": 1 << 20,
+		"512K":    512 << 10,
+		"500m":    500 << 20,
+		"2G":      2 << 30,
+		"2GB":     2 << 30,
+		"2GiB":    2 << 30,
+		"1T":      1 << 40,
+	}
+	for value, want := range tests {
+		if got, err := ParseSize(value); err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "G", "1.5G", "2X", "-5", "99999999999T"} {
+		if _, err := ParseSize(value); err == nil {
+			t.Errorf("ParseSize(%q) succeeded, want error", value)
+		}
+	}
+}
+
+func TestResolveInvalidValue(t *testing.T) {
+	dir := setupLayers(t, "[download]\nretries = \"many\"\n", constants.ProjectConfigFileName, "[download]\ntimeout = \"soon\"\nretries = 5\n")
+
+	// 系统和项目配置中的无效值被忽略，同一文件中的其他配置项仍然生效
+	s, err := Resolve(nil, "", dir)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got := Duration(s, constants.SettingDownloadTimeout, 0); got != constants.DownloadTimeout {
+		t.Errorf("download.timeout = %v, want the default %v", got, constants.DownloadTimeout)
+	}
+	if got := Int(s, constants.SettingDownloadRetries, 0); got != 5 {
+		t.Errorf("download.retries = %d, want 5 from the project", got)
+	}
+	if warnings := s.Warnings(); len(warnings) != 2 {
+		t.Errorf("Warnings() = %q, want one for each invalid value", warnings)
+	}
+
+	// 用户配置中的无效值返回错误
+	_, err = Resolve(map[string]string{constants.SettingDownloadTimeout: "soon"}, "", dir)
+	if !errors.IsType(err, errors.ErrInvalidSetting) {
+		t.Fatalf("Resolve() error = %v, want %v", err, errors.ErrInvalidSetting)
+	}
+}
+
+func TestResolveNormalizesValues(t *testing.T) {
+	dir := setupLayers(t, "", "", "")
+
+	s, err := Resolve(map[string]string{constants.SettingUpgradeRemoveOld: "1"}, "", dir)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if !Bool(s, constants.SettingUpgradeRemoveOld) {
+		t.Errorf("upgrade.remove-old = %q, want true", s.Get(constants.SettingUpgradeRemoveOld))
+	}
+	if got := Duration(s, constants.SettingDownloadTimeout, 0); got != constants.DownloadTimeout {
+		t.Errorf("download.timeout = %v, want %v", got, constants.DownloadTimeout)
+	}
+}
+
+func TestQuoteTOMLRoundTrip(t *testing.T) {
+	for _, value := range []string{"", "plain", `C:\Go`, `say "hi"`, "tab\tand\nnewline", "# not a comment"} {
+		values, err := ParseTOML([]byte("key = " + QuoteTOML(value) + "\n"))
+		if err != nil {
+			t.Fatalf("ParseTOML(QuoteTOML(%q)) error = %v", value, err)
+		}
+		if values["key"] != value {
+			t.Errorf("round trip of %q = %q", value, values["key"])
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// SourceKind 发布源的类型
+type SourceKind string
+
+const (
+	SourceHTTP  SourceKind = "http"  // 与 go.dev/dl 布局相同的 HTTP(S) 镜像
+	SourceDir   SourceKind = "dir"   // 存放官方安装包的本地目录
+	SourceIndex SourceKind = "index" // 本地 JSON 版本列表（file://.../index.json），安装包与列表位于同一目录
+)
+
+// Source download.sources 中的一个发布源
+type Source struct {
+	Kind         SourceKind
+	Location     string        // HTTP 源为以 / 结尾的地址，本地源为绝对路径
+	Name         string        // 记录在安装元数据中的名称，默认为 Location
+	Timeout      time.Duration // 下载安装包的超时时间
+	IndexTimeout time.Duration // 获取版本列表的超时时间
+}
+
+// ParseSources 解析 download.sources 的值
+//
+// 发布源之间用逗号分隔，按顺序尝试。每个发布源是一个位置，后面可以跟空格分隔的选项：
+//
+//	https://artifactory.example.com/go/ name=corp timeout=10m index-timeout=5s, https://golang.google.cn/dl/, https://go.dev/dl/
+//
+// 位置可以是 http(s) 地址、file:// 地址或以 / 或 ~ 开头的本地目录；
+// 指向 .json 文件的 file:// 地址是版本列表，其他本地位置是存放官方安装包的目录。
+// 未指定的超时时间为 0，由 Sources 填入 download.timeout 和 download.index-timeout。
+func ParseSources(value string) ([]Source, error) {
+	var sources []Source
+	for _, entry := range strings.Split(value, ",") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		source, err := parseLocation(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, option := range fields[1:] {
+			key, val, ok := strings.Cut(option, "=")
+			if !ok || val == "" {
+				return nil, fmt.Errorf("invalid option %q for source %s: expected key=value", option, fields[0])
+			}
+			switch key {
+			case "name":
+				source.Name = val
+			case "timeout", "index-timeout":
+				duration, err := time.ParseDuration(val)
+				if err != nil || duration <= 0 {
+					return nil, fmt.Errorf("invalid %s %q for source %s: expected a positive duration such as 30s", key, val, fields[0])
+				}
+				if key == "timeout" {
+					source.Timeout = duration
+				} else {
+					source.IndexTimeout = duration
+				}
+			default:
+				return nil, fmt.Errorf("unknown option %q for source %s: expected name, timeout or index-timeout", key, fields[0])
+			}
+		}
+
+		if source.Name == "" {
+			source.Name = fields[0]
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// parseLocation 识别发布源的类型
+func parseLocation(location string) (Source, error) {
+	u, err := url.Parse(location)
+	if err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		if u.Host == "" {
+			return Source{}, fmt.Errorf("source %q has no host", location)
+		}
+		if !strings.HasSuffix(location, "/") {
+			location += "/"
+		}
+		return Source{Kind: SourceHTTP, Location: location}, nil
+	}
+
+	path := location
+	if err == nil && u.Scheme == "file" {
+		path = u.Path
+		// file:///C:/go 的路径为 /C:/go
+		if runtime.GOOS == constants.OSWindows && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+			path = path[1:]
+		}
+		path = filepath.FromSlash(path)
+	} else if err == nil && len(u.Scheme) > 1 {
+		return Source{}, fmt.Errorf("unsupported scheme %q in source %s: expected http, https or file", u.Scheme, location)
+	}
+
+	path = resolvePath(path, "")
+	if !filepath.IsAbs(path) {
+		return Source{}, fmt.Errorf("source %q must be a URL or an absolute path", location)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return Source{Kind: SourceIndex, Location: path}, nil
+	}
+	return Source{Kind: SourceDir, Location: path}, nil
+}
+
+// Sources 返回按优先级排列的发布源
+// 未设置 download.sources 时只有 download.mirror 一个源；未单独指定的超时时间使用 download.timeout 和 download.index-timeout
+func Sources(s *interfaces.Settings) []Source {
+	timeout := Duration(s, constants.SettingDownloadTimeout, constants.DownloadTimeout)
+	indexTimeout := Duration(s, constants.SettingDownloadIndexTimeout, constants.IndexTimeout)
+
+	sources, err := ParseSources(s.Get(constants.SettingDownloadSources))
+	if err != nil || len(sources) == 0 {
+		mirror := Mirror(s)
+		sources = []Source{{Kind: SourceHTTP, Location: mirror, Name: mirror}}
+	}
+
+	for i := range sources {
+		if sources[i].Timeout == 0 {
+			sources[i].Timeout = timeout
+		}
+		if sources[i].IndexTimeout == 0 {
+			sources[i].IndexTimeout = indexTimeout
+		}
+	}
+	return sources
+}
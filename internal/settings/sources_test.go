@@ -0,0 +1,67 @@
+package settings
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+func TestParseSources(t *testing.T) {
+	if runtime.GOOS == constants.OSWindows {
+		t.Skip("uses Unix paths")
+	}
+
+	got, err := ParseSources("https://artifactory.corp/go name=corp timeout=10m index-timeout=5s, /srv/go-dist , file:///srv/index.json, https://go.dev/dl/")
+	if err != nil {
+		t.Fatalf("ParseSources() error = %v", err)
+	}
+
+	want := []Source{
+		{Kind: SourceHTTP, Location: "https://artifactory.corp/go/", Name: "corp", Timeout: 10 * time.Minute, IndexTimeout: 5 * time.Second},
+		{Kind: SourceDir, Location: "/srv/go-dist", Name: "/srv/go-dist"},
+		{Kind: SourceIndex, Location: "/srv/index.json", Name: "file:///srv/index.json"},
+		{Kind: SourceHTTP, Location: "https://go.dev/dl/", Name: "https://go.dev/dl/"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSources() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseSourcesInvalid(t *testing.T) {
+	for _, value := range []string{
+		"relative/dir",
+		"ftp://mirror.corp/go/",
+		"https:///no-host",
+		"https://go.dev/dl/ name",
+		"https://go.dev/dl/ retries=3",
+		"https://go.dev/dl/ timeout=-1s",
+	} {
+		if _, err := ParseSources(value); err == nil {
+			t.Errorf("ParseSources(%q) error = nil, want error", value)
+		}
+	}
+}
+
+func TestSourcesDefaults(t *testing.T) {
+	s := interfaces.NewSettings()
+	s.Set(interfaces.Setting{Key: constants.SettingDownloadMirror, Value: "https://mirror.corp/go"})
+	s.Set(interfaces.Setting{Key: constants.SettingDownloadTimeout, Value: "20m"})
+
+	// 未设置 download.sources 时使用 download.mirror
+	got := Sources(s)
+	if len(got) != 1 || got[0].Location != "https://mirror.corp/go/" || got[0].Timeout != 20*time.Minute || got[0].IndexTimeout != constants.IndexTimeout {
+		t.Errorf("Sources() = %+v, want the mirror with default timeouts", got)
+	}
+
+	dir := t.TempDir()
+	s.Set(interfaces.Setting{Key: constants.SettingDownloadSources, Value: dir + " timeout=1m, https://go.dev/dl/"})
+	got = Sources(s)
+	if len(got) != 2 || got[0].Location != filepath.Clean(dir) || got[0].Timeout != time.Minute || got[1].Timeout != 20*time.Minute {
+		t.Errorf("Sources() = %+v, want per-source timeouts to override download.timeout", got)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build linux || darwin
+
+package settings
+
+// defaultSystemConfigFile 系统级配置文件的默认路径
+func defaultSystemConfigFile() string {
+	return "/etc/gx/config"
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package settings
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultSystemConfigFile 系统级配置文件的默认路径
+func defaultSystemConfigFile() string {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		programData = `C:\ProgramData`
+	}
+	return filepath.Join(programData, "gx", "config")
+}
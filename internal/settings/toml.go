@@ -0,0 +1,195 @@
+package settings
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseTOML 解析 TOML 的一个子集：表头、键值对、注释，以及字符串、布尔和数字值
+// 表头和点分隔的键都展开为 "table.key" 形式；数组、内联表和多行字符串不受支持
+func ParseTOML(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	table := ""
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		// 表头
+		if line[0] == '[' {
+			if strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: arrays of tables are not supported", i+1)
+			}
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated table header", i+1)
+			}
+			if rest := strings.TrimSpace(line[end+1:]); rest != "" && rest[0] != '#' {
+				return nil, fmt.Errorf("line %d: unexpected %q after table header", i+1, rest)
+			}
+			name, err := parseKey(line[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			table = name
+			continue
+		}
+
+		// 键值对
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		key, err := parseKey(line[:eq])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		value, err := parseValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+		if table != "" {
+			key = table + "." + key
+		}
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %s", i+1, key)
+		}
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// parseKey 解析由字母、数字、- 和 _ 组成、以 . 分隔的键
+func parseKey(s string) (string, error) {
+	parts := strings.Split(s, ".")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return "", fmt.Errorf("invalid key %q", strings.TrimSpace(s))
+		}
+		for _, r := range part {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return "", fmt.Errorf("invalid key %q", strings.TrimSpace(s))
+			}
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// parseValue 解析值，值后面可以跟注释
+func parseValue(s string) (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("missing value")
+	}
+
+	var value, rest string
+	switch s[0] {
+	case '"':
+		if strings.HasPrefix(s, `"""`) {
+			return "", fmt.Errorf("multi-line strings are not supported")
+		}
+		var b strings.Builder
+		closed := false
+		i := 1
+		for ; i < len(s); i++ {
+			c := s[i]
+			if c == '"' {
+				closed = true
+				break
+			}
+			if c != '\\' {
+				b.WriteByte(c)
+				continue
+			}
+			if i++; i == len(s) {
+				break
+			}
+			switch s[i] {
+			case '"', '\\':
+				b.WriteByte(s[i])
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				return "", fmt.Errorf("unsupported escape sequence \\%c", s[i])
+			}
+		}
+		if !closed {
+			return "", fmt.Errorf("unterminated string")
+		}
+		value, rest = b.String(), s[i+1:]
+
+	case '\'':
+		if strings.HasPrefix(s, "'''") {
+			return "", fmt.Errorf("multi-line strings are not supported")
+		}
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		value, rest = s[1:end+1], s[end+2:]
+
+	default:
+		token := s
+		if hash := strings.IndexByte(token, '#'); hash >= 0 {
+			token = token[:hash]
+		}
+		token = strings.TrimSpace(token)
+
+		switch {
+		case token == "true" || token == "false":
+			return token, nil
+		case isNumber(token):
+			return strings.ReplaceAll(token, "_", ""), nil
+		default:
+			return "", fmt.Errorf("unsupported value %q (strings must be quoted)", token)
+		}
+	}
+
+	if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+		return "", fmt.Errorf("unexpected %q after value", rest)
+	}
+	return value, nil
+}
+
+// QuoteTOML 将字符串格式化为 TOML 基本字符串
+func QuoteTOML(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// isNumber 判断是否为 TOML 整数或浮点数
+func isNumber(token string) bool {
+	token = strings.ReplaceAll(token, "_", "")
+	if _, err := strconv.ParseInt(token, 10, 64); err == nil {
+		return true
+	}
+	_, err := strconv.ParseFloat(token, 64)
+	return err == nil && !strings.ContainsAny(token, "xXpP") && token != "NaN" && !strings.Contains(strings.ToLower(token), "inf")
+}
@@ -0,0 +1,163 @@
+package shim
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/kawaiirei0/gx/internal/config"
+	"github.com/kawaiirei0/gx/internal/platform"
+	"github.com/kawaiirei0/gx/internal/version"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// ShimDirName shims 目录名（位于配置目录下）
+const ShimDirName = "shims"
+
+// Tools 需要生成 shim 的工具列表
+var Tools = []string{"go", "gofmt"}
+
+// GetShimDir 获取 shims 目录路径
+func GetShimDir() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, ShimDirName), nil
+}
+
+// ToolFromArgv0 判断程序是否以 shim 的身份被调用
+// 返回对应的工具名，例如通过 ~/.gx/shims/go 调用时返回 "go"
+func ToolFromArgv0(argv0 string) (string, bool) {
+	name := strings.ToLower(filepath.Base(argv0))
+	name = strings.TrimSuffix(name, ".exe")
+
+	for _, tool := range Tools {
+		if name == tool {
+			return tool, true
+		}
+	}
+	return "", false
+}
+
+// Rehash 重新生成 shims 目录中的所有启动器
+// gxExe 为 gx 可执行文件的绝对路径
+func Rehash(gxExe string) (string, error) {
+	shimDir, err := GetShimDir()
+	if err != nil {
+		return "", errors.ErrStorageFailed.WithCause(err).WithMessage("failed to get shim directory")
+	}
+
+	if err := os.MkdirAll(shimDir, 0755); err != nil {
+		return "", errors.ErrStorageFailed.WithCause(err).WithMessage("failed to create shim directory").
+			WithContext("shim_dir", shimDir)
+	}
+
+	for _, tool := range Tools {
+		if err := writeShim(shimDir, tool, gxExe); err != nil {
+			return "", errors.ErrOperationFailed.WithCause(err).
+				WithMessage(fmt.Sprintf("failed to create shim for %s", tool)).
+				WithContext("shim_dir", shimDir)
+		}
+	}
+
+	return shimDir, nil
+}
+
+// Resolve 解析当前目录下工具对应的真实可执行文件路径
+func Resolve(tool string) (string, error) {
+	// 从 PATH 中移除 shims 目录，避免回退到系统 Go 时再次调用自身
+	if shimDir, err := GetShimDir(); err == nil {
+		os.Setenv(constants.EnvPath, removeFromPath(os.Getenv(constants.EnvPath), shimDir))
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return "", err
+	}
+
+	storage, err := config.NewStorage(store)
+	if err != nil {
+		return "", err
+	}
+
+	// shim 只需要版本解析和使用记录，不涉及下载、安装和环境变量修改
+	manager := version.NewManager(store, storage, platform.NewAdapter(), nil, nil, nil)
+	resolved, err := manager.Resolve("")
+	if err != nil {
+		return "", err
+	}
+
+	manager.RecordUsage(resolved.Version)
+
+	return toolPath(resolved, tool)
+}
+
+// Main 以 shim 方式运行工具，返回进程退出码
+func Main(tool string, args []string) int {
+	toolExe, err := Resolve(tool)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gx: %v\n", err)
+		return 1
+	}
+
+	if err := execTool(toolExe, tool, args); err != nil {
+		if exitErr, ok := err.(*exitCodeError); ok {
+			return exitErr.code
+		}
+		fmt.Fprintf(os.Stderr, "gx: failed to run %s: %v\n", toolExe, err)
+		return 1
+	}
+
+	return 0
+}
+
+// toolPath 构建已解析版本中工具的可执行文件路径
+func toolPath(resolved *interfaces.ResolvedVersion, tool string) (string, error) {
+	if resolved.Path == "" {
+		return "", errors.ErrVersionNotFound.WithMessage("resolved version path is empty")
+	}
+
+	exe := tool
+	if runtime.GOOS == constants.OSWindows {
+		exe += ".exe"
+	}
+
+	exePath := filepath.Join(resolved.Path, "bin", exe)
+	if _, err := os.Stat(exePath); err != nil {
+		return "", errors.ErrNotFound.
+			WithMessage(fmt.Sprintf("%s not found in Go %s", exe, goversion.Display(resolved.Version))).
+			WithContext("path", exePath)
+	}
+
+	return exePath, nil
+}
+
+// removeFromPath 从 PATH 中移除指定目录
+func removeFromPath(pathEnv, dir string) string {
+	cleanDir := filepath.Clean(dir)
+
+	var kept []string
+	for _, p := range filepath.SplitList(pathEnv) {
+		if p != "" && filepath.Clean(p) == cleanDir {
+			continue
+		}
+		kept = append(kept, p)
+	}
+
+	return strings.Join(kept, string(os.PathListSeparator))
+}
+
+// exitCodeError 表示被执行的工具以非零退出码结束
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
@@ -0,0 +1,105 @@
+package shim
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// setupHome 创建一个临时 HOME，其中包含一个已安装的假 Go 版本
+func setupHome(t testing.TB) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GX_VERSION", "")
+	t.Setenv("GX_HOME", "")
+	t.Setenv("GX_CONFIG", "")
+
+	goroot := filepath.Join(home, ".gx", "versions", "go1.21.5")
+	binDir := filepath.Join(goroot, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	exe := "go"
+	if runtime.GOOS == "windows" {
+		exe = "go.exe"
+	}
+	if err := os.WriteFile(filepath.Join(binDir, exe), []byte{}, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := interfaces.Config{
+		ActiveVersion: "go1.21.5",
+		Versions:      map[string]string{"go1.21.5": goroot},
+	}
+	data, _ := json.Marshal(cfg)
+	if err := os.WriteFile(filepath.Join(home, ".gx", "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return home
+}
+
+func TestToolFromArgv0(t *testing.T) {
+	tests := []struct {
+		argv0 string
+		tool  string
+		ok    bool
+	}{
+		{"/home/user/.gx/shims/go", "go", true},
+		{"gofmt", "gofmt", true},
+		{"go.exe", "go", true},
+		{"/usr/local/bin/gx", "", false},
+	}
+
+	for _, tt := range tests {
+		tool, ok := ToolFromArgv0(tt.argv0)
+		if ok != tt.ok || tool != tt.tool {
+			t.Errorf("ToolFromArgv0(%q) = (%q, %v), want (%q, %v)", tt.argv0, tool, ok, tt.tool, tt.ok)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	home := setupHome(t)
+
+	exePath, err := Resolve("go")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := filepath.Join(home, ".gx", "versions", "go1.21.5", "bin", "go")
+	if runtime.GOOS == "windows" {
+		want += ".exe"
+	}
+	if exePath != want {
+		t.Errorf("Resolve() = %s, want %s", exePath, want)
+	}
+}
+
+func TestRemoveFromPath(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	pathEnv := "/a" + sep + "/shims" + sep + "/b"
+
+	got := removeFromPath(pathEnv, "/shims/")
+	if got != "/a"+sep+"/b" {
+		t.Errorf("removeFromPath() = %s", got)
+	}
+}
+
+// BenchmarkResolve 测量每次 shim 调用的解析开销（目标：几毫秒以内）
+func BenchmarkResolve(b *testing.B) {
+	setupHome(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Resolve("go"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
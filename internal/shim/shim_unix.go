@@ -0,0 +1,36 @@
+//go:build linux || darwin
+
+package shim
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// writeShim 创建指向 gx 可执行文件的符号链接
+// gx 通过 argv[0] 识别自己是以哪个工具的身份被调用
+func writeShim(shimDir, tool, gxExe string) error {
+	target := filepath.Join(shimDir, tool)
+
+	// 先创建临时链接再重命名，避免替换过程中出现短暂缺失
+	tmpLink := target + ".tmp"
+	os.Remove(tmpLink)
+	if err := os.Symlink(gxExe, tmpLink); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpLink, target); err != nil {
+		os.Remove(tmpLink)
+		return err
+	}
+
+	return nil
+}
+
+// execTool 用真实工具替换当前进程
+// 标准输入输出、信号和退出码都直接由目标进程处理
+func execTool(toolExe, tool string, args []string) error {
+	argv := append([]string{tool}, args...)
+	return syscall.Exec(toolExe, argv, os.Environ())
+}
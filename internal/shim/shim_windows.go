@@ -0,0 +1,35 @@
+//go:build windows
+
+package shim
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// writeShim 创建调用 gx 的批处理启动器
+func writeShim(shimDir, tool, gxExe string) error {
+	target := filepath.Join(shimDir, tool+".cmd")
+	content := fmt.Sprintf("@echo off\r\n\"%s\" shim exec %s %%*\r\n", gxExe, tool)
+
+	return os.WriteFile(target, []byte(content), 0755)
+}
+
+// execTool 运行真实工具并透传标准输入输出和退出码
+func execTool(toolExe, tool string, args []string) error {
+	cmd := exec.Command(toolExe, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return &exitCodeError{code: exitErr.ExitCode()}
+		}
+		return err
+	}
+
+	return nil
+}
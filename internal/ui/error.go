@@ -58,7 +58,16 @@ func (ef *ErrorFormatter) formatGxError(err *errors.Error) {
 }
 
 // provideSuggestions 根据错误类型提供解决建议
+// 建议按原因链中最内层的 gx 错误给出，外层通常只是"加载配置失败"之类的包装
 func (ef *ErrorFormatter) provideSuggestions(err *errors.Error) {
+	for {
+		inner, ok := err.Cause.(*errors.Error)
+		if !ok {
+			break
+		}
+		err = inner
+	}
+
 	suggestions := ef.getSuggestions(err)
 	if len(suggestions) == 0 {
 		return
@@ -107,8 +116,12 @@ func (ef *ErrorFormatter) getSuggestions(err *errors.Error) []string {
 		suggestions = append(suggestions,
 			"Check your internet connection",
 			"Ensure you have enough disk space",
-			"Try downloading again",
 		)
+		if _, ok := err.GetContext("partial_download"); ok {
+			suggestions = append(suggestions, "Run the command again to resume the download where it stopped")
+		} else {
+			suggestions = append(suggestions, "Try downloading again")
+		}
 
 	case strings.Contains(err.Code, "CHECKSUM_MISMATCH"):
 		suggestions = append(suggestions,
@@ -141,11 +154,36 @@ func (ef *ErrorFormatter) getSuggestions(err *errors.Error) []string {
 
 	case strings.Contains(err.Code, "STORAGE_FAILED"):
 		suggestions = append(suggestions,
-			"Check if the configuration directory (~/.gx) is writable",
+			"Check if the gx home directory ($GX_HOME, default ~/.gx) is writable",
 			"Ensure you have enough disk space",
 			"Verify file permissions",
 		)
 
+	case strings.Contains(err.Code, "CONFIG_SCHEMA_UNSUPPORTED"):
+		suggestions = append(suggestions,
+			"Upgrade gx to the version that last wrote the configuration, or newer",
+			"Backups of older schema versions are kept next to the config as config.json.v<N>.bak",
+		)
+
+	case strings.Contains(err.Code, "INVALID_SETTING"):
+		suggestions = append(suggestions,
+			"Run 'gx config list --show-origin' to see every setting and the file it comes from",
+			"Fix user settings with 'gx config set', 'gx config unset' or 'gx config edit'",
+			"Settings from /etc/gx/config, gx.toml or .gx.json must be fixed in that file",
+		)
+
+	case strings.Contains(err.Code, "LOCKED"):
+		suggestions = append(suggestions,
+			"Another gx process is installing or updating the configuration; wait for it to finish",
+			"If that process is stuck, stop it; locks of exited processes are cleaned up automatically",
+		)
+
+	case strings.Contains(err.Code, "CANCELLED"):
+		suggestions = append(suggestions, "Changes made by the interrupted operation were rolled back")
+		if _, ok := err.GetContext("partial_download"); ok {
+			suggestions = append(suggestions, "Run the command again to resume the download where it stopped")
+		}
+
 	case strings.Contains(err.Code, "PLATFORM_NOT_SUPPORTED"):
 		suggestions = append(suggestions,
 			"Your platform may not be supported by this Go version",
@@ -156,7 +194,7 @@ func (ef *ErrorFormatter) getSuggestions(err *errors.Error) []string {
 	default:
 		suggestions = append(suggestions,
 			"Run with --verbose flag for more details",
-			"Check the logs at ~/.gx/logs/gx.log",
+			"Check the logs at $GX_HOME/logs/gx.log (default ~/.gx/logs/gx.log)",
 		)
 	}
 
@@ -7,11 +7,11 @@ import (
 )
 
 // OutputStreamer 实时输出流处理器
+// 每行以一次 Write 输出；多个 OutputStreamer 写入同一 writer 时，应传入 NewSyncWriter 包装后的 writer
 type OutputStreamer struct {
 	reader io.Reader
 	writer io.Writer
 	prefix string
-	mu     sync.Mutex
 }
 
 // NewOutputStreamer 创建新的输出流处理器
@@ -23,17 +23,30 @@ func NewOutputStreamer(reader io.Reader, writer io.Writer, prefix string) *Outpu
 	}
 }
 
+// syncWriter 串行化 Write 调用的 writer
+type syncWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewSyncWriter 返回串行化写入的 writer，可在多个 goroutine 中共用
+func NewSyncWriter(writer io.Writer) io.Writer {
+	return &syncWriter{writer: writer}
+}
+
+// Write 在锁内写入底层 writer
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.writer.Write(p)
+}
+
 // Stream 开始流式输出
 func (os *OutputStreamer) Stream() error {
 	scanner := bufio.NewScanner(os.reader)
 	for scanner.Scan() {
-		os.mu.Lock()
-		if os.prefix != "" {
-			io.WriteString(os.writer, os.prefix)
-		}
-		io.WriteString(os.writer, scanner.Text())
-		io.WriteString(os.writer, "\n")
-		os.mu.Unlock()
+		os.writeLine(scanner.Text())
 	}
 
 	return scanner.Err()
@@ -45,13 +58,7 @@ func (os *OutputStreamer) StreamWithCallback(callback func(line string)) error {
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		os.mu.Lock()
-		if os.prefix != "" {
-			io.WriteString(os.writer, os.prefix)
-		}
-		io.WriteString(os.writer, line)
-		io.WriteString(os.writer, "\n")
-		os.mu.Unlock()
+		os.writeLine(line)
 
 		if callback != nil {
 			callback(line)
@@ -60,3 +67,9 @@ func (os *OutputStreamer) StreamWithCallback(callback func(line string)) error {
 
 	return scanner.Err()
 }
+
+// writeLine 以一次写入输出带前缀的整行
+// 多个 OutputStreamer 共享同一个 NewSyncWriter 时（如并行执行多个版本），各行不会相互交错
+func (os *OutputStreamer) writeLine(line string) {
+	io.WriteString(os.writer, os.prefix+line+"\n")
+}
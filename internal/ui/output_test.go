@@ -0,0 +1,71 @@
+package ui_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/ui"
+)
+
+// overlapWriter 统计同时进行的 Write 调用
+type overlapWriter struct {
+	active   int32
+	overlaps int32
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *overlapWriter) Write(p []byte) (int, error) {
+	if atomic.AddInt32(&w.active, 1) > 1 {
+		atomic.AddInt32(&w.overlaps, 1)
+	}
+	defer atomic.AddInt32(&w.active, -1)
+
+	time.Sleep(10 * time.Microsecond)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestOutputStreamersShareSyncWriter(t *testing.T) {
+	const lines = 200
+
+	writer := &overlapWriter{}
+	shared := ui.NewSyncWriter(writer)
+	var wg sync.WaitGroup
+	for _, prefix := range []string{"[go1.21.13] ", "[go1.22.5] "} {
+		var input strings.Builder
+		for i := 0; i < lines; i++ {
+			fmt.Fprintf(&input, "line %d\n", i)
+		}
+
+		streamer := ui.NewOutputStreamer(strings.NewReader(input.String()), shared, prefix)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			streamer.Stream()
+		}()
+	}
+	wg.Wait()
+
+	if writer.overlaps > 0 {
+		t.Errorf("%d writes overlapped", writer.overlaps)
+	}
+
+	output := strings.Split(strings.TrimSuffix(writer.buf.String(), "\n"), "\n")
+	if len(output) != 2*lines {
+		t.Fatalf("got %d lines, want %d", len(output), 2*lines)
+	}
+	for _, line := range output {
+		if !strings.HasPrefix(line, "[go1.21.13] line ") && !strings.HasPrefix(line, "[go1.22.5] line ") {
+			t.Errorf("garbled line %q", line)
+		}
+	}
+}
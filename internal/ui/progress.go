@@ -92,21 +92,21 @@ func (pb *ProgressBar) render() {
 		pb.prefix,
 		bar,
 		percent*100,
-		formatBytes(pb.current),
-		formatBytes(pb.total),
+		FormatBytes(pb.current),
+		FormatBytes(pb.total),
 	)
 
 	// 显示速度和预计时间
 	if speed > 0 {
-		fmt.Fprintf(pb.writer, " | %s/s", formatBytes(int64(speed)))
+		fmt.Fprintf(pb.writer, " | %s/s", FormatBytes(int64(speed)))
 	}
 	if eta > 0 && pb.current < pb.total {
 		fmt.Fprintf(pb.writer, " | ETA: %s", formatDuration(eta))
 	}
 }
 
-// formatBytes 格式化字节数
-func formatBytes(bytes int64) string {
+// FormatBytes 格式化字节数（如 "1.5 MB"）
+func FormatBytes(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
 		return fmt.Sprintf("%d B", bytes)
@@ -6,6 +6,8 @@ import (
 	"io"
 	"strconv"
 	"strings"
+
+	"github.com/kawaiirei0/gx/pkg/goversion"
 )
 
 // Prompter 交互式提示器
@@ -145,7 +147,7 @@ func (p *Prompter) SelectVersion(versions []string, pageSize int) (string, error
 
 		// 尝试作为版本号解析
 		for _, version := range versions {
-			if strings.Contains(version, input) || strings.Contains(strings.TrimPrefix(version, "go"), input) {
+			if strings.Contains(version, input) || strings.Contains(goversion.Display(version), input) {
 				return version, nil
 			}
 		}
@@ -212,7 +214,7 @@ func (p *Prompter) SelectVersion(versions []string, pageSize int) (string, error
 
 		// 尝试作为版本号解析
 		for _, version := range versions {
-			if strings.Contains(version, input) || strings.Contains(strings.TrimPrefix(version, "go"), input) {
+			if strings.Contains(version, input) || strings.Contains(goversion.Display(version), input) {
 				return version, nil
 			}
 		}
@@ -0,0 +1,261 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/utils"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// Adopt 将外部安装的 Go 工具链注册为链接版本
+// path 可以是 GOROOT、其中的 bin 目录或 go 可执行文件；gx 不拥有链接版本的文件，卸载时只取消注册
+func (m *manager) Adopt(path string) (string, error) {
+	goRoot, err := m.goRootFromPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Info("Adopting Go toolchain at %s", goRoot)
+
+	version, err := m.toolchainVersion(goRoot)
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.installer.Verify(goRoot, version); err != nil {
+		logger.Error("Toolchain verification failed: %v", err)
+		return "", err
+	}
+
+	// gx 自己的安装目录由 gx 管理，不作为链接版本
+	installRoot, err := m.installRoot()
+	if err != nil {
+		return "", err
+	}
+	if installPath, err := filepath.Abs(installRoot); err == nil && isWithin(goRoot, installPath) {
+		return "", errors.ErrInvalidInput.WithMessage(fmt.Sprintf("%s is inside the gx install directory", goRoot))
+	}
+
+	registered := false
+	err = m.configStore.Update(func(cfg *interfaces.Config) error {
+		if existing, ok := cfg.Versions[version]; ok {
+			if samePath(existing, goRoot) {
+				registered = true
+				return nil
+			}
+			return errors.ErrVersionAlreadyInstalled.
+				WithMessage(fmt.Sprintf("version %s is already registered at %s", version, existing)).
+				WithContext("path", goRoot)
+		}
+
+		if cfg.Versions == nil {
+			cfg.Versions = make(map[string]string)
+		}
+		if cfg.Linked == nil {
+			cfg.Linked = make(map[string]bool)
+		}
+		cfg.Versions[version] = goRoot
+		cfg.Linked[version] = true
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to register %s: %v", version, err)
+		return "", err
+	}
+	if registered {
+		logger.Info("Go %s at %s is already registered", version, goRoot)
+		return version, nil
+	}
+
+	record := &interfaces.GoVersion{
+		Version:     version,
+		Path:        goRoot,
+		InstallDate: time.Now(),
+		Linked:      true,
+		Origin:      interfaces.OriginAdopted,
+		SourceURL:   goRoot,
+	}
+	if size, err := utils.DirSize(goRoot); err == nil {
+		record.Size = size
+	}
+	if err := m.storage.SaveVersion(record); err != nil {
+		logger.Warn("Failed to save metadata for %s: %v", version, err)
+	}
+
+	logger.Info("Go %s at %s adopted", version, goRoot)
+	return version, nil
+}
+
+// ScanToolchains 在常见位置查找外部安装的 Go 工具链，返回 GOROOT 列表
+// 结果不包含 gx 自己安装的版本，也不做注册
+func (m *manager) ScanToolchains() ([]string, error) {
+	installRoot, err := m.installRoot()
+	if err != nil {
+		return nil, err
+	}
+	installPath, _ := filepath.Abs(installRoot)
+
+	var candidates []string
+	for _, pattern := range m.toolchainPatterns() {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, matches...)
+	}
+
+	// PATH 中的 go 也可能来自其他位置（如 asdf、自定义目录）
+	if goPath, err := exec.LookPath(m.goExecutableName()); err == nil {
+		candidates = append(candidates, goPath)
+	}
+
+	var roots []string
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		goRoot, err := m.goRootFromPath(candidate)
+		if err != nil {
+			continue
+		}
+		if seen[goRoot] || (installPath != "" && isWithin(goRoot, installPath)) {
+			continue
+		}
+		seen[goRoot] = true
+		roots = append(roots, goRoot)
+	}
+
+	logger.Info("Found %d Go toolchains", len(roots))
+	return roots, nil
+}
+
+// goRootFromPath 将用户给出的路径规范化为 GOROOT 的真实路径
+func (m *manager) goRootFromPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", errors.ErrInvalidInput.WithCause(err).WithMessage(fmt.Sprintf("invalid path %s", path))
+	}
+
+	// 解析符号链接（如 Homebrew 的 /usr/local/bin/go），得到真实的安装位置
+	resolved, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return "", errors.ErrNotFound.WithCause(err).WithMessage(fmt.Sprintf("path %s does not exist", path))
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", errors.ErrNotFound.WithCause(err).WithMessage(fmt.Sprintf("path %s does not exist", path))
+	}
+
+	// 支持直接指定 go 可执行文件或 bin 目录
+	goRoot := resolved
+	if !info.IsDir() {
+		goRoot = filepath.Dir(goRoot)
+	}
+	if filepath.Base(goRoot) == "bin" && !m.isValidGoInstallation(goRoot) {
+		goRoot = filepath.Dir(goRoot)
+	}
+
+	if !m.isValidGoInstallation(goRoot) {
+		return "", errors.ErrInvalidInput.WithMessage(fmt.Sprintf("%s is not a Go installation (bin/%s not found)", path, m.goExecutableName()))
+	}
+
+	return goRoot, nil
+}
+
+// toolchainVersion 运行 go version 获取工具链版本
+func (m *manager) toolchainVersion(goRoot string) (string, error) {
+	goPath := filepath.Join(goRoot, "bin", m.goExecutableName())
+	output, err := exec.Command(goPath, "version").Output()
+	if err != nil {
+		return "", errors.ErrOperationFailed.WithCause(err).WithMessage(fmt.Sprintf("failed to run %s version", goPath))
+	}
+
+	parsed, err := goversion.ParseVersionOutput(string(output))
+	if err != nil {
+		return "", errors.ErrInvalidVersion.WithCause(err).WithMessage(fmt.Sprintf("unrecognized go version output from %s", goPath))
+	}
+
+	return parsed.String(), nil
+}
+
+// goExecutableName 返回当前平台的 go 可执行文件名
+func (m *manager) goExecutableName() string {
+	if m.platform.GetOS() == constants.OSWindows {
+		return "go.exe"
+	}
+	return "go"
+}
+
+// toolchainPatterns 返回常见 Go 安装位置的 glob 模式
+func (m *manager) toolchainPatterns() []string {
+	home, err := m.platform.GetHomeDir()
+	if err != nil {
+		home = ""
+	}
+
+	if m.platform.GetOS() == constants.OSWindows {
+		patterns := []string{`C:\Go`}
+		for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)"} {
+			if dir := os.Getenv(env); dir != "" {
+				patterns = append(patterns, filepath.Join(dir, "Go"))
+			}
+		}
+		if home != "" {
+			patterns = append(patterns,
+				filepath.Join(home, "sdk", "go*"),                                    // golang.org/dl
+				filepath.Join(home, "scoop", "apps", "go", "current"),                // Scoop
+				filepath.Join(home, "go", "pkg", "mod", "golang.org", "toolchain@*"), // GOTOOLCHAIN 下载
+			)
+		}
+		return patterns
+	}
+
+	patterns := []string{
+		"/usr/local/go",
+		"/usr/lib/go",
+		"/usr/lib/go-*",                // Debian/Ubuntu golang-1.xx-go
+		"/usr/lib/golang",              // Fedora/RHEL
+		"/snap/go/current",             // Snap
+		"/usr/local/opt/go/libexec",    // Homebrew (Intel)
+		"/opt/homebrew/opt/go/libexec", // Homebrew (Apple Silicon)
+		"/usr/local/Cellar/go/*/libexec",
+		"/opt/homebrew/Cellar/go/*/libexec",
+		"/opt/homebrew/opt/go@*/libexec",
+		"/usr/local/opt/go@*/libexec",
+	}
+	if home != "" {
+		patterns = append(patterns,
+			filepath.Join(home, "sdk", "go*"),
+			filepath.Join(home, "go", "pkg", "mod", "golang.org", "toolchain@*"),
+		)
+	}
+	return patterns
+}
+
+// isWithin 检查 path 是否位于 dir 之内（含 dir 本身）
+func isWithin(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// samePath 检查两个路径解析符号链接后是否相同
+func samePath(a string, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,104 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// aliasNamePattern 别名只能由字母开头，包含字母、数字、- 和 _
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// ValidateAliasName 验证别名是否合法
+// 别名不能与版本号或关键字（latest/stable/oldstable）冲突
+func ValidateAliasName(name string) error {
+	if !aliasNamePattern.MatchString(name) || goversion.IsSpec(name) {
+		return errors.ErrInvalidInput.
+			WithMessage(fmt.Sprintf("invalid alias name %q: must start with a letter and contain only letters, digits, '-' or '_'", name))
+	}
+	return nil
+}
+
+// IsAliasName 检查字符串是否可以作为别名使用
+func IsAliasName(name string) bool {
+	return ValidateAliasName(name) == nil
+}
+
+// SetAlias 设置版本别名
+// spec 可以是精确版本、版本线或关键字，但不能是另一个别名
+func (m *manager) SetAlias(name string, spec string) error {
+	if err := ValidateAliasName(name); err != nil {
+		return err
+	}
+
+	parsed, err := goversion.ParseSpec(spec)
+	if err != nil {
+		return errors.ErrInvalidVersion.WithMessage(fmt.Sprintf("invalid version %q", spec))
+	}
+
+	err = m.configStore.Update(func(cfg *interfaces.Config) error {
+		if isNamedVersion(cfg, name) {
+			return errors.ErrInvalidInput.WithMessage(fmt.Sprintf("%s is the name of an installed version built from source", name))
+		}
+		if _, ok := cfg.Aliases[strings.TrimSpace(spec)]; ok {
+			return errors.ErrInvalidInput.WithMessage(fmt.Sprintf("alias %s cannot point to another alias (%s)", name, spec))
+		}
+
+		if parsed.IsExact() {
+			if _, ok := cfg.Versions[parsed.Version.String()]; !ok {
+				logger.Warn("Alias %s points to %s, which is not installed yet", name, parsed)
+			}
+		}
+
+		if cfg.Aliases == nil {
+			cfg.Aliases = make(map[string]string)
+		}
+		cfg.Aliases[name] = parsed.String()
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set alias %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Alias %s set to %s", name, parsed)
+	return nil
+}
+
+// RemoveAlias 删除版本别名
+func (m *manager) RemoveAlias(name string) error {
+	err := m.configStore.Update(func(cfg *interfaces.Config) error {
+		if _, ok := cfg.Aliases[name]; !ok {
+			return errors.ErrNotFound.WithMessage(fmt.Sprintf("alias %s does not exist", name))
+		}
+		delete(cfg.Aliases, name)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to remove alias %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Alias %s removed", name)
+	return nil
+}
+
+// ListAliases 列出所有版本别名
+func (m *manager) ListAliases() (map[string]string, error) {
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	aliases := make(map[string]string, len(cfg.Aliases))
+	for name, spec := range cfg.Aliases {
+		aliases[name] = spec
+	}
+	return aliases, nil
+}
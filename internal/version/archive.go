@@ -0,0 +1,167 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/utils"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// sha256Pattern 十六进制的 SHA256 校验和
+var sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// InstallArchive 从本地安装包离线安装
+// 版本号依次取自 opts.Version、文件名和安装包中的 VERSION 文件；安装包原样保留
+func (m *manager) InstallArchive(ctx context.Context, archivePath string, opts interfaces.ArchiveOptions) (string, error) {
+	archivePath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return "", errors.ErrInvalidInput.WithCause(err).WithMessage("invalid archive path").WithContext("path", archivePath)
+	}
+	if info, err := os.Stat(archivePath); err != nil || info.IsDir() {
+		return "", errors.ErrInvalidInput.
+			WithCause(err).
+			WithMessage(fmt.Sprintf("archive %s does not exist or is not a file", archivePath)).
+			WithContext("path", archivePath)
+	}
+
+	version, err := m.archiveVersion(archivePath, opts.Version)
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.verifyArchive(archivePath, opts.SHA256); err != nil {
+		return "", err
+	}
+
+	logger.Info("Installing Go %s from local archive %s", version, archivePath)
+	err = m.installArchive(ctx, version, archiveSource{
+		origin: interfaces.OriginArchive,
+		fetch: func(string, *errors.RecoveryManager) (*fetchedArchive, error) {
+			sourceURL := opts.Source
+			if sourceURL == "" {
+				sourceURL = archivePath
+			}
+			return &fetchedArchive{path: archivePath, sourceURL: sourceURL}, nil
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// archiveVersion 确定安装包中的版本号
+// 文件名符合官方命名时检查平台是否匹配；否则读取安装包中的 VERSION 文件
+func (m *manager) archiveVersion(archivePath string, explicit string) (string, error) {
+	name := filepath.Base(archivePath)
+	parsed, official := goversion.ParseFilename(name)
+	if official && parsed.IsSource() {
+		return "", errors.ErrInvalidInput.
+			WithMessage(fmt.Sprintf("%s is a source archive; use 'gx install --from-source' to build it", name)).
+			WithContext("path", archivePath)
+	}
+	if official && (parsed.OS != m.platform.GetOS() || parsed.Arch != m.platform.GetArch()) {
+		return "", errors.ErrInvalidInput.
+			WithMessage(fmt.Sprintf("%s is built for %s/%s, but this machine is %s/%s", name, parsed.OS, parsed.Arch, m.platform.GetOS(), m.platform.GetArch())).
+			WithContext("path", archivePath)
+	}
+
+	if explicit != "" {
+		version, err := goversion.Parse(explicit)
+		if err != nil || !(version.HasPatch || version.IsPrerelease()) {
+			return "", errors.ErrInvalidVersion.
+				WithMessage(fmt.Sprintf("%q is not an exact version; give the full version of the archive, e.g. 1.22.3", explicit))
+		}
+		return version.String(), nil
+	}
+
+	if official {
+		return parsed.Version, nil
+	}
+
+	logger.Info("Archive name %s does not include a version, reading %s", name, constants.VersionFileInArchive)
+	return m.installer.ArchiveVersion(archivePath)
+}
+
+// verifyArchive 校验安装包的 SHA256
+// 优先使用 expected，其次是安装包旁的 .sha256 文件；都没有时按 download.verify 策略处理
+func (m *manager) verifyArchive(archivePath string, expected string) error {
+	policy := constants.VerifyAuto
+	if effective, err := m.configStore.Settings(""); err == nil {
+		policy = effective.Get(constants.SettingDownloadVerify)
+	}
+
+	source := "--sha256"
+	if expected == "" {
+		sum, sidecar, err := readChecksumFile(archivePath)
+		if err != nil {
+			return err
+		}
+		expected, source = sum, sidecar
+	}
+
+	switch {
+	case expected == "" && policy == constants.VerifyStrict:
+		return errors.ErrChecksumMismatch.
+			WithMessage(fmt.Sprintf("no checksum for %s and %s is %s; pass --sha256 or put a %s file next to the archive",
+				filepath.Base(archivePath), constants.SettingDownloadVerify, constants.VerifyStrict, constants.ChecksumFileSuffix)).
+			WithContext("path", archivePath)
+	case expected == "":
+		logger.Warn("No checksum for %s, installing without verification", archivePath)
+		return nil
+	case policy == constants.VerifyOff && source != "--sha256":
+		logger.Warn("Skipping checksum verification (%s = %s)", constants.SettingDownloadVerify, constants.VerifyOff)
+		return nil
+	}
+
+	if !sha256Pattern.MatchString(expected) {
+		return errors.ErrInvalidInput.
+			WithMessage(fmt.Sprintf("invalid SHA256 %q from %s: expected 64 hexadecimal characters", expected, source))
+	}
+
+	actual, err := utils.FileSHA256(archivePath)
+	if err != nil {
+		return errors.ErrChecksumMismatch.WithCause(err).WithMessage("failed to calculate checksum").WithContext("path", archivePath)
+	}
+	if !strings.EqualFold(actual, expected) {
+		return errors.ErrChecksumMismatch.
+			WithMessage(fmt.Sprintf("checksum mismatch for %s: expected %s (from %s), got %s", filepath.Base(archivePath), strings.ToLower(expected), source, actual)).
+			WithContext("path", archivePath)
+	}
+
+	logger.Info("Checksum of %s verified against %s", archivePath, source)
+	return nil
+}
+
+// readChecksumFile 读取安装包旁的 .sha256 文件，不存在时返回空字符串
+// 文件可以只包含校验和（go.dev/dl 的格式），也可以是 sha256sum 的输出
+func readChecksumFile(archivePath string) (string, string, error) {
+	path := archivePath + constants.ChecksumFileSuffix
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", errors.ErrOperationFailed.WithCause(err).WithMessage("failed to read checksum file").WithContext("path", path)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", "", errors.ErrInvalidInput.WithMessage(fmt.Sprintf("checksum file %s is empty", path)).WithContext("path", path)
+	}
+	return fields[0], path, nil
+}
+
+// HasChecksumFile 检查安装包旁是否有 .sha256 文件
+func HasChecksumFile(archivePath string) bool {
+	return utils.FileExists(archivePath + constants.ChecksumFileSuffix)
+}
@@ -0,0 +1,163 @@
+package version
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kawaiirei0/gx/internal/installer"
+	"github.com/kawaiirei0/gx/internal/platform"
+	"github.com/kawaiirei0/gx/internal/utils"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// writeTarGz 创建只包含 go/VERSION 的安装包
+func writeTarGz(t *testing.T, path string, version string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	content := version + "\ntime 2024-07-02T16:38:09Z\n"
+	if err := tw.WriteHeader(&tar.Header{Name: "go/VERSION", Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchiveVersion(t *testing.T) {
+	m := newTestManager(t, nil, nil)
+	m.installer = installer.NewInstaller(platform.NewAdapter())
+
+	otherArch := "arm64"
+	if runtime.GOARCH == otherArch {
+		otherArch = "amd64"
+	}
+	native := "go1.22.5." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz"
+	foreign := "go1.22.5." + runtime.GOOS + "-" + otherArch + ".tar.gz"
+
+	tests := []struct {
+		name     string
+		file     string
+		explicit string
+		want     string
+		wantErr  *errors.Error
+	}{
+		{name: "official name", file: native, want: "go1.22.5"},
+		{name: "explicit version wins over the file name", file: native, explicit: "1.22.6", want: "go1.22.6"},
+		{name: "VERSION file", file: "go-offline.tar.gz", want: "go1.21.7"},
+		{name: "explicit version wins over VERSION", file: "go-offline.tar.gz", explicit: "go1.21.8", want: "go1.21.8"},
+		{name: "explicit prerelease", file: "go-offline.tar.gz", explicit: "1.23rc1", want: "go1.23rc1"},
+		{name: "explicit version line", file: "go-offline.tar.gz", explicit: "1.22", wantErr: errors.ErrInvalidVersion},
+		{name: "other platform", file: foreign, wantErr: errors.ErrInvalidInput},
+		{name: "other platform with explicit version", file: foreign, explicit: "1.22.5", wantErr: errors.ErrInvalidInput},
+		{name: "source archive", file: "go1.22.5.src.tar.gz", wantErr: errors.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			writeTarGz(t, path, "go1.21.7")
+
+			got, err := m.archiveVersion(path, tt.explicit)
+			if tt.wantErr != nil {
+				if !errors.IsType(err, tt.wantErr) {
+					t.Fatalf("archiveVersion() error = %v, want %s", err, tt.wantErr.Code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("archiveVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("archiveVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyArchive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "go1.22.5.linux-amd64.tar.gz")
+	if err := os.WriteFile(archive, []byte("go1.22.5 archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := utils.FileSHA256(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrong := strings.Repeat("0", 64)
+
+	tests := []struct {
+		name     string
+		policy   string
+		sidecar  string // 为空表示没有 .sha256 文件
+		expected string // --sha256
+		wantErr  *errors.Error
+	}{
+		// .sha256 文件的格式
+		{name: "checksum only", policy: constants.VerifyAuto, sidecar: sum + "\n"},
+		{name: "sha256sum output", policy: constants.VerifyAuto, sidecar: sum + "  go1.22.5.linux-amd64.tar.gz\n"},
+		{name: "upper case", policy: constants.VerifyAuto, sidecar: strings.ToUpper(sum)},
+		{name: "mismatch", policy: constants.VerifyAuto, sidecar: wrong, wantErr: errors.ErrChecksumMismatch},
+		{name: "empty file", policy: constants.VerifyAuto, sidecar: " \n", wantErr: errors.ErrInvalidInput},
+		{name: "not a checksum", policy: constants.VerifyAuto, sidecar: "deadbeef", wantErr: errors.ErrInvalidInput},
+
+		// download.verify 策略
+		{name: "auto without checksum", policy: constants.VerifyAuto},
+		{name: "strict without checksum", policy: constants.VerifyStrict, wantErr: errors.ErrChecksumMismatch},
+		{name: "strict with checksum file", policy: constants.VerifyStrict, sidecar: sum},
+		{name: "off ignores checksum file", policy: constants.VerifyOff, sidecar: wrong},
+
+		// --sha256 优先于 .sha256 文件，并且总是校验
+		{name: "flag wins over checksum file", policy: constants.VerifyAuto, sidecar: wrong, expected: sum},
+		{name: "flag mismatch", policy: constants.VerifyAuto, sidecar: sum, expected: wrong, wantErr: errors.ErrChecksumMismatch},
+		{name: "flag satisfies strict", policy: constants.VerifyStrict, expected: sum},
+		{name: "flag verified with off", policy: constants.VerifyOff, expected: wrong, wantErr: errors.ErrChecksumMismatch},
+		{name: "invalid flag", policy: constants.VerifyAuto, expected: "abc", wantErr: errors.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t, &interfaces.Config{
+				Versions: map[string]string{},
+				Settings: map[string]string{constants.SettingDownloadVerify: tt.policy},
+			}, nil)
+
+			sidecar := archive + constants.ChecksumFileSuffix
+			os.Remove(sidecar)
+			if tt.sidecar != "" {
+				writeFile(t, sidecar, tt.sidecar)
+			}
+
+			err := m.verifyArchive(archive, tt.expected)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("verifyArchive() error = %v", err)
+				}
+				return
+			}
+			if !errors.IsType(err, tt.wantErr) {
+				t.Fatalf("verifyArchive() error = %v, want %s", err, tt.wantErr.Code)
+			}
+		})
+	}
+}
@@ -1,25 +1,29 @@
 package version
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
+	"github.com/kawaiirei0/gx/internal/gxhome"
+	"github.com/kawaiirei0/gx/internal/lock"
 	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/settings"
+	"github.com/kawaiirei0/gx/internal/utils"
 	"github.com/kawaiirei0/gx/pkg/constants"
 	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
 	"github.com/kawaiirei0/gx/pkg/interfaces"
 )
 
 // manager 实现 VersionManager 接口
 type manager struct {
 	configStore interfaces.ConfigStore
+	storage     interfaces.Storage
 	platform    interfaces.PlatformAdapter
 	envManager  interfaces.EnvironmentManager
 	downloader  interfaces.Downloader
@@ -27,9 +31,10 @@ type manager struct {
 }
 
 // NewManager 创建新的版本管理器
-func NewManager(configStore interfaces.ConfigStore, platform interfaces.PlatformAdapter, envManager interfaces.EnvironmentManager, downloader interfaces.Downloader, installer interfaces.Installer) interfaces.VersionManager {
+func NewManager(configStore interfaces.ConfigStore, storage interfaces.Storage, platform interfaces.PlatformAdapter, envManager interfaces.EnvironmentManager, downloader interfaces.Downloader, installer interfaces.Installer) interfaces.VersionManager {
 	return &manager{
 		configStore: configStore,
+		storage:     storage,
 		platform:    platform,
 		envManager:  envManager,
 		downloader:  downloader,
@@ -50,7 +55,11 @@ func (m *manager) DetectInstalled() ([]interfaces.GoVersion, error) {
 	var versions []interfaces.GoVersion
 
 	// 扫描 gx 管理的版本目录
-	versionsDir := cfg.InstallPath
+	versionsDir, err := m.installRoot()
+	if err != nil {
+		logger.Warn("Failed to resolve install.root, scanning the default versions directory: %v", err)
+		versionsDir, _ = gxhome.VersionsDir()
+	}
 	if _, err := os.Stat(versionsDir); err == nil {
 		gxVersions, err := m.scanGxVersions(versionsDir, cfg.ActiveVersion)
 		if err == nil {
@@ -58,6 +67,15 @@ func (m *manager) DetectInstalled() ([]interfaces.GoVersion, error) {
 		}
 	}
 
+	// install.root 改变前安装在其他目录中的版本
+	versions = append(versions, m.registeredVersions(cfg, versions)...)
+
+	// 链接的外部版本
+	versions = append(versions, m.linkedVersions(cfg)...)
+
+	// 补充持久化的元数据（来源、大小、安装时间等）
+	m.applyMetadata(versions)
+
 	// 扫描系统环境变量中的 Go 版本
 	systemVersion, err := m.detectSystemGoVersion()
 	if err == nil && systemVersion != nil {
@@ -122,17 +140,15 @@ func (m *manager) scanGxVersions(versionsDir string, activeVersion string) ([]in
 	}
 
 	var versions []interfaces.GoVersion
-	versionRegex := regexp.MustCompile(`^go(\d+\.\d+(?:\.\d+)?)$`)
 
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 
-		// 检查目录名是否符合 Go 版本格式
+		// 检查目录名是否符合 Go 版本格式（包括 rc/beta 预发布版本）
 		dirName := entry.Name()
-		matches := versionRegex.FindStringSubmatch(dirName)
-		if len(matches) < 2 {
+		if !strings.HasPrefix(dirName, goversion.Prefix) || !goversion.IsValid(dirName) {
 			continue
 		}
 
@@ -164,6 +180,86 @@ func (m *manager) scanGxVersions(versionsDir string, activeVersion string) ([]in
 	return versions, nil
 }
 
+// applyMetadata 用元数据存储中的记录补充版本信息
+// 没有记录的版本保留扫描得到的信息（如以目录修改时间作为安装日期）
+func (m *manager) applyMetadata(versions []interfaces.GoVersion) {
+	records, err := m.storage.GetAllVersions()
+	if err != nil {
+		logger.Warn("Failed to load version metadata: %v", err)
+		return
+	}
+
+	byVersion := make(map[string]interfaces.GoVersion, len(records))
+	for _, record := range records {
+		byVersion[record.Version] = record
+	}
+
+	for i := range versions {
+		record, ok := byVersion[versions[i].Version]
+		if !ok {
+			continue
+		}
+		if !record.InstallDate.IsZero() {
+			versions[i].InstallDate = record.InstallDate
+		}
+		versions[i].Origin = record.Origin
+		versions[i].SourceURL = record.SourceURL
+		versions[i].ReleaseSource = record.ReleaseSource
+		versions[i].SHA256 = record.SHA256
+		versions[i].Size = record.Size
+		versions[i].InstallDuration = record.InstallDuration
+		versions[i].LastUsed = record.LastUsed
+	}
+}
+
+// registeredVersions 返回配置中记录、但不在 found 中的 gx 安装版本
+// install.root 可以由系统、用户或项目配置改变，此前安装到其他目录的版本仍然可用
+func (m *manager) registeredVersions(cfg *interfaces.Config, found []interfaces.GoVersion) []interfaces.GoVersion {
+	seen := make(map[string]bool, len(found))
+	for _, v := range found {
+		seen[v.Version] = true
+	}
+
+	var versions []interfaces.GoVersion
+	for version, versionPath := range cfg.Versions {
+		if seen[version] || cfg.Linked[version] || !m.isValidGoInstallation(versionPath) {
+			continue
+		}
+
+		var installDate time.Time
+		if info, err := os.Stat(versionPath); err == nil {
+			installDate = info.ModTime()
+		}
+
+		versions = append(versions, interfaces.GoVersion{
+			Version:     version,
+			Path:        versionPath,
+			IsActive:    version == cfg.ActiveVersion,
+			InstallDate: installDate,
+		})
+	}
+	return versions
+}
+
+// linkedVersions 返回通过 gx adopt 注册且仍然有效的外部版本
+func (m *manager) linkedVersions(cfg *interfaces.Config) []interfaces.GoVersion {
+	var versions []interfaces.GoVersion
+	for version, linked := range cfg.Linked {
+		versionPath, ok := cfg.Versions[version]
+		if !linked || !ok || !m.isValidGoInstallation(versionPath) {
+			continue
+		}
+
+		versions = append(versions, interfaces.GoVersion{
+			Version:  version,
+			Path:     versionPath,
+			IsActive: version == cfg.ActiveVersion,
+			Linked:   true,
+		})
+	}
+	return versions
+}
+
 // detectSystemGoVersion 检测系统环境变量中的 Go 版本
 func (m *manager) detectSystemGoVersion() (*interfaces.GoVersion, error) {
 	// 尝试执行 go version 命令
@@ -174,15 +270,13 @@ func (m *manager) detectSystemGoVersion() (*interfaces.GoVersion, error) {
 	}
 
 	// 解析版本号，格式如: "go version go1.21.5 windows/amd64"
-	versionStr := string(output)
-	versionRegex := regexp.MustCompile(`go version (go\d+\.\d+(?:\.\d+)?)`)
-	matches := versionRegex.FindStringSubmatch(versionStr)
-	if len(matches) < 2 {
-		return nil, errors.ErrInvalidVersion.WithMessage("failed to parse go version output")
+	parsed, err := goversion.ParseVersionOutput(string(output))
+	if err != nil {
+		return nil, err
 	}
 
 	// 使用完整版本号（包含 "go" 前缀）
-	fullVersion := matches[1]
+	fullVersion := parsed.String()
 
 	// 获取 GOROOT 路径
 	goroot := os.Getenv(constants.EnvGoRoot)
@@ -195,6 +289,11 @@ func (m *manager) detectSystemGoVersion() (*interfaces.GoVersion, error) {
 		}
 	}
 
+	// GOROOT 可能指向 current 链接，解析为真实路径以便与已安装版本去重
+	if resolved, err := filepath.EvalSymlinks(goroot); err == nil {
+		goroot = resolved
+	}
+
 	return &interfaces.GoVersion{
 		Version:  fullVersion,
 		Path:     goroot,
@@ -225,14 +324,67 @@ func (m *manager) isValidGoInstallation(path string) bool {
 }
 
 // Install 安装指定版本
-func (m *manager) Install(version string, progress interfaces.ProgressCallback) error {
-	// 规范化版本号
-	normalizedVersion := version
-	if !strings.HasPrefix(version, "go") {
-		normalizedVersion = "go" + version
+func (m *manager) Install(ctx context.Context, version string, progress interfaces.ProgressCallback) error {
+	// 将版本说明符（1.22、latest、别名等）解析为具体版本
+	normalizedVersion, err := m.FindRemote(ctx, version)
+	if err != nil {
+		return err
 	}
 
+	return m.installArchive(ctx, normalizedVersion, archiveSource{
+		origin: interfaces.OriginDownloaded,
+		fetch: func(installPath string, recovery *errors.RecoveryManager) (*fetchedArchive, error) {
+			// 构建下载文件路径
+			archiveExt := constants.ArchiveExtTarGz
+			if m.platform.GetOS() == constants.OSWindows {
+				archiveExt = constants.ArchiveExtZip
+			}
+
+			archiveFilename := normalizedVersion + "." + m.platform.GetOS() + "-" + m.platform.GetArch() + archiveExt
+			archivePath := filepath.Join(installPath, archiveFilename)
+
+			// 注册下载文件的清理
+			errors.EnsureFileCleanup(recovery, archivePath)
+
+			logger.Info("Downloading %s to %s", normalizedVersion, archivePath)
+			// 下载安装包
+			result, err := m.downloader.Download(ctx, normalizedVersion, archivePath, progress)
+			if err != nil {
+				logger.Error("Download failed: %v", err)
+				return nil, err
+			}
+			logger.Info("Download completed successfully from %s", result.Source)
+			return &fetchedArchive{
+				path:          archivePath,
+				temporary:     true,
+				sourceURL:     result.URL,
+				releaseSource: result.Source,
+			}, nil
+		},
+	})
+}
+
+// archiveSource 安装包的来源
+type archiveSource struct {
+	origin interfaces.VersionOrigin
+
+	// fetch 在持有安装锁后获取安装包
+	fetch func(installPath string, recovery *errors.RecoveryManager) (*fetchedArchive, error)
+}
+
+// fetchedArchive 获取到的安装包
+type fetchedArchive struct {
+	path          string // 安装包路径
+	temporary     bool   // 安装完成后是否删除安装包
+	sourceURL     string // 记录在元数据中的来源地址
+	releaseSource string // 提供安装包的发布源，离线安装时为空
+}
+
+// installArchive 获取安装包并安装为指定版本
+// 下载安装和离线安装共用加锁、解压、验证和注册的流程；ctx 取消时回滚并返回 ErrCancelled
+func (m *manager) installArchive(ctx context.Context, normalizedVersion string, source archiveSource) error {
 	logger.Info("Starting installation of Go version %s", normalizedVersion)
+	startTime := time.Now()
 
 	// 创建恢复管理器
 	recovery := errors.NewRecoveryManager()
@@ -250,19 +402,6 @@ func (m *manager) Install(version string, progress interfaces.ProgressCallback)
 		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
 	}
 
-	// 备份配置文件
-	configPath := filepath.Join(cfg.InstallPath, "..", constants.ConfigFileName)
-	backupPath, err := errors.BackupFile(configPath)
-	if err == nil {
-		logger.Debug("Config backed up to: %s", backupPath)
-		recovery.AddRollback(func() error {
-			return errors.RestoreFile(backupPath, configPath)
-		})
-		recovery.AddCleanup(func() error {
-			return errors.SafeRemoveFile(backupPath)
-		})
-	}
-
 	// 检查版本是否已安装
 	if _, ok := cfg.Versions[normalizedVersion]; ok {
 		logger.Warn("Version %s is already installed", normalizedVersion)
@@ -270,44 +409,63 @@ func (m *manager) Install(version string, progress interfaces.ProgressCallback)
 	}
 
 	// 确保安装目录存在
-	if err := os.MkdirAll(cfg.InstallPath, 0755); err != nil {
+	installPath, err := m.installRoot()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(installPath, 0755); err != nil {
 		logger.Error("Failed to create install directory: %v", err)
 		return errors.ErrInstallFailed.WithCause(err).WithMessage("failed to create install directory")
 	}
 
-	// 构建下载文件路径
-	archiveExt := constants.ArchiveExtTarGz
-	if m.platform.GetOS() == constants.OSWindows {
-		archiveExt = constants.ArchiveExtZip
+	// 构建安装目标路径
+	versionPath := filepath.Join(installPath, normalizedVersion)
+
+	// 同一版本同时只允许一个进程安装，其余进程等待其完成
+	installLock, err := m.acquireInstallLock(ctx, versionPath, normalizedVersion)
+	if err != nil {
+		return err
+	}
+	defer installLock.Release()
+
+	// 等待期间另一个进程可能已完成安装，直接复用其结果
+	if cfg, err = m.configStore.Load(); err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+	if _, ok := cfg.Versions[normalizedVersion]; ok {
+		logger.Info("Version %s was installed by another process", normalizedVersion)
+		return nil
 	}
-	
-	archiveFilename := normalizedVersion + "." + m.platform.GetOS() + "-" + m.platform.GetArch() + archiveExt
-	archivePath := filepath.Join(cfg.InstallPath, archiveFilename)
 
-	// 注册下载文件的清理
-	errors.EnsureFileCleanup(recovery, archivePath)
+	// 持有锁时目录已存在，说明之前的安装被中断，清理后重新安装
+	if _, err := os.Stat(versionPath); err == nil {
+		logger.Warn("Removing leftover directory of an interrupted install: %s", versionPath)
+		if err := os.RemoveAll(versionPath); err != nil {
+			return errors.ErrInstallFailed.WithCause(err).WithMessage("failed to remove leftover install directory").WithContext("path", versionPath)
+		}
+	}
 
-	logger.Info("Downloading %s to %s", normalizedVersion, archivePath)
-	// 下载安装包
-	if err := m.downloader.Download(normalizedVersion, archivePath, progress); err != nil {
-		logger.Error("Download failed: %v", err)
+	// 等待安装锁时不响应取消，获取后再检查
+	if ctx.Err() != nil {
+		return installCancelled(normalizedVersion)
+	}
+
+	archive, err := source.fetch(installPath, recovery)
+	if err != nil {
 		// 执行回滚
 		if rollbackErr := recovery.Rollback(); rollbackErr != nil {
 			logger.Error("Rollback failed: %v", rollbackErr)
 		}
 		return err
 	}
-	logger.Info("Download completed successfully")
-
-	// 构建安装目标路径
-	versionPath := filepath.Join(cfg.InstallPath, normalizedVersion)
 
 	// 注册版本目录的清理（如果安装失败）
 	errors.EnsureDirectoryCleanup(recovery, versionPath)
 
 	logger.Info("Installing %s to %s", normalizedVersion, versionPath)
 	// 安装（解压）
-	if err := m.installer.Install(archivePath, normalizedVersion, versionPath); err != nil {
+	if err := m.installer.Install(ctx, archive.path, normalizedVersion, versionPath); err != nil {
 		logger.Error("Installation failed: %v", err)
 		// 执行回滚和清理
 		if rollbackErr := recovery.CleanupAndRollback(); rollbackErr != nil {
@@ -317,9 +475,23 @@ func (m *manager) Install(version string, progress interfaces.ProgressCallback)
 	}
 	logger.Info("Installation completed successfully")
 
+	// 注册之前最后一次响应取消，之后的步骤不再中断
+	if ctx.Err() != nil {
+		if rollbackErr := recovery.CleanupAndRollback(); rollbackErr != nil {
+			logger.Error("Recovery failed: %v", rollbackErr)
+		}
+		return installCancelled(normalizedVersion)
+	}
+
 	// 更新配置
-	cfg.Versions[normalizedVersion] = versionPath
-	if err := m.configStore.Save(cfg); err != nil {
+	err = m.configStore.Update(func(cfg *interfaces.Config) error {
+		if cfg.Versions == nil {
+			cfg.Versions = make(map[string]string)
+		}
+		cfg.Versions[normalizedVersion] = versionPath
+		return nil
+	})
+	if err != nil {
 		logger.Error("Failed to save config after installation: %v", err)
 		// 配置保存失败，尝试清理已安装的版本
 		if cleanupErr := recovery.CleanupAndRollback(); cleanupErr != nil {
@@ -331,10 +503,84 @@ func (m *manager) Install(version string, progress interfaces.ProgressCallback)
 	// 安装成功，清除清理函数（不需要清理）
 	recovery.Clear()
 
+	m.saveInstallMetadata(normalizedVersion, versionPath, source.origin, archive, time.Since(startTime))
+
+	// 下载的安装包已解压，不再需要
+	if archive.temporary {
+		if err := errors.SafeRemoveFile(archive.path); err != nil {
+			logger.Warn("Failed to remove archive %s: %v", archive.path, err)
+		}
+	}
+
 	logger.Info("Go version %s installed successfully", normalizedVersion)
 	return nil
 }
 
+// installCancelled 构建安装被取消的错误
+func installCancelled(version string) error {
+	logger.Warn("Installation of %s was cancelled and rolled back", version)
+	return errors.ErrCancelled.WithMessage(fmt.Sprintf("installation of Go %s was interrupted", goversion.Display(version)))
+}
+
+// installRoot 返回新版本的安装目录，即当前目录下生效配置中的 install.root
+// 只在需要时解析分层配置，列出和切换版本不读取系统和项目配置
+func (m *manager) installRoot() (string, error) {
+	effective, err := m.configStore.Settings("")
+	if err != nil {
+		return "", err
+	}
+	return effective.Get(constants.SettingInstallRoot), nil
+}
+
+// acquireInstallLock 获取版本目录的安装锁，其他进程正在安装同一版本时等待
+// 等待时间取自配置项 install.lock-timeout
+func (m *manager) acquireInstallLock(ctx context.Context, versionPath string, version string) (*lock.Lock, error) {
+	lockTimeout := constants.InstallLockTimeout
+	if effective, err := m.configStore.Settings(""); err == nil {
+		lockTimeout = settings.Duration(effective, constants.SettingInstallLockTimeout, constants.InstallLockTimeout)
+	}
+	installLock, err := lock.Acquire(versionPath+constants.LockFileSuffix, lock.Options{
+		Timeout: lockTimeout,
+		Context: ctx,
+		OnWait: func(holder lock.Holder) {
+			fmt.Fprintf(os.Stderr, "Waiting for %s to finish installing Go %s...\n", holder, goversion.Display(version))
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to acquire install lock: %v", err)
+		return nil, err
+	}
+	return installLock, nil
+}
+
+// saveInstallMetadata 记录安装版本的元数据
+// 元数据只用于展示和清理策略，记录失败不影响安装结果
+func (m *manager) saveInstallMetadata(version string, versionPath string, origin interfaces.VersionOrigin, archive *fetchedArchive, duration time.Duration) {
+	record := &interfaces.GoVersion{
+		Version:         version,
+		Path:            versionPath,
+		InstallDate:     time.Now(),
+		Origin:          origin,
+		SourceURL:       archive.sourceURL,
+		ReleaseSource:   archive.releaseSource,
+		InstallDuration: duration,
+	}
+
+	if sum, err := utils.FileSHA256(archive.path); err == nil {
+		record.SHA256 = sum
+	} else {
+		logger.Warn("Failed to compute checksum of %s: %v", archive.path, err)
+	}
+
+	if size, err := utils.DirSize(versionPath); err == nil {
+		record.Size = size
+	}
+
+	if err := m.storage.SaveVersion(record); err != nil {
+		logger.Warn("Failed to save metadata for %s: %v", version, err)
+	}
+}
+
 // SwitchTo 切换到指定版本
 func (m *manager) SwitchTo(version string) error {
 	startTime := time.Now()
@@ -347,47 +593,41 @@ func (m *manager) SwitchTo(version string) error {
 		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
 	}
 
-	// 规范化版本号
-	normalizedVersion := version
-	if !strings.HasPrefix(version, "go") {
-		normalizedVersion = "go" + version
+	// 将版本说明符解析为已安装的具体版本
+	normalizedVersion, ok, err := m.matchInstalled(cfg, version)
+	if err != nil {
+		return err
 	}
-
-	// 检查版本是否已安装
-	versionPath, ok := cfg.Versions[normalizedVersion]
 	if !ok {
-		logger.Error("Version %s is not installed", normalizedVersion)
-		// 提供更友好的错误消息
-		versionDisplay := strings.TrimPrefix(normalizedVersion, "go")
-		return errors.ErrVersionNotInstalled.
-			WithMessage(fmt.Sprintf("Go %s is not installed. Install it first using: gx install %s", versionDisplay, versionDisplay))
+		logger.Error("Version %s is not installed", version)
+		return notInstalledError(version, "")
 	}
+	versionPath := cfg.Versions[normalizedVersion]
 
 	// 验证版本目录是否存在且有效
 	if !m.isValidGoInstallation(versionPath) {
 		logger.Error("Version %s installation is invalid or corrupted", normalizedVersion)
-		versionDisplay := strings.TrimPrefix(normalizedVersion, "go")
+		versionDisplay := goversion.Display(normalizedVersion)
 		return errors.ErrVersionNotFound.
 			WithMessage(fmt.Sprintf("Go %s installation is invalid or corrupted. Try reinstalling: gx uninstall %s && gx install %s", versionDisplay, versionDisplay, versionDisplay))
 	}
 
-	// 更新环境变量
-	if err := m.envManager.SetGoRoot(versionPath); err != nil {
-		logger.Error("Failed to set GOROOT: %v", err)
-		return errors.ErrEnvironmentSetupFailed.WithCause(err).WithMessage("failed to set GOROOT")
-	}
-
-	if err := m.envManager.UpdatePath(versionPath); err != nil {
-		logger.Error("Failed to update PATH: %v", err)
-		return errors.ErrEnvironmentSetupFailed.WithCause(err).WithMessage("failed to update PATH")
+	// 原子地切换 current 指针
+	if err := m.envManager.SwitchCurrent(versionPath); err != nil {
+		logger.Error("Failed to switch current version: %v", err)
+		return errors.ErrEnvironmentSetupFailed.WithCause(err).WithMessage("failed to switch current version")
 	}
 
 	// 更新配置中的激活版本
-	cfg.ActiveVersion = normalizedVersion
-	if err := m.configStore.Save(cfg); err != nil {
+	err = m.configStore.Update(func(cfg *interfaces.Config) error {
+		cfg.ActiveVersion = normalizedVersion
+		return nil
+	})
+	if err != nil {
 		logger.Error("Failed to save config: %v", err)
 		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to save config")
 	}
+	m.markUsed(cfg, normalizedVersion, time.Now())
 
 	// 验证切换是否成功
 	activeVersion, err := m.GetActive()
@@ -412,11 +652,11 @@ func (m *manager) SwitchTo(version string) error {
 }
 
 // ListAvailable 获取可用的远程版本列表
-func (m *manager) ListAvailable() ([]string, error) {
+func (m *manager) ListAvailable(ctx context.Context) ([]string, error) {
 	logger.Info("Fetching available Go versions from remote")
 	// 通过 downloader 获取版本信息
 	// 我们需要创建一个辅助方法来获取版本列表
-	versions, err := m.fetchRemoteVersions()
+	versions, err := m.fetchRemoteVersions(ctx)
 	if err != nil {
 		logger.Error("Failed to fetch remote versions: %v", err)
 		return nil, err
@@ -433,9 +673,9 @@ func (m *manager) ListAvailable() ([]string, error) {
 }
 
 // GetLatest 获取最新稳定版本
-func (m *manager) GetLatest() (string, error) {
+func (m *manager) GetLatest(ctx context.Context) (string, error) {
 	logger.Info("Fetching latest stable Go version")
-	versions, err := m.fetchRemoteVersions()
+	versions, err := m.fetchRemoteVersions(ctx)
 	if err != nil {
 		logger.Error("Failed to fetch remote versions: %v", err)
 		return "", err
@@ -460,74 +700,95 @@ func (m *manager) GetLatest() (string, error) {
 	return "", errors.ErrVersionNotFound.WithMessage("no versions available")
 }
 
-// fetchRemoteVersions 从远程获取版本列表
-// 这是一个辅助方法，通过 downloader 的 GetDownloadURL 来触发版本信息获取
-func (m *manager) fetchRemoteVersions() ([]interfaces.RemoteVersion, error) {
-	// 我们需要直接访问 Go 官方 API
-	// 由于 downloader 已经有 fetchVersions 方法，我们需要创建一个新的接口方法
-	// 或者直接在这里实现 HTTP 请求
-	
-	// 为了避免重复代码，我们直接实现 HTTP 请求
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// fetchRemoteVersions 从发布源获取当前支持的版本列表
+func (m *manager) fetchRemoteVersions(ctx context.Context) ([]interfaces.RemoteVersion, error) {
+	return m.downloader.Versions(ctx, false)
+}
+
+// Uninstall 卸载指定版本
+func (m *manager) Uninstall(version string) error {
+	logger.Info("Uninstalling Go version %s", version)
+
+	if _, _, err := m.uninstallTarget(version); err != nil {
+		return err
 	}
 
-	resp, err := client.Get(constants.GoVersionsAPIURL)
+	// 与安装同一版本的进程互斥，持有锁后重新检查配置：
+	// 等待期间该版本可能已被其他进程卸载或设为当前版本
+	installPath, err := m.installRoot()
 	if err != nil {
-		return nil, errors.ErrNetworkError.WithCause(err).WithMessage("failed to fetch version list")
+		return err
 	}
-	defer resp.Body.Close()
+	installLock, err := m.acquireInstallLock(context.Background(), filepath.Join(installPath, version), version)
+	if err != nil {
+		return err
+	}
+	defer installLock.Release()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.ErrNetworkError.WithMessage("unexpected status code: " + resp.Status)
+	versionPath, linked, err := m.uninstallTarget(version)
+	if err != nil {
+		return err
 	}
 
-	var versions []interfaces.RemoteVersion
-	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
-		return nil, errors.ErrNetworkError.WithCause(err).WithMessage("failed to parse version list")
+	// 链接版本的文件不属于 gx，只取消注册；
+	// 配置中记录的目录不在安装根目录内时（配置被手动修改或 install.root 已变更），同样不删除文件
+	if absRoot, err := filepath.Abs(installPath); err == nil {
+		installPath = absRoot
+	}
+	owned := isWithin(versionPath, installPath) && !samePath(versionPath, installPath)
+	if linked {
+		logger.Info("Unregistering linked version %s, leaving %s untouched", version, versionPath)
+	} else if !owned {
+		logger.Warn("Version directory %s is outside the install root %s, only unregistering %s", versionPath, installPath, version)
+		fmt.Fprintf(os.Stderr, "Warning: %s is outside the install root %s; unregistering Go %s without removing its files\n", versionPath, installPath, goversion.Display(version))
+	} else {
+		// 删除版本目录
+		logger.Info("Removing version directory: %s", versionPath)
+		if err := os.RemoveAll(versionPath); err != nil {
+			logger.Error("Failed to remove version directory: %v", err)
+			return errors.ErrUninstallFailed.WithCause(err).WithMessage("failed to remove version directory")
+		}
 	}
 
-	return versions, nil
+	// 从配置中移除版本记录
+	err = m.configStore.Update(func(cfg *interfaces.Config) error {
+		delete(cfg.Versions, version)
+		delete(cfg.Linked, version)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to save config after uninstall: %v", err)
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to save config after uninstall")
+	}
+
+	if err := m.storage.DeleteVersion(version); err != nil {
+		logger.Warn("Failed to delete metadata for %s: %v", version, err)
+	}
+
+	logger.Info("Successfully uninstalled Go version %s", version)
+	return nil
 }
 
-// Uninstall 卸载指定版本
-func (m *manager) Uninstall(version string) error {
-	logger.Info("Uninstalling Go version %s", version)
-	
-	// 加载配置
+// uninstallTarget 检查版本能否卸载，返回版本目录以及是否为链接版本
+func (m *manager) uninstallTarget(version string) (string, bool, error) {
 	cfg, err := m.configStore.Load()
 	if err != nil {
 		logger.Error("Failed to load config: %v", err)
-		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+		return "", false, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
 	}
 
 	// 检查版本是否已安装
 	versionPath, ok := cfg.Versions[version]
 	if !ok {
 		logger.Warn("Version %s is not installed", version)
-		return errors.ErrVersionNotInstalled.WithMessage("version " + version + " is not installed")
+		return "", false, errors.ErrVersionNotInstalled.WithMessage("version " + version + " is not installed")
 	}
 
 	// 安全检查：不能卸载当前激活的版本
 	if cfg.ActiveVersion == version {
 		logger.Error("Cannot uninstall currently active version %s", version)
-		return errors.ErrUninstallFailed.WithMessage("cannot uninstall the currently active version")
-	}
-
-	// 删除版本目录
-	logger.Info("Removing version directory: %s", versionPath)
-	if err := os.RemoveAll(versionPath); err != nil {
-		logger.Error("Failed to remove version directory: %v", err)
-		return errors.ErrUninstallFailed.WithCause(err).WithMessage("failed to remove version directory")
-	}
-
-	// 从配置中移除版本记录
-	delete(cfg.Versions, version)
-	if err := m.configStore.Save(cfg); err != nil {
-		logger.Error("Failed to save config after uninstall: %v", err)
-		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to save config after uninstall")
+		return "", false, errors.ErrUninstallFailed.WithMessage("cannot uninstall the currently active version")
 	}
 
-	logger.Info("Successfully uninstalled Go version %s", version)
-	return nil
+	return versionPath, cfg.Linked[version], nil
 }
@@ -0,0 +1,46 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+func TestUninstallOnlyRemovesInsideInstallRoot(t *testing.T) {
+	root := t.TempDir()
+	inside := fakeGoRoot(t, root, "go1.21.5")
+	outside := fakeGoRoot(t, t.TempDir(), "go1.22.3")
+
+	m := newTestManager(t, &interfaces.Config{
+		Versions: map[string]string{
+			"go1.21.5": inside,
+			"go1.22.3": outside,
+		},
+		Settings: map[string]string{constants.SettingInstallRoot: root},
+	}, nil)
+
+	for _, version := range []string{"go1.21.5", "go1.22.3"} {
+		if err := m.Uninstall(version); err != nil {
+			t.Fatalf("Uninstall(%s) error = %v", version, err)
+		}
+	}
+
+	if _, err := os.Stat(inside); !os.IsNotExist(err) {
+		t.Errorf("%s was not removed", inside)
+	}
+	// 安装根目录外的目录只取消注册，文件保留
+	if _, err := os.Stat(filepath.Join(outside, "bin")); err != nil {
+		t.Errorf("%s was removed: %v", outside, err)
+	}
+
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Versions) != 0 {
+		t.Errorf("versions still registered: %v", cfg.Versions)
+	}
+}
@@ -0,0 +1,202 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/utils"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// Prune 按保留策略删除旧版本
+// 当前激活版本、链接版本、源码构建的命名版本和别名指向的版本始终保留；其余版本只要被任一策略保护就不会删除
+func (m *manager) Prune(policy interfaces.PrunePolicy, dryRun bool) (*interfaces.PruneResult, error) {
+	// 只有固定目录时会删除所有未固定的版本，要求至少指定一条按数量或时间保留的策略
+	if policy.KeepPatches <= 0 && policy.UnusedFor <= 0 {
+		return nil, errors.ErrInvalidInput.WithMessage("no retention policy given: specify how many patches to keep or how long unused versions are kept")
+	}
+
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	logger.Info("Pruning Go versions (keep patches: %d, unused for: %v, pinned dirs: %v, dry run: %v)",
+		policy.KeepPatches, policy.UnusedFor, policy.KeepPinnedDirs, dryRun)
+
+	// 记录每个受保护版本的保留原因（只保留第一个原因）
+	kept := make(map[string]string)
+	keep := func(version string, reason string) {
+		if _, ok := kept[version]; !ok {
+			kept[version] = reason
+		}
+	}
+
+	if cfg.ActiveVersion != "" {
+		keep(cfg.ActiveVersion, "active version")
+	}
+
+	for version, linked := range cfg.Linked {
+		if linked {
+			keep(version, "linked (not owned by gx)")
+		}
+	}
+
+	// 源码构建的版本不属于任何版本线，重新构建代价高，不自动清理
+	for version := range cfg.Versions {
+		if isNamedVersion(cfg, version) {
+			keep(version, "built from source")
+		}
+	}
+
+	for name := range cfg.Aliases {
+		if version, ok, err := m.matchInstalled(cfg, name); err == nil && ok {
+			keep(version, "alias "+name)
+		}
+	}
+
+	for _, dir := range policy.KeepPinnedDirs {
+		pins, err := m.findPinnedVersions(cfg, dir)
+		if err != nil {
+			return nil, err
+		}
+		for version, file := range pins {
+			keep(version, "pinned by "+file)
+		}
+	}
+
+	if policy.KeepPatches > 0 {
+		for version, line := range m.newestPatches(cfg, policy.KeepPatches) {
+			keep(version, fmt.Sprintf("newest %d of %s", policy.KeepPatches, goversion.Display(line)))
+		}
+	}
+
+	now := time.Now()
+	if policy.UnusedFor > 0 {
+		for version := range cfg.Versions {
+			if at, activity := m.lastUsed(cfg, version); activity != "" && now.Sub(at) < policy.UnusedFor {
+				keep(version, activity+" "+at.Format("2006-01-02"))
+			}
+		}
+	}
+
+	versions := make([]string, 0, len(cfg.Versions))
+	for version := range cfg.Versions {
+		versions = append(versions, version)
+	}
+	goversion.Sort(versions)
+
+	result := &interfaces.PruneResult{}
+	for _, version := range versions {
+		item := interfaces.PruneItem{
+			Version: version,
+			Path:    cfg.Versions[version],
+		}
+		// 只有真实的使用记录才作为最近使用时间显示，安装时间等代替值只出现在原因中
+		at, activity := m.lastUsed(cfg, version)
+		if activity == activityUsed {
+			item.LastUsed = at
+		}
+
+		if reason, ok := kept[version]; ok {
+			item.Reason = reason
+			result.Kept = append(result.Kept, item)
+			continue
+		}
+
+		item.Reason = removalReason(policy, at, activity, now)
+		if size, err := utils.DirSize(item.Path); err == nil {
+			item.Size = size
+		} else {
+			logger.Warn("Failed to compute size of %s: %v", item.Path, err)
+		}
+
+		if !dryRun {
+			if err := m.Uninstall(version); err != nil {
+				logger.Error("Failed to prune %s: %v", version, err)
+				item.Reason = fmt.Sprintf("removal failed: %v", err)
+				result.Failed = append(result.Failed, item)
+				continue
+			}
+		}
+
+		result.Removed = append(result.Removed, item)
+		result.FreedBytes += item.Size
+	}
+
+	logger.Info("Prune finished: %d removed, %d kept, %d failed, %d bytes freed",
+		len(result.Removed), len(result.Kept), len(result.Failed), result.FreedBytes)
+	return result, nil
+}
+
+// newestPatches 返回每个次版本线中最新的 n 个 gx 管理的版本（版本 -> 版本线）
+// 链接版本不占用名额，因为它们不会被清理
+func (m *manager) newestPatches(cfg *interfaces.Config, n int) map[string]string {
+	lines := make(map[string][]goversion.Version)
+	for version := range cfg.Versions {
+		if cfg.Linked[version] {
+			continue
+		}
+		parsed, err := goversion.Parse(version)
+		if err != nil {
+			continue
+		}
+		lines[parsed.Line()] = append(lines[parsed.Line()], parsed)
+	}
+
+	newest := make(map[string]string)
+	for line, versions := range lines {
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[j].Less(versions[i])
+		})
+		for i := 0; i < len(versions) && i < n; i++ {
+			newest[versions[i].String()] = line
+		}
+	}
+	return newest
+}
+
+// findPinnedVersions 递归查找 dir 中的 .go-version 文件，返回其固定的已安装版本（版本 -> 文件路径）
+func (m *manager) findPinnedVersions(cfg *interfaces.Config, dir string) (map[string]string, error) {
+	pins := make(map[string]string)
+	err := walkVersionFiles(dir, func(path string, spec string) {
+		version, ok, err := m.matchInstalled(cfg, spec)
+		if err != nil || !ok {
+			return
+		}
+		if _, exists := pins[version]; !exists {
+			pins[version] = path
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pins, nil
+}
+
+// removalReason 描述版本被清理的原因，at 和 activity 为 lastUsed 的返回值
+func removalReason(policy interfaces.PrunePolicy, at time.Time, activity string, now time.Time) string {
+	var reasons []string
+	if policy.KeepPatches > 0 {
+		reasons = append(reasons, fmt.Sprintf("not among newest %d of its line", policy.KeepPatches))
+	}
+	if policy.UnusedFor > 0 {
+		days := int(now.Sub(at).Hours() / 24)
+		switch activity {
+		case activityUsed:
+			reasons = append(reasons, fmt.Sprintf("unused for %d days", days))
+		case "":
+			reasons = append(reasons, "never used")
+		default:
+			reasons = append(reasons, fmt.Sprintf("never used, %s %d days ago", activity, days))
+		}
+	}
+	return strings.Join(reasons, ", ")
+}
@@ -0,0 +1,176 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// newPruneManager 创建包含各类版本的临时安装，返回管理器、.go-version 所在目录和固定文件路径
+// 版本目录的修改时间除 go1.20.14（十天前）外都是一年前；go1.22.1 两天前用过，
+// go1.21.13 没有使用记录，五天前安装
+func newPruneManager(t *testing.T) (*manager, string, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	cfg := &interfaces.Config{
+		ActiveVersion: "go1.22.3",
+		Versions:      make(map[string]string),
+		Aliases:       map[string]string{"work": "go1.21.5"},
+		Linked:        map[string]bool{"go1.21.20": true},
+	}
+
+	yearAgo := time.Now().AddDate(-1, 0, 0)
+	for _, version := range []string{"go1.20.14", "go1.21.1", "go1.21.5", "go1.21.13", "go1.21.20", "go1.22.1", "go1.22.3", "tip"} {
+		dir := filepath.Join(root, version)
+		writeFile(t, filepath.Join(dir, "VERSION"), version)
+		if err := os.Chtimes(dir, yearAgo, yearAgo); err != nil {
+			t.Fatal(err)
+		}
+		cfg.Versions[version] = dir
+	}
+
+	tenDaysAgo := time.Now().AddDate(0, 0, -10)
+	if err := os.Chtimes(cfg.Versions["go1.20.14"], tenDaysAgo, tenDaysAgo); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestManager(t, cfg, nil)
+	m.markUsed(cfg, "go1.22.1", time.Now().Add(-48*time.Hour))
+	installed := &interfaces.GoVersion{Version: "go1.21.13", Path: cfg.Versions["go1.21.13"], InstallDate: time.Now().AddDate(0, 0, -5)}
+	if err := m.storage.SaveVersion(installed); err != nil {
+		t.Fatal(err)
+	}
+
+	projects := t.TempDir()
+	pin := filepath.Join(projects, "service", constants.VersionFileName)
+	writeFile(t, pin, "1.21.1\n")
+
+	return m, projects, pin
+}
+
+func TestPruneKeepRules(t *testing.T) {
+	day := func(t time.Time) string { return t.Format("2006-01-02") }
+
+	// 每个版本只记录第一个保留原因：激活、链接、源码构建、别名、固定、最新补丁、最近使用
+	always := map[string]string{
+		"go1.22.3":  "active version",
+		"go1.21.20": "linked (not owned by gx)",
+		"tip":       "built from source",
+		"go1.21.5":  "alias work",
+	}
+
+	tests := []struct {
+		name        string
+		policy      func(projects string) interfaces.PrunePolicy
+		wantKept    func(pin string) map[string]string
+		wantRemoved []string
+	}{
+		{
+			name: "newest patch per line",
+			policy: func(string) interfaces.PrunePolicy {
+				return interfaces.PrunePolicy{KeepPatches: 1}
+			},
+			wantKept: func(string) map[string]string {
+				// 链接的 go1.21.20 不占用 1.21 的名额
+				return map[string]string{
+					"go1.20.14": "newest 1 of 1.20",
+					"go1.21.13": "newest 1 of 1.21",
+				}
+			},
+			wantRemoved: []string{"go1.21.1", "go1.22.1"},
+		},
+		{
+			name: "pinned versions",
+			policy: func(projects string) interfaces.PrunePolicy {
+				return interfaces.PrunePolicy{KeepPatches: 1, KeepPinnedDirs: []string{projects}}
+			},
+			wantKept: func(pin string) map[string]string {
+				return map[string]string{
+					"go1.20.14": "newest 1 of 1.20",
+					"go1.21.1":  "pinned by " + pin,
+					"go1.21.13": "newest 1 of 1.21",
+				}
+			},
+			wantRemoved: []string{"go1.22.1"},
+		},
+		{
+			name: "unused for",
+			policy: func(string) interfaces.PrunePolicy {
+				return interfaces.PrunePolicy{UnusedFor: 30 * 24 * time.Hour}
+			},
+			wantKept: func(string) map[string]string {
+				// 没有使用记录时以安装时间或目录修改时间代替，并标明来源
+				return map[string]string{
+					"go1.20.14": "modified " + day(time.Now().AddDate(0, 0, -10)),
+					"go1.21.13": "installed " + day(time.Now().AddDate(0, 0, -5)),
+					"go1.22.1":  "used " + day(time.Now().Add(-48*time.Hour)),
+				}
+			},
+			wantRemoved: []string{"go1.21.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, projects, pin := newPruneManager(t)
+
+			result, err := m.Prune(tt.policy(projects), true)
+			if err != nil {
+				t.Fatalf("Prune() error = %v", err)
+			}
+
+			wantKept := tt.wantKept(pin)
+			for version, reason := range always {
+				wantKept[version] = reason
+			}
+
+			kept := make(map[string]string)
+			for _, item := range result.Kept {
+				kept[item.Version] = item.Reason
+			}
+			if !reflect.DeepEqual(kept, wantKept) {
+				t.Errorf("kept = %v, want %v", kept, wantKept)
+			}
+
+			var removed []string
+			for _, item := range result.Removed {
+				removed = append(removed, item.Version)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+			if len(result.Failed) != 0 {
+				t.Errorf("failed = %v", result.Failed)
+			}
+
+			// dry-run 不删除任何文件，也不修改配置
+			cfg, err := m.configStore.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, version := range tt.wantRemoved {
+				if _, ok := cfg.Versions[version]; !ok {
+					t.Errorf("dry run unregistered %s", version)
+				}
+				if _, err := os.Stat(cfg.Versions[version]); err != nil {
+					t.Errorf("dry run removed %s: %v", version, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneRequiresPolicy(t *testing.T) {
+	m, projects, _ := newPruneManager(t)
+
+	// 只固定目录时会删除所有未固定的版本，必须拒绝
+	if _, err := m.Prune(interfaces.PrunePolicy{KeepPinnedDirs: []string{projects}}, true); err == nil {
+		t.Fatal("Prune() without a retention policy succeeded")
+	}
+}
@@ -0,0 +1,279 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// versionFileSkipDirs 查找 .go-version 文件时跳过的目录
+var versionFileSkipDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Resolve 解析指定目录下应使用的版本
+func (m *manager) Resolve(dir string) (*interfaces.ResolvedVersion, error) {
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, errors.ErrOperationFailed.WithCause(err).WithMessage("failed to get working directory")
+		}
+		dir = wd
+	}
+
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	// 1. GX_VERSION 环境变量
+	if envVersion := strings.TrimSpace(os.Getenv(constants.EnvGxVersion)); envVersion != "" {
+		logger.Debug("Version %s selected by %s", envVersion, constants.EnvGxVersion)
+		return m.resolveInstalled(cfg, envVersion, interfaces.VersionSourceEnv, "")
+	}
+
+	// 2. 最近的 .go-version 文件
+	versionFile, err := FindVersionFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if versionFile != "" {
+		fileVersion, err := ReadVersionFile(versionFile)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("Version %s selected by %s", fileVersion, versionFile)
+		return m.resolveInstalled(cfg, fileVersion, interfaces.VersionSourceFile, versionFile)
+	}
+
+	// 3. 全局默认版本
+	if cfg.ActiveVersion != "" {
+		return m.resolveInstalled(cfg, cfg.ActiveVersion, interfaces.VersionSourceGlobal, "")
+	}
+
+	// 4. 回退到系统 PATH 中的 Go
+	systemVersion, err := m.detectSystemGoVersion()
+	if err != nil {
+		logger.Warn("No version could be resolved for %s", dir)
+		return nil, errors.ErrVersionNotFound.WithMessage("no active version found")
+	}
+
+	return &interfaces.ResolvedVersion{
+		GoVersion: *systemVersion,
+		Source:    interfaces.VersionSourceSystem,
+	}, nil
+}
+
+// resolveInstalled 将解析出的版本号（或说明符、别名）映射到已安装的版本
+func (m *manager) resolveInstalled(cfg *interfaces.Config, version string, source interfaces.VersionSource, sourcePath string) (*interfaces.ResolvedVersion, error) {
+	normalizedVersion, ok, err := m.matchInstalled(cfg, version)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, notInstalledError(version, DescribeSource(source, sourcePath)).
+			WithContext("source", string(source)).
+			WithContext("source_path", sourcePath)
+	}
+	versionPath := cfg.Versions[normalizedVersion]
+
+	return &interfaces.ResolvedVersion{
+		GoVersion: interfaces.GoVersion{
+			Version:  normalizedVersion,
+			Path:     versionPath,
+			IsActive: true,
+		},
+		Source:     source,
+		SourcePath: sourcePath,
+	}, nil
+}
+
+// SetLocal 在指定目录写入 .go-version 文件
+// version 可以是精确版本、版本线、关键字、别名或源码构建的版本名，原样写入以便随安装情况浮动
+func (m *manager) SetLocal(dir string, version string) error {
+	version = strings.TrimSpace(version)
+
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	pinned := version
+	if _, isAlias := cfg.Aliases[version]; !isAlias && !isNamedVersion(cfg, version) {
+		parsed, err := goversion.ParseSpec(version)
+		if err != nil {
+			return errors.ErrInvalidVersion.WithMessage(fmt.Sprintf("%q is not a valid version, keyword or alias", version))
+		}
+		pinned = parsed.String()
+	}
+
+	logger.Info("Pinning %s to Go version %s", dir, pinned)
+
+	if _, ok, err := m.matchInstalled(cfg, pinned); err == nil && !ok {
+		logger.Warn("Pinned version %s is not installed yet", pinned)
+	}
+
+	return WriteVersionFile(dir, pinned)
+}
+
+// FindVersionFile 从 dir 开始向上查找最近的 .go-version 文件
+// 未找到时返回空字符串
+func FindVersionFile(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errors.ErrInvalidInput.WithCause(err).WithMessage("invalid directory").WithContext("dir", dir)
+	}
+
+	for {
+		candidate := filepath.Join(absDir, constants.VersionFileName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+
+		parent := filepath.Dir(absDir)
+		if parent == absDir {
+			return "", nil
+		}
+		absDir = parent
+	}
+}
+
+// ReadVersionFile 读取 .go-version 文件中的版本号
+// 忽略空行和以 # 开头的注释行
+func ReadVersionFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.ErrOperationFailed.WithCause(err).WithMessage("failed to open version file").WithContext("path", path)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !goversion.IsSpec(line) && !IsAliasName(line) {
+			return "", errors.ErrInvalidVersion.
+				WithMessage(fmt.Sprintf("invalid version %q in %s", line, path)).
+				WithContext("path", path)
+		}
+		return line, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", errors.ErrOperationFailed.WithCause(err).WithMessage("failed to read version file").WithContext("path", path)
+	}
+
+	return "", errors.ErrInvalidVersion.WithMessage(fmt.Sprintf("version file %s is empty", path)).WithContext("path", path)
+}
+
+// walkVersionFiles 递归查找 dir 中的 .go-version 文件，对每个有效文件调用 fn
+// 版本控制目录和依赖目录会被跳过，无效的版本文件只记录警告
+func walkVersionFiles(dir string, fn func(path string, spec string)) error {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return errors.ErrNotFound.WithCause(err).WithMessage(fmt.Sprintf("directory %s does not exist", dir))
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 无法访问的子目录不影响其他目录的扫描
+			logger.Debug("Skipping %s: %v", path, err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if path != dir && versionFileSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() != constants.VersionFileName {
+			return nil
+		}
+
+		spec, err := ReadVersionFile(path)
+		if err != nil {
+			logger.Warn("Ignoring invalid version file %s: %v", path, err)
+			return nil
+		}
+
+		fn(path, spec)
+		return nil
+	})
+	if err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage(fmt.Sprintf("failed to scan %s for version files", dir))
+	}
+
+	return nil
+}
+
+// WriteVersionFile 在 dir 中写入 .go-version 文件
+// 文件内容不带 "go" 前缀，与其他工具保持兼容；版本线写作 1.22.x
+func WriteVersionFile(dir string, version string) error {
+	path := filepath.Join(dir, constants.VersionFileName)
+	content := goversion.Display(version) + "\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to write version file").WithContext("path", path)
+	}
+
+	return nil
+}
+
+// rewriteVersionFile 将 .go-version 文件中的版本替换为 version，保留注释和空行
+func rewriteVersionFile(path string, version string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to read version file").WithContext("path", path)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lines[i] = goversion.Display(version)
+		break
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return errors.ErrOperationFailed.WithCause(err).WithMessage("failed to write version file").WithContext("path", path)
+	}
+
+	return nil
+}
+
+// DescribeSource 返回版本来源的可读描述
+func DescribeSource(source interfaces.VersionSource, sourcePath string) string {
+	switch source {
+	case interfaces.VersionSourceEnv:
+		return constants.EnvGxVersion + " environment variable"
+	case interfaces.VersionSourceFile:
+		return sourcePath
+	case interfaces.VersionSourceGlobal:
+		return "global default"
+	default:
+		return "system PATH"
+	}
+}
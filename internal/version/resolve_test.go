@@ -0,0 +1,196 @@
+package version
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/kawaiirei0/gx/internal/config"
+	"github.com/kawaiirei0/gx/internal/platform"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// newTestManager 创建使用临时 GX_HOME 的版本管理器，cfg 不为空时先写入配置
+func newTestManager(t *testing.T, cfg *interfaces.Config, downloader interfaces.Downloader) *manager {
+	t.Helper()
+
+	t.Setenv(constants.EnvGxHome, t.TempDir())
+	t.Setenv(constants.EnvGxConfig, "")
+	t.Setenv(constants.EnvGxVersion, "")
+
+	store, err := config.NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != nil {
+		if err := store.Save(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	storage, err := config.NewStorage(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewManager(store, storage, platform.NewAdapter(), nil, downloader, nil).(*manager)
+}
+
+// writeFile 写入测试文件，按需创建父目录
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveOrder(t *testing.T) {
+	cfg := &interfaces.Config{
+		ActiveVersion: "go1.21.5",
+		Versions: map[string]string{
+			"go1.20.14": "/versions/go1.20.14",
+			"go1.21.5":  "/versions/go1.21.5",
+			"go1.22.3":  "/versions/go1.22.3",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		env        string
+		file       string
+		want       string
+		wantSource interfaces.VersionSource
+	}{
+		{
+			name:       "GX_VERSION wins over .go-version and global",
+			env:        "1.20",
+			file:       "1.22.3\n",
+			want:       "go1.20.14",
+			wantSource: interfaces.VersionSourceEnv,
+		},
+		{
+			name:       ".go-version wins over global",
+			file:       "# pinned for CI\n1.22.x\n",
+			want:       "go1.22.3",
+			wantSource: interfaces.VersionSourceFile,
+		},
+		{
+			name:       "global default",
+			want:       "go1.21.5",
+			wantSource: interfaces.VersionSourceGlobal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t, cfg, nil)
+			t.Setenv(constants.EnvGxVersion, tt.env)
+
+			root := t.TempDir()
+			dir := filepath.Join(root, "cmd", "server")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if tt.file != "" {
+				// .go-version 位于上层目录，应向上查找到它
+				writeFile(t, filepath.Join(root, constants.VersionFileName), tt.file)
+			}
+
+			resolved, err := m.Resolve(dir)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if resolved.Version != tt.want || resolved.Source != tt.wantSource {
+				t.Errorf("Resolve() = %s from %s, want %s from %s", resolved.Version, resolved.Source, tt.want, tt.wantSource)
+			}
+			if resolved.Path != cfg.Versions[tt.want] {
+				t.Errorf("Resolve() path = %s, want %s", resolved.Path, cfg.Versions[tt.want])
+			}
+			if tt.wantSource == interfaces.VersionSourceFile && resolved.SourcePath != filepath.Join(root, constants.VersionFileName) {
+				t.Errorf("Resolve() source path = %s", resolved.SourcePath)
+			}
+		})
+	}
+}
+
+func TestResolveSystemFallback(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not in PATH")
+	}
+
+	m := newTestManager(t, &interfaces.Config{Versions: map[string]string{}}, nil)
+
+	resolved, err := m.Resolve(t.TempDir())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolved.Source != interfaces.VersionSourceSystem {
+		t.Errorf("Resolve() source = %s, want %s", resolved.Source, interfaces.VersionSourceSystem)
+	}
+}
+
+func TestResolveNotInstalled(t *testing.T) {
+	m := newTestManager(t, &interfaces.Config{
+		ActiveVersion: "go1.21.5",
+		Versions:      map[string]string{"go1.21.5": "/versions/go1.21.5"},
+	}, nil)
+
+	// .go-version 指定的版本未安装时报错，而不是回退到全局默认版本
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, constants.VersionFileName), "1.22.3\n")
+
+	_, err := m.Resolve(dir)
+	if !errors.IsType(err, errors.ErrVersionNotInstalled) {
+		t.Fatalf("Resolve() error = %v, want ErrVersionNotInstalled", err)
+	}
+}
+
+func TestRewriteVersionFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "version only",
+			content: "1.21.5\n",
+			want:    "1.22.3\n",
+		},
+		{
+			name:    "comments and blank lines are kept",
+			content: "# CI uses the same toolchain\n\n1.21.5\n# trailing note\n",
+			want:    "# CI uses the same toolchain\n\n1.22.3\n# trailing note\n",
+		},
+		{
+			name:    "no trailing newline",
+			content: "# pinned\ngo1.21.5",
+			want:    "# pinned\n1.22.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), constants.VersionFileName)
+			writeFile(t, path, tt.content)
+
+			if err := rewriteVersionFile(path, "go1.22.3"); err != nil {
+				t.Fatalf("rewriteVersionFile() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("rewriteVersionFile() wrote %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
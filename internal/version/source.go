@@ -0,0 +1,574 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/internal/utils"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// sourceKind 源码的获取方式
+type sourceKind int
+
+const (
+	// sourceRelease 下载官方发布的源码包
+	sourceRelease sourceKind = iota
+
+	// sourceGit 从源码仓库获取指定的 git 引用
+	sourceGit
+
+	// sourceDir 复制本地源码目录
+	sourceDir
+)
+
+// buildSource 解析后的源码来源
+type buildSource struct {
+	kind    sourceKind
+	name    string // 注册的版本名
+	version string // 发布版本号（仅 sourceRelease）
+	ref     string // git 引用（仅 sourceGit）
+	dir     string // 本地源码目录（仅 sourceDir）
+}
+
+// treeVersionPattern 匹配源码树中 src/internal/goversion/goversion.go 的次版本号
+var treeVersionPattern = regexp.MustCompile(`(?m)^const Version = (\d+)`)
+
+// commitPattern 完整的 git 提交哈希
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// InstallFromSource 从源码构建并安装 Go，返回注册的版本名
+// 源码在版本目录旁的临时目录中构建，成功后才移动到版本目录；tip 已安装时重新构建并替换
+func (m *manager) InstallFromSource(ctx context.Context, source string, opts interfaces.SourceOptions) (string, error) {
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return "", errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	src, err := m.parseSource(ctx, cfg, source, opts.Name)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Info("Starting source build of %s from %s", src.name, source)
+	startTime := time.Now()
+
+	output := opts.Output
+	if output == nil {
+		output = io.Discard
+	}
+
+	// 创建恢复管理器，构建失败时删除下载的源码包和构建目录
+	recovery := errors.NewRecoveryManager()
+	defer func() {
+		if err := recovery.Cleanup(); err != nil {
+			logger.Warn("Cleanup failed: %v", err)
+		}
+	}()
+
+	if _, ok := cfg.Versions[src.name]; ok && src.name != constants.TipVersion {
+		logger.Warn("Version %s is already installed", src.name)
+		return "", errors.ErrVersionAlreadyInstalled.WithMessage("version " + src.name + " is already installed")
+	}
+
+	// 发布版本的次版本号已知，下载源码前先确认有可用的引导工具链
+	var bootstrap string
+	if src.kind == sourceRelease {
+		if bootstrap, err = m.selectBootstrap(cfg, opts.Bootstrap, goversion.MustParse(src.version).Minor); err != nil {
+			return "", err
+		}
+	}
+
+	// 确保安装目录存在
+	installPath, err := m.installRoot()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(installPath, 0755); err != nil {
+		logger.Error("Failed to create install directory: %v", err)
+		return "", errors.ErrInstallFailed.WithCause(err).WithMessage("failed to create install directory")
+	}
+
+	versionPath := filepath.Join(installPath, src.name)
+	installLock, err := m.acquireInstallLock(ctx, versionPath, src.name)
+	if err != nil {
+		return "", err
+	}
+	defer installLock.Release()
+
+	// 等待期间另一个进程可能已完成安装
+	if cfg, err = m.configStore.Load(); err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return "", errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+	_, installed := cfg.Versions[src.name]
+	if installed && src.name != constants.TipVersion {
+		logger.Info("Version %s was installed by another process", src.name)
+		return src.name, nil
+	}
+	if ctx.Err() != nil {
+		return "", installCancelled(src.name)
+	}
+
+	// 未注册的版本目录是之前被中断的安装留下的
+	if !installed {
+		if _, err := os.Stat(versionPath); err == nil {
+			logger.Warn("Removing leftover directory of an interrupted install: %s", versionPath)
+			if err := os.RemoveAll(versionPath); err != nil {
+				return "", errors.ErrInstallFailed.WithCause(err).WithMessage("failed to remove leftover install directory").WithContext("path", versionPath)
+			}
+		}
+	}
+
+	buildDir := versionPath + constants.BuildDirSuffix
+	if err := os.RemoveAll(buildDir); err != nil {
+		return "", errors.ErrInstallFailed.WithCause(err).WithMessage("failed to remove leftover build directory").WithContext("path", buildDir)
+	}
+	errors.EnsureDirectoryCleanup(recovery, buildDir)
+
+	record := &interfaces.GoVersion{
+		Version: src.name,
+		Path:    versionPath,
+		Origin:  interfaces.OriginSource,
+	}
+	if err := m.fetchSource(ctx, src, buildDir, installPath, opts.Progress, output, recovery, record); err != nil {
+		return "", err
+	}
+
+	// git 引用和本地目录的次版本号要从源码树中读取
+	if bootstrap == "" {
+		if bootstrap, err = m.selectBootstrap(cfg, opts.Bootstrap, treeMinor(buildDir)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := m.runMakeScript(ctx, buildDir, cfg.Versions[bootstrap], output); err != nil {
+		return "", err
+	}
+
+	if err := m.verifyBuild(src, buildDir); err != nil {
+		return "", err
+	}
+	if ctx.Err() != nil {
+		return "", installCancelled(src.name)
+	}
+
+	if err := replaceDir(buildDir, versionPath); err != nil {
+		return "", errors.ErrInstallFailed.WithCause(err).WithMessage("failed to move the build into place").WithContext("path", versionPath)
+	}
+	if !installed {
+		errors.EnsureDirectoryCleanup(recovery, versionPath)
+	}
+
+	err = m.configStore.Update(func(cfg *interfaces.Config) error {
+		if cfg.Versions == nil {
+			cfg.Versions = make(map[string]string)
+		}
+		cfg.Versions[src.name] = versionPath
+		delete(cfg.Linked, src.name)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to save config after source build: %v", err)
+		return "", errors.ErrStorageFailed.WithCause(err).WithMessage("build succeeded but failed to save config")
+	}
+
+	// 构建成功，不再需要清理
+	recovery.Clear()
+
+	record.InstallDate = time.Now()
+	record.InstallDuration = time.Since(startTime)
+	if size, err := utils.DirSize(versionPath); err == nil {
+		record.Size = size
+	}
+	if err := m.storage.SaveVersion(record); err != nil {
+		logger.Warn("Failed to save metadata for %s: %v", src.name, err)
+	}
+
+	logger.Info("Go %s built from source with bootstrap %s in %v", src.name, bootstrap, record.InstallDuration)
+	return src.name, nil
+}
+
+// parseSource 判断 source 是本地目录、tip、版本说明符还是 git 引用，并确定注册的版本名
+func (m *manager) parseSource(ctx context.Context, cfg *interfaces.Config, source string, name string) (*buildSource, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, errors.ErrInvalidInput.WithMessage("no source given: specify a version, git ref or source directory")
+	}
+
+	var src *buildSource
+	_, isAlias := cfg.Aliases[source]
+
+	switch {
+	case isPathLike(source):
+		dir, err := filepath.Abs(source)
+		if err != nil {
+			return nil, errors.ErrInvalidInput.WithCause(err).WithMessage("invalid source directory").WithContext("path", source)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "src", m.makeScriptName())); err != nil {
+			return nil, errors.ErrInvalidInput.
+				WithMessage(fmt.Sprintf("%s is not a Go source tree (src/%s not found)", dir, m.makeScriptName())).
+				WithContext("path", dir)
+		}
+		src = &buildSource{kind: sourceDir, dir: dir, name: constants.SourceVersionPrefix + sanitizeRef(filepath.Base(dir))}
+
+	case strings.EqualFold(source, constants.TipVersion):
+		src = &buildSource{kind: sourceGit, ref: constants.TipRef, name: constants.TipVersion}
+
+	case isAlias || goversion.IsSpec(source):
+		version, err := m.FindRemote(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		src = &buildSource{kind: sourceRelease, version: version, name: version}
+
+	default:
+		src = &buildSource{kind: sourceGit, ref: source, name: constants.SourceVersionPrefix + sanitizeRef(source)}
+	}
+
+	if name != "" {
+		src.name = name
+	}
+
+	// 发布版本以版本号注册，其余版本的名称与别名遵循相同的规则
+	if src.name != src.version {
+		if err := validateSourceName(cfg, src.name); err != nil {
+			return nil, err
+		}
+	}
+
+	return src, nil
+}
+
+// validateSourceName 检查源码构建的版本名
+// 名称不能是版本号、关键字或已有的别名，也不能以 go 开头，以免与发布版本混淆
+func validateSourceName(cfg *interfaces.Config, name string) error {
+	if err := ValidateAliasName(name); err != nil || strings.HasPrefix(strings.ToLower(name), goversion.Prefix) {
+		return errors.ErrInvalidInput.
+			WithMessage(fmt.Sprintf("invalid version name %q: must start with a letter other than 'go' and contain only letters, digits, '-' or '_'", name))
+	}
+	if _, ok := cfg.Aliases[name]; ok {
+		return errors.ErrInvalidInput.
+			WithMessage(fmt.Sprintf("version name %s is already used by an alias; choose another with --name", name))
+	}
+	return nil
+}
+
+// isPathLike 判断 source 是否指向本地目录
+// git 引用中也可能出现 /，甚至与当前目录下的某个目录同名，因此不以 . 开头的相对路径
+// 只有在包含 src/make.bash 或 src/make.bat 时才视为源码目录，否则按 git 引用处理
+func isPathLike(source string) bool {
+	if filepath.IsAbs(source) || strings.HasPrefix(source, ".") {
+		return true
+	}
+	for _, script := range []string{"make.bash", "make.bat"} {
+		if info, err := os.Stat(filepath.Join(source, "src", script)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeRef 将 git 引用或目录名转换为可用作版本名的形式
+// 完整的提交哈希缩短为 12 位
+func sanitizeRef(ref string) string {
+	if commitPattern.MatchString(ref) {
+		ref = ref[:12]
+	}
+	var b strings.Builder
+	for _, r := range ref {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// fetchSource 将源码放入 buildDir，并在 record 中记录来源
+func (m *manager) fetchSource(ctx context.Context, src *buildSource, buildDir string, installPath string, progress interfaces.ProgressCallback, output io.Writer, recovery *errors.RecoveryManager, record *interfaces.GoVersion) error {
+	switch src.kind {
+	case sourceRelease:
+		archivePath := filepath.Join(installPath, src.version+".src"+constants.ArchiveExtTarGz)
+		errors.EnsureFileCleanup(recovery, archivePath)
+
+		logger.Info("Downloading source of %s to %s", src.version, archivePath)
+		result, err := m.downloader.DownloadSource(ctx, src.version, archivePath, progress)
+		if err != nil {
+			logger.Error("Source download failed: %v", err)
+			return err
+		}
+		if err := m.installer.Extract(ctx, archivePath, buildDir); err != nil {
+			logger.Error("Failed to extract source: %v", err)
+			return err
+		}
+
+		record.SourceURL = result.URL
+		record.ReleaseSource = result.Source
+		record.SHA256 = result.SHA256
+		if err := errors.SafeRemoveFile(archivePath); err != nil {
+			logger.Warn("Failed to remove source archive %s: %v", archivePath, err)
+		}
+		return nil
+
+	case sourceGit:
+		repository := constants.GoSourceRepository
+		if effective, err := m.configStore.Settings(""); err == nil && effective.Get(constants.SettingSourceRepository) != "" {
+			repository = effective.Get(constants.SettingSourceRepository)
+		}
+
+		commit, err := fetchGitRef(ctx, repository, src.ref, buildDir, output)
+		if err != nil {
+			return err
+		}
+		record.SourceURL = repository + "@" + commit
+		return nil
+
+	default:
+		logger.Info("Copying source tree %s to %s", src.dir, buildDir)
+		if err := utils.CopyDir(src.dir, buildDir); err != nil {
+			return errors.ErrInstallFailed.WithCause(err).WithMessage("failed to copy source tree").WithContext("path", src.dir)
+		}
+		record.SourceURL = src.dir
+		return nil
+	}
+}
+
+// fetchGitRef 浅克隆 repository 中的 ref 到 dir，返回检出的提交
+// 使用 fetch 而不是 clone --branch，以便 ref 也可以是提交哈希；ctx 取消时终止 git
+func fetchGitRef(ctx context.Context, repository string, ref string, dir string, output io.Writer) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", errors.ErrInstallFailed.WithCause(err).WithMessage("git is required to build from a git ref")
+	}
+
+	logger.Info("Fetching %s from %s", ref, repository)
+	steps := [][]string{
+		{"init", "--quiet", dir},
+		{"-C", dir, "fetch", "--depth", "1", repository, ref},
+		{"-C", dir, "checkout", "--quiet", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Stdout = output
+		cmd.Stderr = output
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return "", errors.ErrCancelled.WithMessage(fmt.Sprintf("fetching %s was interrupted", ref))
+			}
+			return "", errors.ErrInstallFailed.
+				WithCause(err).
+				WithMessage(fmt.Sprintf("failed to fetch %s from %s", ref, repository)).
+				WithContext("ref", ref)
+		}
+	}
+
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", errors.ErrCancelled.WithMessage(fmt.Sprintf("fetching %s was interrupted", ref))
+		}
+		return "", errors.ErrInstallFailed.WithCause(err).WithMessage("failed to determine the fetched commit")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// treeMinor 读取源码树的次版本号，无法确定时返回 -1
+func treeMinor(goRoot string) int {
+	data, err := os.ReadFile(filepath.Join(goRoot, "src", "internal", "goversion", "goversion.go"))
+	if err != nil {
+		return -1
+	}
+	match := treeVersionPattern.FindSubmatch(data)
+	if match == nil {
+		return -1
+	}
+	minor, err := strconv.Atoi(string(match[1]))
+	if err != nil {
+		return -1
+	}
+	return minor
+}
+
+// bootstrapRequirement 返回构建 Go 1.minor 所需引导工具链的最低版本
+// 参见 https://go.dev/doc/install/source#bootstrapFromSource
+func bootstrapRequirement(minor int) (goversion.Version, bool) {
+	switch {
+	case minor >= 22:
+		// 从 Go 1.22 起，需要两个版本之前的偶数次版本的 .6 补丁，例如 1.24 和 1.25 需要 1.22.6
+		return goversion.Version{Major: 1, Minor: minor - 2 - minor%2, Patch: 6, HasPatch: true}, true
+	case minor >= 20:
+		return goversion.Version{Major: 1, Minor: 17, Patch: 13, HasPatch: true}, true
+	case minor >= 5:
+		return goversion.Version{Major: 1, Minor: 4}, true
+	default:
+		return goversion.Version{}, false
+	}
+}
+
+// selectBootstrap 从已安装版本中选择引导工具链，返回其版本号
+// spec 为空时选择最新的已安装正式版本；minor 为目标的次版本号，未知时为 -1
+func (m *manager) selectBootstrap(cfg *interfaces.Config, spec string, minor int) (string, error) {
+	required, known := bootstrapRequirement(minor)
+
+	var version string
+	if spec != "" {
+		matched, ok, err := m.matchInstalled(cfg, spec)
+		if err != nil {
+			return "", err
+		}
+		if !ok || !m.isValidGoInstallation(cfg.Versions[matched]) {
+			return "", notInstalledError(spec, "--bootstrap")
+		}
+		version = matched
+	} else {
+		var candidates []goversion.Version
+		for v, path := range cfg.Versions {
+			parsed, err := goversion.Parse(v)
+			if err != nil || parsed.IsPrerelease() || !m.isValidGoInstallation(path) {
+				continue
+			}
+			candidates = append(candidates, parsed)
+		}
+		if len(candidates) == 0 {
+			hint := "1.22"
+			if known {
+				hint = goversion.Display(required.Line())
+			}
+			return "", errors.ErrVersionNotInstalled.
+				WithMessage(fmt.Sprintf("building Go from source needs an installed Go toolchain to bootstrap. Install one first using: gx install %s", hint))
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[j].Less(candidates[i])
+		})
+		version = candidates[0].String()
+	}
+
+	if parsed, err := goversion.Parse(version); err == nil && known && parsed.Less(required) {
+		selected := "the newest installed release is"
+		if spec != "" {
+			selected = "--bootstrap selected"
+		}
+		return "", errors.ErrVersionNotInstalled.
+			WithMessage(fmt.Sprintf("building Go 1.%d needs Go %s or newer to bootstrap, but %s Go %s. Install one using: gx install %s",
+				minor, required.Short(), selected, goversion.Display(version), goversion.Display(required.Line())))
+	}
+
+	logger.Info("Using Go %s at %s to bootstrap", version, cfg.Versions[version])
+	return version, nil
+}
+
+// makeScriptName 返回当前平台的构建脚本名
+func (m *manager) makeScriptName() string {
+	if m.platform.GetOS() == constants.OSWindows {
+		return constants.MakeScriptWindows
+	}
+	return constants.MakeScriptUnix
+}
+
+// runMakeScript 在 goRoot 中运行构建脚本，ctx 取消时终止构建
+func (m *manager) runMakeScript(ctx context.Context, goRoot string, bootstrapRoot string, output io.Writer) error {
+	script := m.makeScriptName()
+	srcDir := filepath.Join(goRoot, "src")
+
+	var cmd *exec.Cmd
+	if m.platform.GetOS() == constants.OSWindows {
+		cmd = exec.CommandContext(ctx, "cmd", "/c", script)
+	} else {
+		cmd = exec.CommandContext(ctx, "bash", script)
+	}
+	cmd.Dir = srcDir
+	cmd.Env = buildEnv(os.Environ(), bootstrapRoot)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	logger.Info("Running %s in %s with %s=%s", script, srcDir, constants.EnvGoRootBootstrap, bootstrapRoot)
+	if err := cmd.Run(); err != nil {
+		logger.Error("%s failed: %v", script, err)
+		if ctx.Err() != nil {
+			return errors.ErrCancelled.WithMessage(fmt.Sprintf("%s was interrupted", script))
+		}
+		return errors.ErrInstallFailed.
+			WithCause(err).
+			WithMessage(fmt.Sprintf("%s failed", script)).
+			WithContext("path", srcDir)
+	}
+	return nil
+}
+
+// buildEnv 返回运行构建脚本的环境变量
+// 去掉会影响构建结果的 Go 环境变量，并禁止引导工具链自动切换到其他版本
+func buildEnv(environ []string, bootstrapRoot string) []string {
+	drop := map[string]bool{
+		constants.EnvGoRoot:          true,
+		constants.EnvGoRootBootstrap: true,
+		constants.EnvGoToolchain:     true,
+		"GOBIN":                      true,
+		"GOFLAGS":                    true,
+	}
+
+	env := make([]string, 0, len(environ)+2)
+	for _, kv := range environ {
+		if key, _, ok := strings.Cut(kv, "="); ok && drop[key] {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return append(env,
+		constants.EnvGoRootBootstrap+"="+bootstrapRoot,
+		constants.EnvGoToolchain+"=local",
+	)
+}
+
+// verifyBuild 验证构建结果
+// 发布版本检查版本号，其他版本只检查 go 命令可以运行
+func (m *manager) verifyBuild(src *buildSource, goRoot string) error {
+	if src.kind == sourceRelease {
+		return m.installer.Verify(goRoot, src.version)
+	}
+
+	goPath := filepath.Join(goRoot, "bin", m.goExecutableName())
+	output, err := exec.Command(goPath, "version").Output()
+	if err != nil {
+		return errors.ErrInstallFailed.WithCause(err).WithMessage("the built go command does not run")
+	}
+	logger.Info("Built %s", strings.TrimSpace(string(output)))
+	return nil
+}
+
+// replaceDir 将 src 重命名为 dst，dst 已存在时替换它
+func replaceDir(src string, dst string) error {
+	if _, err := os.Stat(dst); os.IsNotExist(err) {
+		return os.Rename(src, dst)
+	}
+
+	old := dst + ".old"
+	if err := os.RemoveAll(old); err != nil {
+		return err
+	}
+	if err := os.Rename(dst, old); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		os.Rename(old, dst)
+		return err
+	}
+	if err := os.RemoveAll(old); err != nil {
+		logger.Warn("Failed to remove previous build %s: %v", old, err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package version
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+func TestBootstrapRequirement(t *testing.T) {
+	tests := []struct {
+		minor  int
+		want   string
+		wantOK bool
+	}{
+		{minor: 25, want: "go1.22.6", wantOK: true},
+		{minor: 24, want: "go1.22.6", wantOK: true},
+		{minor: 23, want: "go1.20.6", wantOK: true},
+		{minor: 22, want: "go1.20.6", wantOK: true},
+		{minor: 21, want: "go1.17.13", wantOK: true},
+		{minor: 20, want: "go1.17.13", wantOK: true},
+		{minor: 19, want: "go1.4", wantOK: true},
+		{minor: 5, want: "go1.4", wantOK: true},
+		{minor: 4, wantOK: false},
+		{minor: -1, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := bootstrapRequirement(tt.minor)
+		if ok != tt.wantOK {
+			t.Errorf("bootstrapRequirement(%d) ok = %v, want %v", tt.minor, ok, tt.wantOK)
+			continue
+		}
+		if ok && got.String() != tt.want {
+			t.Errorf("bootstrapRequirement(%d) = %s, want %s", tt.minor, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "2f3e1c9a8b7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f", want: "2f3e1c9a8b7d"},
+		{ref: "2f3e1c9a8b7d", want: "2f3e1c9a8b7d"},
+		{ref: "release-branch.go1.22", want: "release-branch-go1-22"},
+		{ref: "refs/heads/dev.boringcrypto", want: "refs-heads-dev-boringcrypto"},
+		{ref: "feature_x", want: "feature_x"},
+		{ref: "/work/go-src/", want: "work-go-src"},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizeRef(tt.ref); got != tt.want {
+			t.Errorf("sanitizeRef(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	m := newTestManager(t, &interfaces.Config{
+		Versions: map[string]string{},
+		Aliases:  map[string]string{"work": "go1.22.x"},
+	}, &fakeDownloader{current: []string{"go1.22.5", "go1.21.12"}})
+
+	tree := filepath.Join(t.TempDir(), "go-dev.tree")
+	writeFile(t, filepath.Join(tree, "src", m.makeScriptName()), "")
+	notTree := t.TempDir()
+
+	// 当前目录下的相对路径：只有源码树被当作目录，与分支同名的普通目录按 git 引用处理
+	work := t.TempDir()
+	t.Chdir(work)
+	writeFile(t, filepath.Join(work, "goroot", "src", m.makeScriptName()), "")
+	writeFile(t, filepath.Join(work, "dev.boringcrypto", "notes.txt"), "")
+
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		as      string
+		want    buildSource
+		wantErr bool
+	}{
+		{name: "tip", source: "tip", want: buildSource{kind: sourceGit, ref: "master", name: "tip"}},
+		{name: "tip in capitals", source: "TIP", want: buildSource{kind: sourceGit, ref: "master", name: "tip"}},
+		{name: "version line", source: "1.22", want: buildSource{kind: sourceRelease, version: "go1.22.5", name: "go1.22.5"}},
+		{name: "exact version", source: "go1.21.3", want: buildSource{kind: sourceRelease, version: "go1.21.3", name: "go1.21.3"}},
+		{name: "alias", source: "work", want: buildSource{kind: sourceRelease, version: "go1.22.5", name: "go1.22.5"}},
+		{
+			name:   "branch",
+			source: "release-branch.go1.22",
+			want:   buildSource{kind: sourceGit, ref: "release-branch.go1.22", name: "src-release-branch-go1-22"},
+		},
+		{
+			name:   "commit",
+			source: "2f3e1c9a8b7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f",
+			want:   buildSource{kind: sourceGit, ref: "2f3e1c9a8b7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f", name: "src-2f3e1c9a8b7d"},
+		},
+		{name: "source tree", source: tree, want: buildSource{kind: sourceDir, dir: tree, name: "src-go-dev-tree"}},
+		{
+			name:   "relative source tree",
+			source: "goroot",
+			want:   buildSource{kind: sourceDir, dir: filepath.Join(work, "goroot"), name: "src-goroot"},
+		},
+		{
+			name:   "relative directory that is not a source tree",
+			source: "dev.boringcrypto",
+			want:   buildSource{kind: sourceGit, ref: "dev.boringcrypto", name: "src-dev-boringcrypto"},
+		},
+		{name: "custom name", source: "dev.typeparams", as: "generics", want: buildSource{kind: sourceGit, ref: "dev.typeparams", name: "generics"}},
+		{name: "empty", source: " ", wantErr: true},
+		{name: "not a source tree", source: notTree, wantErr: true},
+		{name: "name starting with go", source: "master", as: "gonext", wantErr: true},
+		{name: "name that is a version", source: "master", as: "1.22", wantErr: true},
+		{name: "name used by an alias", source: "master", as: "work", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.parseSource(context.Background(), cfg, tt.source, tt.as)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSource(%q, %q) error = %v, wantErr %v", tt.source, tt.as, err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("parseSource(%q, %q) = %+v, want %+v", tt.source, tt.as, *got, tt.want)
+			}
+		})
+	}
+}
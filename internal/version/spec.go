@@ -0,0 +1,145 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// FindInstalled 将版本说明符解析为已安装的具体版本
+// 1.22 / 1.22.x 匹配该版本线中已安装的最新补丁版本，latest/stable/oldstable 同理
+func (m *manager) FindInstalled(spec string) (string, error) {
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return "", errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	version, ok, err := m.matchInstalled(cfg, spec)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", notInstalledError(spec, "")
+	}
+
+	return version, nil
+}
+
+// FindRemote 将版本说明符解析为远程可下载的具体版本
+// 精确版本直接返回，不查询远程列表
+func (m *manager) FindRemote(ctx context.Context, spec string) (string, error) {
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return "", errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	parsed, err := parseSpec(cfg, spec)
+	if err != nil {
+		return "", err
+	}
+
+	if parsed.IsExact() {
+		return parsed.Version.String(), nil
+	}
+
+	// 默认列表只包含当前支持的两个版本线，匹配不到时再查询完整列表
+	for _, all := range []bool{false, true} {
+		versions, err := m.downloader.Versions(ctx, all)
+		if err != nil {
+			logger.Error("Failed to fetch remote versions: %v", err)
+			return "", err
+		}
+
+		candidates := make([]string, 0, len(versions))
+		for _, v := range versions {
+			candidates = append(candidates, v.Version)
+		}
+
+		if version, ok := parsed.Select(candidates); ok {
+			logger.Info("Resolved %s to %s", spec, version)
+			return version, nil
+		}
+	}
+
+	return "", errors.ErrVersionNotFound.
+		WithMessage(fmt.Sprintf("no Go release matches %s", goversion.Display(parsed.String())))
+}
+
+// matchInstalled 在已安装版本中查找与说明符匹配的最新版本
+func (m *manager) matchInstalled(cfg *interfaces.Config, spec string) (string, bool, error) {
+	// 从源码构建的版本（如 tip）以名称注册
+	if name := strings.TrimSpace(spec); isNamedVersion(cfg, name) {
+		return name, true, nil
+	}
+
+	parsed, err := parseSpec(cfg, spec)
+	if err != nil {
+		return "", false, err
+	}
+
+	// 精确版本优先按配置中的键查找
+	if parsed.IsExact() {
+		if _, ok := cfg.Versions[parsed.Version.String()]; ok {
+			return parsed.Version.String(), true, nil
+		}
+	}
+
+	installed := make([]string, 0, len(cfg.Versions))
+	for v := range cfg.Versions {
+		installed = append(installed, v)
+	}
+
+	version, ok := parsed.Select(installed)
+	if ok {
+		logger.Debug("Resolved %s to installed version %s", spec, version)
+	}
+	return version, ok, nil
+}
+
+// isNamedVersion 判断 name 是否为以名称而不是版本号注册的已安装版本
+func isNamedVersion(cfg *interfaces.Config, name string) bool {
+	_, ok := cfg.Versions[name]
+	return ok && !goversion.IsValid(name)
+}
+
+// parseSpec 解析版本说明符，先展开别名
+func parseSpec(cfg *interfaces.Config, spec string) (goversion.Spec, error) {
+	name := strings.TrimSpace(spec)
+	if target, ok := cfg.Aliases[name]; ok {
+		logger.Debug("Alias %s expands to %s", name, target)
+		spec = target
+	}
+
+	parsed, err := goversion.ParseSpec(spec)
+	if err != nil {
+		return goversion.Spec{}, errors.ErrInvalidVersion.
+			WithMessage(fmt.Sprintf("%q is not a valid version, keyword or alias", name))
+	}
+
+	return parsed, nil
+}
+
+// notInstalledError 构建版本未安装错误，origin 为版本来源描述（可为空）
+func notInstalledError(spec string, origin string) *errors.Error {
+	versionDisplay := goversion.Display(strings.TrimSpace(spec))
+
+	setBy := ""
+	if origin != "" {
+		setBy = fmt.Sprintf(" (set by %s)", origin)
+	}
+
+	if parsed, err := goversion.ParseSpec(spec); err == nil && !parsed.IsExact() {
+		return errors.ErrVersionNotInstalled.
+			WithMessage(fmt.Sprintf("No installed Go version matches %s%s. Install one using: gx install %s", versionDisplay, setBy, versionDisplay))
+	}
+
+	return errors.ErrVersionNotInstalled.
+		WithMessage(fmt.Sprintf("Go %s%s is not installed. Install it first using: gx install %s", versionDisplay, setBy, versionDisplay))
+}
@@ -0,0 +1,72 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+func TestMatchInstalled(t *testing.T) {
+	cfg := &interfaces.Config{
+		Versions: map[string]string{
+			"go1.21.5":  "/versions/go1.21.5",
+			"go1.21.13": "/versions/go1.21.13",
+			"go1.22.3":  "/versions/go1.22.3",
+			"go1.23rc1": "/versions/go1.23rc1",
+			"tip":       "/versions/tip",
+		},
+		Aliases: map[string]string{
+			"work":   "go1.21.x",
+			"pinned": "go1.22.3",
+			"legacy": "go1.19.x",
+		},
+	}
+
+	tests := []struct {
+		spec    string
+		want    string
+		wantOK  bool
+		wantErr bool
+	}{
+		// 精确版本
+		{spec: "1.21.5", want: "go1.21.5", wantOK: true},
+		{spec: "go1.21.5", want: "go1.21.5", wantOK: true},
+		{spec: " 1.22.3 ", want: "go1.22.3", wantOK: true},
+		{spec: "1.23rc1", want: "go1.23rc1", wantOK: true},
+		{spec: "1.21.6", wantOK: false},
+
+		// 版本线选择最新的补丁
+		{spec: "1.21", want: "go1.21.13", wantOK: true},
+		{spec: "1.21.x", want: "go1.21.13", wantOK: true},
+		{spec: "go1.22", want: "go1.22.3", wantOK: true},
+		{spec: "1.20", wantOK: false},
+
+		// 关键字只匹配正式版本
+		{spec: "latest", want: "go1.22.3", wantOK: true},
+		{spec: "stable", want: "go1.22.3", wantOK: true},
+		{spec: "oldstable", want: "go1.21.13", wantOK: true},
+
+		// 别名
+		{spec: "work", want: "go1.21.13", wantOK: true},
+		{spec: "pinned", want: "go1.22.3", wantOK: true},
+		{spec: "legacy", wantOK: false},
+
+		// 源码构建的版本以名称注册
+		{spec: "tip", want: "tip", wantOK: true},
+
+		{spec: "not a version", wantErr: true},
+	}
+
+	m := &manager{}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, ok, err := m.matchInstalled(cfg, tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchInstalled(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("matchInstalled(%q) = %q, %v, want %q, %v", tt.spec, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package version
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// PlanUpgrade 为每个已安装的次版本线查找远程最新的补丁版本
+// 版本线不再出现在远程列表中时（例如只安装过已撤下的预发布版本）跳过该版本线
+// 链接版本不属于 gx，不参与升级，也不会被迁移引用或删除
+func (m *manager) PlanUpgrade(ctx context.Context) ([]interfaces.UpgradePlan, error) {
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	// 按版本线分组
+	lines := make(map[string][]string)
+	for version := range cfg.Versions {
+		if cfg.Linked[version] {
+			continue
+		}
+		parsed, err := goversion.Parse(version)
+		if err != nil {
+			continue
+		}
+		lines[parsed.Line()] = append(lines[parsed.Line()], version)
+	}
+
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	lineNames := make([]string, 0, len(lines))
+	for line := range lines {
+		lineNames = append(lineNames, line)
+	}
+	goversion.Sort(lineNames)
+
+	// 默认列表只包含当前支持的两个版本线，较旧的版本线需要查询完整列表
+	var plans []interfaces.UpgradePlan
+	pending := lineNames
+	for _, all := range []bool{false, true} {
+		remote, err := m.downloader.Versions(ctx, all)
+		if err != nil {
+			logger.Error("Failed to fetch remote versions: %v", err)
+			return nil, err
+		}
+
+		candidates := make([]string, 0, len(remote))
+		for _, v := range remote {
+			candidates = append(candidates, v.Version)
+		}
+
+		var unresolved []string
+		for _, line := range pending {
+			spec := goversion.MustParseSpec(goversion.Display(line) + ".x")
+			latest, ok := spec.Select(candidates)
+			if !ok {
+				unresolved = append(unresolved, line)
+				continue
+			}
+
+			installed := lines[line]
+			goversion.Sort(installed)
+			plans = append(plans, interfaces.UpgradePlan{
+				Line:      line,
+				Installed: installed,
+				From:      installed[len(installed)-1],
+				To:        newest(installed[len(installed)-1], latest),
+			})
+		}
+
+		pending = unresolved
+		if len(pending) == 0 {
+			break
+		}
+	}
+
+	for _, line := range pending {
+		logger.Warn("No remote release found for %s, skipping", line)
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		return goversion.Compare(plans[i].Line, plans[j].Line) < 0
+	})
+	return plans, nil
+}
+
+// Repoint 将指向旧版本的激活版本、别名和 .go-version 文件改为指向新版本
+// 只修改精确版本的引用，版本线（1.21.x）和关键字会自动匹配到新版本；
+// pins 中的目录递归查找 .go-version 文件，文件则只改写该文件
+func (m *manager) Repoint(from []string, to string, pins []string) (*interfaces.RepointResult, error) {
+	old := make(map[string]bool, len(from))
+	for _, v := range from {
+		if v != to {
+			old[v] = true
+		}
+	}
+
+	result := &interfaces.RepointResult{}
+	if len(old) == 0 {
+		return result, nil
+	}
+
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Error("Failed to load config: %v", err)
+		return nil, errors.ErrStorageFailed.WithCause(err).WithMessage("failed to load config")
+	}
+
+	if _, ok := cfg.Versions[to]; !ok {
+		return nil, notInstalledError(to, "")
+	}
+
+	target := goversion.MustParseSpec(to).String()
+
+	// 1. 别名
+	err = m.configStore.Update(func(cfg *interfaces.Config) error {
+		for name, spec := range cfg.Aliases {
+			parsed, err := goversion.ParseSpec(spec)
+			if err != nil || !parsed.IsExact() || !old[parsed.Version.String()] {
+				continue
+			}
+			cfg.Aliases[name] = target
+			result.Aliases = append(result.Aliases, name)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to repoint aliases: %v", err)
+		return nil, err
+	}
+	sort.Strings(result.Aliases)
+
+	if len(result.Aliases) > 0 {
+		logger.Info("Repointed aliases %v to %s", result.Aliases, to)
+	}
+
+	// 2. 激活版本
+	if old[cfg.ActiveVersion] {
+		if err := m.SwitchTo(to); err != nil {
+			return result, err
+		}
+		result.Active = true
+	}
+
+	// 3. .go-version 文件
+	for _, pin := range pins {
+		var rewriteErr error
+		err := walkPins(pin, func(path string, spec string) {
+			if rewriteErr != nil {
+				return
+			}
+			parsed, err := goversion.ParseSpec(spec)
+			if err != nil || !parsed.IsExact() || !old[parsed.Version.String()] {
+				return
+			}
+			if rewriteErr = rewriteVersionFile(path, to); rewriteErr == nil {
+				logger.Info("Repointed %s from %s to %s", path, spec, to)
+				result.Pins = append(result.Pins, path)
+			}
+		})
+		if err == nil {
+			err = rewriteErr
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// walkPins 对 pin 中的 .go-version 文件调用 fn：pin 为目录时递归查找，为文件时只处理该文件
+func walkPins(pin string, fn func(path string, spec string)) error {
+	if info, err := os.Stat(pin); err != nil || info.IsDir() {
+		return walkVersionFiles(pin, fn)
+	}
+
+	spec, err := ReadVersionFile(pin)
+	if err != nil {
+		logger.Warn("Ignoring invalid version file %s: %v", pin, err)
+		return nil
+	}
+	fn(pin, spec)
+	return nil
+}
+
+// newest 返回两个版本中较新的一个
+func newest(a string, b string) string {
+	if goversion.Compare(a, b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,238 @@
+package version
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// fakeDownloader 只提供版本列表的下载器
+type fakeDownloader struct {
+	interfaces.Downloader
+	current []string // Versions(false) 返回的版本
+	all     []string // Versions(true) 额外返回的历史版本
+}
+
+func (d *fakeDownloader) Versions(ctx context.Context, all bool) ([]interfaces.RemoteVersion, error) {
+	versions := d.current
+	if all {
+		versions = append(append([]string(nil), d.current...), d.all...)
+	}
+
+	remote := make([]interfaces.RemoteVersion, len(versions))
+	for i, v := range versions {
+		remote[i] = interfaces.RemoteVersion{Version: v, Stable: true}
+	}
+	return remote, nil
+}
+
+// fakeEnvManager 记录 current 指针切换的环境管理器
+type fakeEnvManager struct {
+	interfaces.EnvironmentManager
+	current string
+}
+
+func (e *fakeEnvManager) SwitchCurrent(goRoot string) error {
+	e.current = goRoot
+	return nil
+}
+
+// fakeGoRoot 创建只包含 go 可执行文件的 GOROOT
+func fakeGoRoot(t *testing.T, root string, version string) string {
+	t.Helper()
+
+	exe := "go"
+	if runtime.GOOS == constants.OSWindows {
+		exe = "go.exe"
+	}
+	dir := filepath.Join(root, version)
+	writeFile(t, filepath.Join(dir, "bin", exe), "")
+	return dir
+}
+
+func TestPlanUpgrade(t *testing.T) {
+	m := newTestManager(t, &interfaces.Config{
+		Versions: map[string]string{
+			"go1.19.2":  "/versions/go1.19.2",
+			"go1.20.3":  "/versions/go1.20.3",
+			"go1.21.5":  "/versions/go1.21.5",
+			"go1.21.10": "/versions/go1.21.10",
+			"go1.22.1":  "/versions/go1.22.1",
+			"go1.22.3":  "/opt/go1.22.3",
+			"go1.23.0":  "/opt/go1.23.0",
+			"tip":       "/versions/tip",
+		},
+		Linked: map[string]bool{"go1.22.3": true, "go1.23.0": true},
+	}, &fakeDownloader{
+		current: []string{"go1.23.2", "go1.22.5", "go1.22.4", "go1.21.12"},
+		all:     []string{"go1.19.13", "go1.19.12"},
+	})
+
+	plans, err := m.PlanUpgrade(context.Background())
+	if err != nil {
+		t.Fatalf("PlanUpgrade() error = %v", err)
+	}
+
+	// go1.19 只在完整列表中；go1.20 不在远程列表中，跳过；
+	// 链接版本不参与升级，只有链接版本的 go1.23 没有计划
+	want := []interfaces.UpgradePlan{
+		{Line: "go1.19", Installed: []string{"go1.19.2"}, From: "go1.19.2", To: "go1.19.13"},
+		{Line: "go1.21", Installed: []string{"go1.21.5", "go1.21.10"}, From: "go1.21.10", To: "go1.21.12"},
+		{Line: "go1.22", Installed: []string{"go1.22.1"}, From: "go1.22.1", To: "go1.22.5"},
+	}
+	if !reflect.DeepEqual(plans, want) {
+		t.Errorf("PlanUpgrade() = %+v, want %+v", plans, want)
+	}
+}
+
+func TestPlanUpgradeUpToDate(t *testing.T) {
+	m := newTestManager(t, &interfaces.Config{
+		Versions: map[string]string{"go1.22.5": "/versions/go1.22.5"},
+	}, &fakeDownloader{current: []string{"go1.22.5", "go1.21.12"}})
+
+	plans, err := m.PlanUpgrade(context.Background())
+	if err != nil {
+		t.Fatalf("PlanUpgrade() error = %v", err)
+	}
+	if len(plans) != 1 || plans[0].From != plans[0].To {
+		t.Errorf("PlanUpgrade() = %+v, want an up-to-date plan for go1.22", plans)
+	}
+}
+
+func TestRepoint(t *testing.T) {
+	root := t.TempDir()
+	cfg := &interfaces.Config{
+		ActiveVersion: "go1.21.5",
+		Versions:      make(map[string]string),
+		Aliases: map[string]string{
+			"work":   "go1.21.5",
+			"legacy": "go1.21.10",
+			"line":   "go1.21.x",
+			"ext":    "go1.22.3",
+		},
+		Linked: map[string]bool{"go1.22.3": true},
+	}
+	for _, version := range []string{"go1.21.5", "go1.21.10", "go1.21.12", "go1.22.3"} {
+		cfg.Versions[version] = fakeGoRoot(t, root, version)
+	}
+
+	m := newTestManager(t, cfg, nil)
+	env := &fakeEnvManager{}
+	m.envManager = env
+
+	projects := t.TempDir()
+	pins := map[string]string{
+		"api":     "# toolchain\n1.21.5\n",
+		"worker":  "go1.21.10\n",
+		"cli":     "1.21.x\n",
+		"adopted": "1.22.3\n",
+	}
+	for dir, content := range pins {
+		writeFile(t, filepath.Join(projects, dir, constants.VersionFileName), content)
+	}
+
+	result, err := m.Repoint([]string{"go1.21.5", "go1.21.10", "go1.21.12"}, "go1.21.12", []string{projects})
+	if err != nil {
+		t.Fatalf("Repoint() error = %v", err)
+	}
+
+	// 激活版本
+	if !result.Active {
+		t.Error("Repoint() did not move the active version")
+	}
+	if env.current != cfg.Versions["go1.21.12"] {
+		t.Errorf("current points to %q, want %q", env.current, cfg.Versions["go1.21.12"])
+	}
+
+	// 别名：只修改指向旧版本的精确版本
+	if want := []string{"legacy", "work"}; !reflect.DeepEqual(result.Aliases, want) {
+		t.Errorf("repointed aliases = %v, want %v", result.Aliases, want)
+	}
+	saved, err := m.configStore.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantAliases := map[string]string{
+		"work":   "go1.21.12",
+		"legacy": "go1.21.12",
+		"line":   "go1.21.x",
+		"ext":    "go1.22.3",
+	}
+	if !reflect.DeepEqual(saved.Aliases, wantAliases) {
+		t.Errorf("aliases = %v, want %v", saved.Aliases, wantAliases)
+	}
+	if saved.ActiveVersion != "go1.21.12" {
+		t.Errorf("active version = %s, want go1.21.12", saved.ActiveVersion)
+	}
+
+	// .go-version 文件：版本线和其他版本不变，注释保留
+	if len(result.Pins) != 2 {
+		t.Errorf("repointed pins = %v, want api and worker", result.Pins)
+	}
+	wantPins := map[string]string{
+		"api":     "# toolchain\n1.21.12\n",
+		"worker":  "1.21.12\n",
+		"cli":     "1.21.x\n",
+		"adopted": "1.22.3\n",
+	}
+	for dir, want := range wantPins {
+		data, err := os.ReadFile(filepath.Join(projects, dir, constants.VersionFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s/.go-version = %q, want %q", dir, data, want)
+		}
+	}
+}
+
+func TestRepointSingleVersionFile(t *testing.T) {
+	root := t.TempDir()
+	cfg := &interfaces.Config{Versions: make(map[string]string)}
+	for _, version := range []string{"go1.21.5", "go1.21.12"} {
+		cfg.Versions[version] = fakeGoRoot(t, root, version)
+	}
+	m := newTestManager(t, cfg, nil)
+
+	// 只改写给定的文件，不扫描同一目录树中的其他 .go-version 文件
+	projects := t.TempDir()
+	pinned := filepath.Join(projects, constants.VersionFileName)
+	nested := filepath.Join(projects, "tools", constants.VersionFileName)
+	writeFile(t, pinned, "1.21.5\n")
+	writeFile(t, nested, "1.21.5\n")
+
+	result, err := m.Repoint([]string{"go1.21.5", "go1.21.12"}, "go1.21.12", []string{pinned})
+	if err != nil {
+		t.Fatalf("Repoint() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Pins, []string{pinned}) {
+		t.Errorf("repointed pins = %v, want %v", result.Pins, []string{pinned})
+	}
+
+	for path, want := range map[string]string{pinned: "1.21.12\n", nested: "1.21.5\n"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", path, data, want)
+		}
+	}
+}
+
+func TestRepointRequiresInstalledTarget(t *testing.T) {
+	m := newTestManager(t, &interfaces.Config{
+		ActiveVersion: "go1.21.5",
+		Versions:      map[string]string{"go1.21.5": "/versions/go1.21.5"},
+	}, nil)
+
+	if _, err := m.Repoint([]string{"go1.21.5"}, "go1.21.12", nil); err == nil {
+		t.Fatal("Repoint() to a version that is not installed succeeded")
+	}
+}
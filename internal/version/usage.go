@@ -0,0 +1,77 @@
+package version
+
+import (
+	"os"
+	"time"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+// RecordUsage 记录版本被使用的时间
+// shim 和包装器每次调用 go 都会记录；清理策略只关心日期，同一天内已有记录时不再写入，
+// 常见情况下 shim 只读取配置和元数据文件。记录失败不影响命令执行，只写入调试日志
+func (m *manager) RecordUsage(version string) {
+	cfg, err := m.configStore.Load()
+	if err != nil {
+		logger.Debug("Failed to load config for usage tracking: %v", err)
+		return
+	}
+
+	// 系统 PATH 中的 Go 不由 gx 管理，无需记录
+	if _, ok := cfg.Versions[version]; !ok {
+		return
+	}
+
+	now := time.Now()
+	if record, err := m.storage.GetVersion(version); err == nil && sameDay(record.LastUsed, now) {
+		return
+	}
+
+	m.markUsed(cfg, version, now)
+}
+
+// sameDay 判断两个时间是否在本地时区的同一天
+func sameDay(a time.Time, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// markUsed 在元数据中更新版本的最近使用时间
+func (m *manager) markUsed(cfg *interfaces.Config, version string, t time.Time) {
+	record, err := m.storage.GetVersion(version)
+	if err != nil {
+		// 元数据功能加入之前安装的版本没有记录，补建一条
+		record = &interfaces.GoVersion{Version: version, Path: cfg.Versions[version]}
+	}
+	record.LastUsed = t
+
+	if err := m.storage.SaveVersion(record); err != nil {
+		logger.Debug("Failed to save usage for %s: %v", version, err)
+	}
+}
+
+// lastUsed 返回的时间来源，同时用作清理结果中的标签
+const (
+	activityUsed      = "used"      // 记录的最近使用时间
+	activityInstalled = "installed" // 没有使用记录，以安装时间代替
+	activityModified  = "modified"  // 没有元数据，以安装目录的修改时间代替
+)
+
+// lastUsed 返回版本的最近使用时间及其来源
+// 没有使用记录时依次以安装时间、安装目录的修改时间代替；都没有时返回零值和空字符串
+func (m *manager) lastUsed(cfg *interfaces.Config, version string) (time.Time, string) {
+	if record, err := m.storage.GetVersion(version); err == nil {
+		if !record.LastUsed.IsZero() {
+			return record.LastUsed, activityUsed
+		}
+		if !record.InstallDate.IsZero() {
+			return record.InstallDate, activityInstalled
+		}
+	}
+	if info, err := os.Stat(cfg.Versions[version]); err == nil {
+		return info.ModTime(), activityModified
+	}
+	return time.Time{}, ""
+}
@@ -0,0 +1,44 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+func TestRecordUsageOncePerDay(t *testing.T) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		lastUsed  time.Time
+		wantWrite bool
+	}{
+		{name: "already recorded today", lastUsed: startOfDay, wantWrite: false},
+		{name: "recorded yesterday", lastUsed: startOfDay.Add(-time.Minute), wantWrite: true},
+		{name: "never recorded", wantWrite: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t, &interfaces.Config{
+				Versions: map[string]string{"go1.22.3": "/versions/go1.22.3"},
+			}, nil)
+			if err := m.storage.SaveVersion(&interfaces.GoVersion{Version: "go1.22.3", LastUsed: tt.lastUsed}); err != nil {
+				t.Fatal(err)
+			}
+
+			m.RecordUsage("go1.22.3")
+
+			record, err := m.storage.GetVersion("go1.22.3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if written := !record.LastUsed.Equal(tt.lastUsed); written != tt.wantWrite {
+				t.Errorf("RecordUsage() wrote = %v, want %v (last used %s)", written, tt.wantWrite, record.LastUsed)
+			}
+		})
+	}
+}
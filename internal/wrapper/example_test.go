@@ -23,12 +23,19 @@ func Example_executeGoVersion() {
 		return
 	}
 
+	storage, err := config.NewStorage(configStore)
+	if err != nil {
+		fmt.Printf("Error creating storage: %v\n", err)
+		return
+	}
+
 	envManager := environment.NewManager(platformAdapter)
-	downloaderInstance := downloader.NewDownloader()
+	downloaderInstance := downloader.NewDownloader(nil, nil)
 	installerInstance := installer.NewInstaller(platformAdapter)
 
 	versionManager := version.NewManager(
 		configStore,
+		storage,
 		platformAdapter,
 		envManager,
 		downloaderInstance,
@@ -61,12 +68,19 @@ func Example_executeGoRun() {
 		return
 	}
 
+	storage, err := config.NewStorage(configStore)
+	if err != nil {
+		fmt.Printf("Error creating storage: %v\n", err)
+		return
+	}
+
 	envManager := environment.NewManager(platformAdapter)
-	downloaderInstance := downloader.NewDownloader()
+	downloaderInstance := downloader.NewDownloader(nil, nil)
 	installerInstance := installer.NewInstaller(platformAdapter)
 
 	versionManager := version.NewManager(
 		configStore,
+		storage,
 		platformAdapter,
 		envManager,
 		downloaderInstance,
@@ -99,12 +113,19 @@ func Example_getGoExecutable() {
 		return
 	}
 
+	storage, err := config.NewStorage(configStore)
+	if err != nil {
+		fmt.Printf("Error creating storage: %v\n", err)
+		return
+	}
+
 	envManager := environment.NewManager(platformAdapter)
-	downloaderInstance := downloader.NewDownloader()
+	downloaderInstance := downloader.NewDownloader(nil, nil)
 	installerInstance := installer.NewInstaller(platformAdapter)
 
 	versionManager := version.NewManager(
 		configStore,
+		storage,
 		platformAdapter,
 		envManager,
 		downloaderInstance,
@@ -134,12 +155,19 @@ func Example_executeWithArguments() {
 		return
 	}
 
+	storage, err := config.NewStorage(configStore)
+	if err != nil {
+		fmt.Printf("Error creating storage: %v\n", err)
+		return
+	}
+
 	envManager := environment.NewManager(platformAdapter)
-	downloaderInstance := downloader.NewDownloader()
+	downloaderInstance := downloader.NewDownloader(nil, nil)
 	installerInstance := installer.NewInstaller(platformAdapter)
 
 	versionManager := version.NewManager(
 		configStore,
+		storage,
 		platformAdapter,
 		envManager,
 		downloaderInstance,
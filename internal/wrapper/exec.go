@@ -0,0 +1,125 @@
+package wrapper
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/kawaiirei0/gx/internal/logger"
+	"github.com/kawaiirei0/gx/pkg/constants"
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+// Run 运行已配置好的子进程命令
+// 终端产生的中断信号由子进程自行处理，gx 只转发终止信号并等待子进程退出，
+// 子进程以非零状态结束时返回保留退出码的 ExitError
+func Run(cmd *exec.Cmd) error {
+	logger.Debug("Running %s %v", cmd.Path, cmd.Args[1:])
+
+	// 在子进程运行期间接管信号，避免 gx 先于子进程退出
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, append([]os.Signal{os.Interrupt}, forwardedSignals...)...)
+	defer signal.Stop(sigCh)
+
+	if err := cmd.Start(); err != nil {
+		return errors.ErrOperationFailed.
+			WithCause(err).
+			WithMessage(fmt.Sprintf("failed to start %s", filepath.Base(cmd.Path))).
+			WithContext("path", cmd.Path)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-sigCh:
+				// 中断信号已由终端发送给整个前台进程组，无需重复转发
+				if sig != os.Interrupt {
+					cmd.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return &ExitError{
+				ExitCode: exitErr.ExitCode(),
+				Err:      exitErr,
+				Program:  filepath.Base(cmd.Path),
+				Args:     cmd.Args[1:],
+			}
+		}
+		return errors.ErrOperationFailed.
+			WithCause(err).
+			WithMessage(fmt.Sprintf("failed to run %s", filepath.Base(cmd.Path))).
+			WithContext("path", cmd.Path)
+	}
+
+	return nil
+}
+
+// LookPathIn 在给定的 PATH 值中查找可执行文件
+// 与 exec.LookPath 不同，它不读取当前进程的 PATH，因此可以为子进程的环境解析命令
+func LookPathIn(file string, pathEnv string) (string, error) {
+	// 包含路径分隔符时直接使用
+	if strings.ContainsAny(file, `/\`) {
+		if isExecutableFile(file) {
+			return file, nil
+		}
+		return "", errors.ErrNotFound.WithMessage(fmt.Sprintf("%s is not an executable file", file))
+	}
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		for _, candidate := range executableNames(file) {
+			path := filepath.Join(dir, candidate)
+			if isExecutableFile(path) {
+				return path, nil
+			}
+		}
+	}
+
+	return "", errors.ErrNotFound.WithMessage(fmt.Sprintf("%s not found in PATH", file))
+}
+
+// executableNames 返回可能的可执行文件名（Windows 上补全 PATHEXT 扩展名）
+func executableNames(file string) []string {
+	if runtime.GOOS != constants.OSWindows || filepath.Ext(file) != "" {
+		return []string{file}
+	}
+
+	exts := os.Getenv("PATHEXT")
+	if exts == "" {
+		exts = ".com;.exe;.bat;.cmd"
+	}
+
+	var names []string
+	for _, ext := range strings.Split(exts, ";") {
+		if ext != "" {
+			names = append(names, file+strings.ToLower(ext))
+		}
+	}
+	return names
+}
+
+// isExecutableFile 检查路径是否为可执行的普通文件
+func isExecutableFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == constants.OSWindows {
+		return true
+	}
+	return info.Mode()&0111 != 0
+}
@@ -0,0 +1,95 @@
+package wrapper
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kawaiirei0/gx/internal/platform"
+	"github.com/kawaiirei0/gx/pkg/interfaces"
+)
+
+func TestLookPathIn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+
+	first := t.TempDir()
+	second := t.TempDir()
+
+	tool := filepath.Join(second, "tool")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// 不可执行的同名文件应被跳过
+	if err := os.WriteFile(filepath.Join(first, "tool"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pathEnv := first + string(os.PathListSeparator) + second
+	got, err := LookPathIn("tool", pathEnv)
+	if err != nil {
+		t.Fatalf("LookPathIn() error = %v", err)
+	}
+	if got != tool {
+		t.Errorf("LookPathIn() = %s, want %s", got, tool)
+	}
+
+	if _, err := LookPathIn("missing", pathEnv); err == nil {
+		t.Error("LookPathIn(missing) should fail")
+	}
+}
+
+func TestRunPreservesExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+
+	err := Run(exec.Command("/bin/sh", "-c", "exit 3"))
+	exitErr, ok := IsExitError(err)
+	if !ok {
+		t.Fatalf("Run() error = %v, want *ExitError", err)
+	}
+	if exitErr.GetExitCode() != 3 {
+		t.Errorf("exit code = %d, want 3", exitErr.GetExitCode())
+	}
+
+	if err := Run(exec.Command("/bin/sh", "-c", "true")); err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestExecuteWithVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+
+	goRoot := t.TempDir()
+	binDir := filepath.Join(goRoot, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"$1 $GOROOT $GX_VERSION\"\necho warn >&2\nexit 2\n"
+	if err := os.WriteFile(filepath.Join(binDir, "go"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewCLIWrapper(nil, platform.NewAdapter())
+	var stdout, stderr bytes.Buffer
+	err := w.ExecuteWithVersion(&interfaces.GoVersion{Version: "go1.21.5", Path: goRoot}, "test", nil, &stdout, &stderr)
+
+	exitErr, ok := IsExitError(err)
+	if !ok || exitErr.GetExitCode() != 2 {
+		t.Fatalf("ExecuteWithVersion() error = %v, want exit code 2", err)
+	}
+	if got, want := strings.TrimSpace(stdout.String()), "test "+goRoot+" 1.21.5"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got := strings.TrimSpace(stderr.String()); got != "warn" {
+		t.Errorf("stderr = %q, want %q", got, "warn")
+	}
+}
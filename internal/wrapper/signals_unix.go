@@ -0,0 +1,11 @@
+//go:build linux || darwin
+
+package wrapper
+
+import (
+	"os"
+	"syscall"
+)
+
+// forwardedSignals 需要转发给子进程的信号
+var forwardedSignals = []os.Signal{syscall.SIGTERM, syscall.SIGHUP}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package wrapper
+
+import "os"
+
+// forwardedSignals Windows 不支持向子进程转发信号
+var forwardedSignals = []os.Signal{}
@@ -2,13 +2,16 @@ package wrapper
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 
+	"github.com/kawaiirei0/gx/internal/environment"
 	"github.com/kawaiirei0/gx/internal/logger"
 	"github.com/kawaiirei0/gx/pkg/constants"
 	"github.com/kawaiirei0/gx/pkg/errors"
+	"github.com/kawaiirei0/gx/pkg/goversion"
 	"github.com/kawaiirei0/gx/pkg/interfaces"
 )
 
@@ -31,7 +34,7 @@ func (w *cliWrapper) Execute(command string, args []string) error {
 	logger.Info("Executing Go command: %s %v", command, args)
 	
 	// 获取 Go 可执行文件路径
-	goExe, err := w.GetGoExecutable()
+	resolved, goExe, err := w.resolveGoExecutable()
 	if err != nil {
 		logger.Error("Failed to get Go executable: %v", err)
 		return errors.Wrap(err, "OPERATION_FAILED", "failed to get Go executable").
@@ -40,17 +43,51 @@ func (w *cliWrapper) Execute(command string, args []string) error {
 	}
 	logger.Debug("Using Go executable: %s", goExe)
 
+	w.versionManager.RecordUsage(resolved.Version)
+
+	return w.run(goExe, command, args, nil, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// ExecuteWithVersion 使用指定的已安装版本执行 Go 命令
+func (w *cliWrapper) ExecuteWithVersion(version *interfaces.GoVersion, command string, args []string, stdout, stderr io.Writer) error {
+	logger.Info("Executing Go command with %s: %s %v", version.Version, command, args)
+
+	goExe, err := w.goExecutableIn(version.Path)
+	if err != nil {
+		return err
+	}
+
+	// 子进程环境指向所选版本，使其中再调用的 go 和 gx 解析到同一版本
+	binDir := filepath.Join(version.Path, "bin")
+	env := environment.MergeEnv(os.Environ(), map[string]string{
+		constants.EnvGoRoot:    version.Path,
+		constants.EnvPath:      binDir + string(os.PathListSeparator) + os.Getenv(constants.EnvPath),
+		constants.EnvGxVersion: goversion.Display(version.Version),
+	})
+
+	// 仅执行指定版本时可以不提供版本管理器
+	if w.versionManager != nil {
+		w.versionManager.RecordUsage(version.Version)
+	}
+
+	// 可能有多个版本并行执行，不共享标准输入
+	return w.run(goExe, command, args, env, nil, stdout, stderr)
+}
+
+// run 执行 Go 命令并保留退出码；env 为 nil 时继承当前进程环境
+func (w *cliWrapper) run(goExe string, command string, args []string, env []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	// 构建完整的命令参数
 	// 第一个参数是 Go 子命令（如 run, build, test）
 	cmdArgs := append([]string{command}, args...)
 
 	// 创建命令
 	cmd := exec.Command(goExe, cmdArgs...)
+	cmd.Env = env
 
 	// 透传标准输入、输出、错误流
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
 	// 执行命令
 	if err := cmd.Run(); err != nil {
@@ -80,24 +117,41 @@ func (w *cliWrapper) Execute(command string, args []string) error {
 
 // GetGoExecutable 获取当前使用的 Go 可执行文件路径
 func (w *cliWrapper) GetGoExecutable() (string, error) {
-	// 获取当前激活的版本
-	activeVersion, err := w.versionManager.GetActive()
+	_, goExe, err := w.resolveGoExecutable()
+	return goExe, err
+}
+
+// resolveGoExecutable 按解析链获取当前目录应使用的版本及其 Go 可执行文件路径
+func (w *cliWrapper) resolveGoExecutable() (*interfaces.ResolvedVersion, string, error) {
+	activeVersion, err := w.versionManager.Resolve("")
 	if err != nil {
-		return "", errors.ErrVersionNotFound.WithCause(err).WithMessage("no active Go version found")
+		if errors.IsType(err, errors.ErrVersionNotInstalled) {
+			return nil, "", err
+		}
+		return nil, "", errors.ErrVersionNotFound.WithCause(err).WithMessage("no active Go version found")
 	}
 
 	// 验证版本路径是否存在
 	if activeVersion.Path == "" {
-		return "", errors.ErrVersionNotFound.WithMessage("active version path is empty")
+		return nil, "", errors.ErrVersionNotFound.WithMessage("active version path is empty")
 	}
 
+	goExe, err := w.goExecutableIn(activeVersion.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	return activeVersion, goExe, nil
+}
+
+// goExecutableIn 获取指定 GOROOT 下的 Go 可执行文件路径
+func (w *cliWrapper) goExecutableIn(goRoot string) (string, error) {
 	// 构建 Go 可执行文件路径
 	goExe := "go"
 	if w.platform.GetOS() == constants.OSWindows {
 		goExe = "go.exe"
 	}
 
-	goPath := filepath.Join(activeVersion.Path, "bin", goExe)
+	goPath := filepath.Join(goRoot, "bin", goExe)
 
 	// 验证文件是否存在
 	if _, err := os.Stat(goPath); os.IsNotExist(err) {
@@ -118,12 +172,16 @@ func (w *cliWrapper) GetGoExecutable() (string, error) {
 type ExitError struct {
 	ExitCode int
 	Err      error
-	Command  string
+	Command  string // Go 子命令（如 "test"），仅由 Execute 设置
+	Program  string // 任意程序名（如 "make"），仅由 Run 设置
 	Args     []string
 }
 
 // Error 实现 error 接口
 func (e *ExitError) Error() string {
+	if e.Program != "" {
+		return fmt.Sprintf("command '%s' exited with code %d", e.Program, e.ExitCode)
+	}
 	if e.Command != "" {
 		return fmt.Sprintf("command 'go %s' exited with code %d", e.Command, e.ExitCode)
 	}
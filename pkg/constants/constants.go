@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 const (
 	// AppName 应用名称
 	AppName = "gx"
@@ -7,20 +9,44 @@ const (
 	// AppVersion 应用版本
 	AppVersion = "0.1.0"
 
-	// DefaultInstallDir 默认安装目录
-	DefaultInstallDir = ".gx/versions"
+	// VersionsDirName 默认的版本安装目录名（位于根目录下）
+	VersionsDirName = "versions"
+
+	// DownloadsDirName 未完成下载的目录名（位于根目录下），中断的下载从这里续传
+	DownloadsDirName = "downloads"
+
+	// PartialFileSuffix 未完成下载的文件后缀
+	PartialFileSuffix = ".part"
+
+	// LogDirName 日志目录名（位于根目录下）
+	LogDirName = "logs"
 
 	// ConfigFileName 配置文件名
 	ConfigFileName = "config.json"
 
-	// ConfigDir 配置目录
+	// ConfigDir 默认根目录名（位于用户主目录下），可由 GX_HOME 覆盖
 	ConfigDir = ".gx"
 
+	// StorageFileName 版本元数据文件名（位于配置目录下）
+	StorageFileName = "versions.json"
+
+	// CurrentLinkName 指向当前激活版本的符号链接名（位于配置目录下）
+	CurrentLinkName = "current"
+
 	// GoDownloadURL Go 官方下载地址
 	GoDownloadURL = "https://go.dev/dl/"
 
 	// GoVersionsAPIURL Go 版本列表 API
-	GoVersionsAPIURL = "https://go.dev/dl/?mode=json"
+	GoVersionsAPIURL = GoDownloadURL + VersionsIndexQuery
+
+	// GoAllVersionsAPIURL 包含所有历史版本的 Go 版本列表 API
+	GoAllVersionsAPIURL = GoDownloadURL + AllVersionsIndexQuery
+
+	// VersionsIndexQuery 下载地址（或镜像）上版本列表的查询参数
+	VersionsIndexQuery = "?mode=json"
+
+	// AllVersionsIndexQuery 下载地址（或镜像）上完整版本列表的查询参数
+	AllVersionsIndexQuery = "?mode=json&include=all"
 
 	// MinGoVersion 最低支持的 Go 版本
 	MinGoVersion = "1.16"
@@ -54,6 +80,12 @@ const (
 
 	// ArchiveExtZip zip 压缩包
 	ArchiveExtZip = ".zip"
+
+	// ChecksumFileSuffix 安装包旁 SHA256 校验和文件的后缀，与 go.dev/dl 发布的格式相同
+	ChecksumFileSuffix = ".sha256"
+
+	// VersionFileInArchive 安装包中记录版本号的文件（去掉顶层 go 目录后）
+	VersionFileInArchive = "VERSION"
 )
 
 // 环境变量名称
@@ -66,4 +98,175 @@ const (
 
 	// EnvPath PATH 环境变量
 	EnvPath = "PATH"
+
+	// EnvGxVersion 覆盖当前 shell 使用的 Go 版本
+	EnvGxVersion = "GX_VERSION"
+
+	// EnvGxHome gx 根目录，默认为 ~/.gx
+	EnvGxHome = "GX_HOME"
+
+	// EnvGxConfig 配置文件路径，默认为 $GX_HOME/config.json
+	EnvGxConfig = "GX_CONFIG"
+
+	// EnvGxSystemConfig 系统级配置文件路径，默认为 /etc/gx/config
+	EnvGxSystemConfig = "GX_SYSTEM_CONFIG"
+
+	// EnvGoRootBootstrap 从源码构建 Go 时使用的引导工具链
+	EnvGoRootBootstrap = "GOROOT_BOOTSTRAP"
+
+	// EnvGoToolchain 控制 go 命令是否自动切换工具链
+	EnvGoToolchain = "GOTOOLCHAIN"
+)
+
+// 源码构建
+const (
+	// GoSourceRepository Go 官方源码仓库
+	GoSourceRepository = "https://go.googlesource.com/go"
+
+	// TipVersion gx install tip 注册的版本名，对应源码仓库的 master 分支
+	TipVersion = "tip"
+
+	// TipRef tip 对应的 git 引用
+	TipRef = "master"
+
+	// SourceVersionPrefix 从 git 引用或本地目录构建的版本默认名称前缀
+	SourceVersionPrefix = "src-"
+
+	// MakeScriptUnix 构建脚本（位于源码树的 src 目录下）
+	MakeScriptUnix = "make.bash"
+
+	// MakeScriptWindows Windows 上的构建脚本
+	MakeScriptWindows = "make.bat"
+
+	// BuildDirSuffix 构建中的版本目录后缀，构建成功后重命名为版本目录
+	BuildDirSuffix = ".build"
+)
+
+// 离线安装包集合（gx bundle）
+const (
+	// BundleFormat 集合清单的格式版本，读取时拒绝更新的格式
+	BundleFormat = 1
+
+	// BundleManifestFile 集合中的清单文件，写在集合的最前面
+	BundleManifestFile = "manifest.json"
+
+	// BundleChecksumFile 集合中 sha256sum 格式的校验和文件，便于手工校验
+	BundleChecksumFile = "SHA256SUMS"
+)
+
+// 安装包缓存
+const (
+	// CacheDirName 默认的缓存目录名（位于 ~/.gx 下，不随 GX_HOME 变化）
+	CacheDirName = "cache"
+
+	// CacheIndexFile 缓存目录中记录各安装包信息的文件
+	CacheIndexFile = "index.json"
+
+	// CacheBlobDir 缓存目录中存放安装包的子目录，文件以 SHA256 命名
+	CacheBlobDir = "sha256"
+
+	// CacheSourceName 从缓存取出安装包时记录的发布源名称
+	CacheSourceName = "cache"
+
+	// DefaultCacheMaxSize 默认的缓存容量
+	DefaultCacheMaxSize = "2G"
+)
+
+// 版本文件
+const (
+	// VersionFileName 项目级版本文件名
+	VersionFileName = ".go-version"
+)
+
+// 分层配置
+const (
+	// ProjectConfigFileName 项目级配置文件名（TOML）
+	ProjectConfigFileName = "gx.toml"
+
+	// ProjectConfigJSONFileName 项目级配置文件名（JSON），与 gx.toml 同时存在时忽略
+	ProjectConfigJSONFileName = ".gx.json"
+
+	// SettingDownloadMirror 下载镜像地址，版本列表和安装包都从这里获取
+	SettingDownloadMirror = "download.mirror"
+
+	// SettingDownloadSources 按优先级排列的发布源，设置后取代 download.mirror
+	SettingDownloadSources = "download.sources"
+
+	// SettingDownloadVerify 安装包校验策略
+	SettingDownloadVerify = "download.verify"
+
+	// SettingInstallRoot 版本安装目录
+	SettingInstallRoot = "install.root"
+
+	// SettingDownloadTimeout 下载单个安装包的超时时间
+	SettingDownloadTimeout = "download.timeout"
+
+	// SettingDownloadIndexTimeout 获取版本列表的超时时间
+	SettingDownloadIndexTimeout = "download.index-timeout"
+
+	// SettingDownloadRetries 获取版本列表或下载安装包遇到可恢复的网络错误时的重试次数
+	SettingDownloadRetries = "download.retries"
+
+	// SettingInstallLockTimeout 等待其他 gx 进程完成同一版本安装的最长时间
+	SettingInstallLockTimeout = "install.lock-timeout"
+
+	// SettingUpdateSwitch gx update 默认是否切换到新版本（--switch）
+	SettingUpdateSwitch = "update.switch"
+
+	// SettingUpgradeRemoveOld gx upgrade 默认是否卸载被替换的版本（--remove-old）
+	SettingUpgradeRemoveOld = "upgrade.remove-old"
+
+	// SettingSourceRepository 从源码构建时克隆的 Go 仓库地址
+	SettingSourceRepository = "source.repository"
+
+	// SettingCacheDir 安装包缓存目录
+	SettingCacheDir = "cache.dir"
+
+	// SettingCacheMaxSize 安装包缓存容量，0 表示禁用缓存
+	SettingCacheMaxSize = "cache.max-size"
+)
+
+// 安装包校验策略（download.verify 的取值）
+const (
+	// VerifyStrict 必须有官方校验和且校验通过
+	VerifyStrict = "strict"
+
+	// VerifyAuto 有校验和时校验，没有时给出警告后继续
+	VerifyAuto = "auto"
+
+	// VerifyOff 不校验
+	VerifyOff = "off"
+)
+
+// 网络超时（默认值，可由配置项覆盖）
+const (
+	// DownloadTimeout 下载单个安装包的超时时间
+	DownloadTimeout = 30 * time.Minute
+
+	// IndexTimeout 获取版本列表的超时时间
+	IndexTimeout = 30 * time.Second
+)
+
+// 网络错误重试
+const (
+	// DownloadRetries 遇到可恢复的网络错误时的重试次数（默认值，可由 download.retries 覆盖）
+	DownloadRetries = 3
+
+	// RetryBaseDelay 第一次重试前的等待时间，之后每次翻倍
+	RetryBaseDelay = 1 * time.Second
+
+	// RetryMaxDelay 两次重试之间的最长等待时间，服务器要求（Retry-After）更久时不再重试
+	RetryMaxDelay = 30 * time.Second
+)
+
+// 锁等待时间
+const (
+	// ConfigLockTimeout 等待其他 gx 进程释放配置文件锁的最长时间
+	ConfigLockTimeout = 30 * time.Second
+
+	// InstallLockTimeout 等待其他 gx 进程完成同一版本安装的最长时间（默认值，可由 install.lock-timeout 覆盖）
+	InstallLockTimeout = 30 * time.Minute
+
+	// LockFileSuffix 锁文件后缀，锁文件与被保护的文件或目录同名
+	LockFileSuffix = ".lock"
 )
@@ -81,6 +81,15 @@ var (
 
 	// ErrPartialFailure 部分操作失败
 	ErrPartialFailure = NewError("PARTIAL_FAILURE", "operation partially failed")
+
+	// ErrConfigSchemaUnsupported 配置文件由更新版本的 gx 写入
+	ErrConfigSchemaUnsupported = NewError("CONFIG_SCHEMA_UNSUPPORTED", "configuration was written by a newer version of gx")
+
+	// ErrInvalidSetting 配置项不存在或值无效
+	ErrInvalidSetting = NewError("INVALID_SETTING", "invalid setting")
+
+	// ErrLocked 资源被另一个 gx 进程锁定
+	ErrLocked = NewError("LOCKED", "resource is locked by another gx process")
 )
 
 // Error 自定义错误类型
@@ -0,0 +1,34 @@
+package goversion
+
+import "regexp"
+
+// filenamePattern 官方发布文件的文件名
+// 例如 go1.22.3.linux-amd64.tar.gz、go1.22.3.windows-amd64.zip、go1.22.3.src.tar.gz
+var filenamePattern = regexp.MustCompile(`^(go\d+\.\d+(?:\.\d+)?(?:(?:beta|rc)\d+)?)\.(?:([a-z0-9]+)-([a-z0-9]+)|src)(\.tar\.gz|\.zip)$`)
+
+// Filename 官方发布文件名中的信息
+type Filename struct {
+	Version string // 例如: "go1.22.3"
+	OS      string // 源码包为空
+	Arch    string // 源码包为空
+	Ext     string // ".tar.gz" 或 ".zip"
+}
+
+// IsSource 是否为源码包
+func (f Filename) IsSource() bool {
+	return f.OS == "" && f.Arch == ""
+}
+
+// ParseFilename 解析官方发布文件的文件名，不符合官方命名时返回 false
+func ParseFilename(name string) (Filename, bool) {
+	matches := filenamePattern.FindStringSubmatch(name)
+	if matches == nil {
+		return Filename{}, false
+	}
+	return Filename{
+		Version: matches[1],
+		OS:      matches[2],
+		Arch:    matches[3],
+		Ext:     matches[4],
+	}, true
+}
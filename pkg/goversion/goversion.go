@@ -0,0 +1,237 @@
+// Package goversion 提供 Go 发行版本号的解析、规范化和排序
+//
+// 支持的格式（"go" 前缀可选）：
+//
+//	1.21.5   go1.21.5   1.21   go1.21rc2   go1.9beta1
+//
+// 排序规则与 Go 发行顺序一致：go1.21rc1 < go1.21rc2 < go1.21.0 < go1.21.1。
+// 不带补丁号的版本（如 go1.20）视为该次要版本的首个正式版本，即等同于 go1.20.0。
+package goversion
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+// Prefix Go 版本号前缀
+const Prefix = "go"
+
+// 预发布类型
+const (
+	PrereleaseBeta = "beta"
+	PrereleaseRC   = "rc"
+)
+
+var versionPattern = regexp.MustCompile(`^(?:go)?(\d+)\.(\d+)(?:\.(\d+))?(?:(beta|rc)(\d+))?$`)
+
+// Version 表示一个 Go 发行版本
+type Version struct {
+	Major         int
+	Minor         int
+	Patch         int
+	HasPatch      bool   // 版本号中是否显式包含补丁号
+	Prerelease    string // "beta"、"rc" 或空（正式版本）
+	PrereleaseNum int
+}
+
+// Parse 解析版本号
+func Parse(s string) (Version, error) {
+	matches := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return Version{}, errors.ErrInvalidVersion.WithMessage(s)
+	}
+
+	v := Version{
+		Major: atoi(matches[1]),
+		Minor: atoi(matches[2]),
+	}
+
+	if matches[3] != "" {
+		v.Patch = atoi(matches[3])
+		v.HasPatch = true
+	}
+
+	if matches[4] != "" {
+		// 预发布版本没有补丁号，例如 go1.21rc2
+		if v.HasPatch {
+			return Version{}, errors.ErrInvalidVersion.WithMessage(s)
+		}
+		v.Prerelease = matches[4]
+		v.PrereleaseNum = atoi(matches[5])
+	}
+
+	return v, nil
+}
+
+// ParseVersionOutput 解析 "go version" 命令的输出
+// 例如: "go version go1.21.5 linux/amd64" 或 "go version go1.23rc1 darwin/arm64"
+func ParseVersionOutput(output string) (Version, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "go" || fields[1] != "version" {
+		return Version{}, errors.ErrInvalidVersion.WithMessage("failed to parse go version output")
+	}
+
+	v, err := Parse(fields[2])
+	if err != nil {
+		return Version{}, errors.ErrInvalidVersion.WithMessage("failed to parse go version output: " + fields[2])
+	}
+	return v, nil
+}
+
+// MustParse 解析版本号，失败时 panic
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IsValid 检查字符串是否为有效的版本号
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// String 返回带 "go" 前缀的规范版本号
+func (v Version) String() string {
+	return Prefix + v.Short()
+}
+
+// Short 返回不带 "go" 前缀的版本号
+func (v Version) Short() string {
+	s := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if v.HasPatch {
+		s += fmt.Sprintf(".%d", v.Patch)
+	}
+	if v.Prerelease != "" {
+		s += fmt.Sprintf("%s%d", v.Prerelease, v.PrereleaseNum)
+	}
+	return s
+}
+
+// Line 返回版本所属的次要版本线，例如 go1.21.5 -> go1.21
+func (v Version) Line() string {
+	return fmt.Sprintf("%s%d.%d", Prefix, v.Major, v.Minor)
+}
+
+// IsPrerelease 是否为预发布版本（beta/rc）
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare 比较两个版本
+// 返回: -1 (v < o), 0 (v == o), 1 (v > o)
+func (v Version) Compare(o Version) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+
+	// 预发布版本排在同一次要版本的所有正式版本之前
+	if v.IsPrerelease() != o.IsPrerelease() {
+		if v.IsPrerelease() {
+			return -1
+		}
+		return 1
+	}
+
+	if v.IsPrerelease() {
+		if c := compareInt(prereleaseRank(v.Prerelease), prereleaseRank(o.Prerelease)); c != 0 {
+			return c
+		}
+		return compareInt(v.PrereleaseNum, o.PrereleaseNum)
+	}
+
+	return compareInt(v.Patch, o.Patch)
+}
+
+// Less 判断 v 是否早于 o
+func (v Version) Less(o Version) bool {
+	return v.Compare(o) < 0
+}
+
+// Compare 比较两个版本字符串
+// 无法解析的版本排在所有有效版本之前，彼此之间按字符串排序
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	default:
+		return va.Compare(vb)
+	}
+}
+
+// Sort 按版本从旧到新原地排序
+func Sort(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(versions[i], versions[j]) < 0
+	})
+}
+
+// sortVersions 按版本从旧到新原地排序
+func sortVersions(versions []Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Less(versions[j])
+	})
+}
+
+// Normalize 返回带 "go" 前缀的版本号
+// 可解析的版本会被规范化（去除空白等），否则仅补充前缀
+func Normalize(s string) string {
+	if v, err := Parse(s); err == nil {
+		return v.String()
+	}
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, Prefix) {
+		return s
+	}
+	return Prefix + s
+}
+
+// Display 返回用于展示的版本号（不带 "go" 前缀）
+func Display(s string) string {
+	return strings.TrimPrefix(s, Prefix)
+}
+
+// prereleaseRank 预发布类型的先后顺序
+func prereleaseRank(kind string) int {
+	switch kind {
+	case PrereleaseBeta:
+		return 0
+	case PrereleaseRC:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
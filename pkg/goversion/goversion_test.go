@@ -0,0 +1,132 @@
+package goversion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"1.21.5", "go1.21.5", true},
+		{"go1.21.5", "go1.21.5", true},
+		{"1.21", "go1.21", true},
+		{"go1.21rc2", "go1.21rc2", true},
+		{"go1.9beta1", "go1.9beta1", true},
+		{" go1.22.0\n", "go1.22.0", true},
+		{"go1.21.0rc1", "", false},
+		{"1", "", false},
+		{"latest", "", false},
+		{"go1.21.x", "", false},
+	}
+
+	for _, tt := range tests {
+		v, err := Parse(tt.input)
+		if (err == nil) != tt.ok {
+			t.Errorf("Parse(%q) error = %v, want ok=%v", tt.input, err, tt.ok)
+			continue
+		}
+		if tt.ok && v.String() != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, v, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"go1.21rc2", "go1.21.0", -1},
+		{"go1.21.0", "go1.21.1", -1},
+		{"go1.21rc1", "go1.21rc2", -1},
+		{"go1.21beta1", "go1.21rc1", -1},
+		{"go1.9", "go1.21", -1},
+		{"go1.20", "go1.20.0", 0},
+		{"1.21.5", "go1.21.5", 0},
+		{"go1.22.0", "go1.21.10", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"go1.21.1", "go1.9", "go1.21rc2", "go1.21.0", "go1.10.3", "go1.21rc1"}
+	Sort(versions)
+
+	want := []string{"go1.9", "go1.10.3", "go1.21rc1", "go1.21rc2", "go1.21.0", "go1.21.1"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("Sort() = %v, want %v", versions, want)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("1.21.5"); got != "go1.21.5" {
+		t.Errorf("Normalize(1.21.5) = %s", got)
+	}
+	if got := Normalize("go1.21rc1"); got != "go1.21rc1" {
+		t.Errorf("Normalize(go1.21rc1) = %s", got)
+	}
+	if got := Display("go1.21.5"); got != "1.21.5" {
+		t.Errorf("Display(go1.21.5) = %s", got)
+	}
+	if got := MustParse("go1.21.5").Line(); got != "go1.21" {
+		t.Errorf("Line() = %s", got)
+	}
+}
+
+func TestParseVersionOutput(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+		ok     bool
+	}{
+		{"go version go1.21.5 linux/amd64\n", "go1.21.5", true},
+		{"go version go1.23rc1 darwin/arm64", "go1.23rc1", true},
+		{"go version devel go1.24-abcdef linux/amd64", "", false},
+		{"command not found", "", false},
+	}
+
+	for _, tt := range tests {
+		v, err := ParseVersionOutput(tt.output)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseVersionOutput(%q) error = %v, want ok=%v", tt.output, err, tt.ok)
+			continue
+		}
+		if tt.ok && v.String() != tt.want {
+			t.Errorf("ParseVersionOutput(%q) = %s, want %s", tt.output, v, tt.want)
+		}
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want Filename
+		ok   bool
+	}{
+		{"go1.22.3.linux-amd64.tar.gz", Filename{Version: "go1.22.3", OS: "linux", Arch: "amd64", Ext: ".tar.gz"}, true},
+		{"go1.21rc2.windows-arm64.zip", Filename{Version: "go1.21rc2", OS: "windows", Arch: "arm64", Ext: ".zip"}, true},
+		{"go1.22.3.src.tar.gz", Filename{Version: "go1.22.3", Ext: ".tar.gz"}, true},
+		{"go1.22.3.darwin-amd64.pkg", Filename{}, false},
+		{"go1.22.3.linux-amd64.tar.gz.sha256", Filename{}, false},
+		{"go.tar.gz", Filename{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseFilename(tt.name)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseFilename(%q) = %+v, %v, want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+	if f, _ := ParseFilename("go1.22.3.src.tar.gz"); !f.IsSource() {
+		t.Errorf("IsSource() = false for a source archive")
+	}
+}
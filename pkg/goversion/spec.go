@@ -0,0 +1,189 @@
+package goversion
+
+import (
+	"strings"
+
+	"github.com/kawaiirei0/gx/pkg/errors"
+)
+
+// 版本关键字
+const (
+	// KeywordLatest 最新的正式版本（与 stable 相同）
+	KeywordLatest = "latest"
+
+	// KeywordStable 最新的正式版本
+	KeywordStable = "stable"
+
+	// KeywordOldstable 上一个次要版本线的最新正式版本
+	KeywordOldstable = "oldstable"
+)
+
+// SpecKind 版本说明符的类型
+type SpecKind int
+
+const (
+	// SpecExact 精确版本，例如 1.21.5、go1.23rc1
+	SpecExact SpecKind = iota
+
+	// SpecLine 次要版本线，例如 1.22、1.22.x，匹配该线的最新补丁版本
+	SpecLine
+
+	// SpecStable 最新正式版本（latest/stable）
+	SpecStable
+
+	// SpecOldstable 上一个次要版本线的最新正式版本
+	SpecOldstable
+)
+
+// Spec 表示用户输入的版本说明符
+type Spec struct {
+	Kind    SpecKind
+	Version Version // 仅对 SpecExact 和 SpecLine 有效
+	keyword string
+}
+
+// ParseSpec 解析版本说明符
+//
+// 支持: 1.21.5、go1.21.5、1.23rc1（精确），1.22、1.22.x（版本线），latest、stable、oldstable（关键字）
+func ParseSpec(s string) (Spec, error) {
+	s = strings.TrimSpace(s)
+
+	switch strings.ToLower(s) {
+	case KeywordLatest, KeywordStable:
+		return Spec{Kind: SpecStable, keyword: strings.ToLower(s)}, nil
+	case KeywordOldstable:
+		return Spec{Kind: SpecOldstable, keyword: KeywordOldstable}, nil
+	}
+
+	if line := strings.TrimSuffix(s, ".x"); line != s {
+		v, err := Parse(line)
+		if err != nil || v.HasPatch || v.IsPrerelease() {
+			return Spec{}, errors.ErrInvalidVersion.WithMessage(s)
+		}
+		return Spec{Kind: SpecLine, Version: v}, nil
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return Spec{}, err
+	}
+
+	// 不带补丁号的正式版本视为版本线
+	if !v.HasPatch && !v.IsPrerelease() {
+		return Spec{Kind: SpecLine, Version: v}, nil
+	}
+
+	return Spec{Kind: SpecExact, Version: v}, nil
+}
+
+// MustParseSpec 解析版本说明符，失败时 panic
+func MustParseSpec(s string) Spec {
+	spec, err := ParseSpec(s)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// IsSpec 检查字符串是否为有效的版本说明符
+func IsSpec(s string) bool {
+	_, err := ParseSpec(s)
+	return err == nil
+}
+
+// IsKeyword 检查字符串是否为版本关键字
+func IsKeyword(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case KeywordLatest, KeywordStable, KeywordOldstable:
+		return true
+	}
+	return false
+}
+
+// String 返回说明符的规范形式，例如 go1.21.5、go1.22.x、latest
+func (s Spec) String() string {
+	switch s.Kind {
+	case SpecExact:
+		return s.Version.String()
+	case SpecLine:
+		return s.Version.Line() + ".x"
+	default:
+		return s.keyword
+	}
+}
+
+// IsExact 是否为精确版本
+func (s Spec) IsExact() bool {
+	return s.Kind == SpecExact
+}
+
+// Select 从候选版本中选出与说明符匹配的最新版本
+// 候选版本中无法解析的条目会被忽略
+func (s Spec) Select(candidates []string) (string, bool) {
+	var parsed []Version
+	byVersion := make(map[Version]string)
+	for _, c := range candidates {
+		v, err := Parse(c)
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, v)
+		byVersion[v] = c
+	}
+
+	var line string
+	switch s.Kind {
+	case SpecStable:
+		line = stableLine(parsed, 0)
+	case SpecOldstable:
+		line = stableLine(parsed, 1)
+	}
+
+	var best *Version
+	for i := range parsed {
+		v := parsed[i]
+		if !s.matches(v, line) {
+			continue
+		}
+		if best == nil || best.Less(v) {
+			best = &parsed[i]
+		}
+	}
+
+	if best == nil {
+		return "", false
+	}
+	return byVersion[*best], true
+}
+
+// matches 判断版本是否满足说明符；line 为关键字已确定的版本线
+func (s Spec) matches(v Version, line string) bool {
+	switch s.Kind {
+	case SpecExact:
+		return v.Compare(s.Version) == 0
+	case SpecLine:
+		return !v.IsPrerelease() && v.Line() == s.Version.Line()
+	default:
+		return line != "" && !v.IsPrerelease() && v.Line() == line
+	}
+}
+
+// stableLine 返回候选中第 n 新（从 0 开始）的正式版本线
+func stableLine(versions []Version, n int) string {
+	var lines []Version
+	seen := make(map[string]bool)
+	for _, v := range versions {
+		if v.IsPrerelease() || seen[v.Line()] {
+			continue
+		}
+		seen[v.Line()] = true
+		lines = append(lines, Version{Major: v.Major, Minor: v.Minor})
+	}
+
+	if n >= len(lines) {
+		return ""
+	}
+
+	sortVersions(lines)
+	return lines[len(lines)-1-n].Line()
+}
@@ -0,0 +1,66 @@
+package goversion
+
+import "testing"
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		input string
+		kind  SpecKind
+		want  string
+		ok    bool
+	}{
+		{"1.21.5", SpecExact, "go1.21.5", true},
+		{"go1.23rc1", SpecExact, "go1.23rc1", true},
+		{"1.22", SpecLine, "go1.22.x", true},
+		{"go1.22.x", SpecLine, "go1.22.x", true},
+		{"latest", SpecStable, "latest", true},
+		{"Stable", SpecStable, "stable", true},
+		{"oldstable", SpecOldstable, "oldstable", true},
+		{"1.22.5.x", 0, "", false},
+		{"1.x", 0, "", false},
+		{"prod", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParseSpec(tt.input)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseSpec(%q) error = %v, want ok=%v", tt.input, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		if spec.Kind != tt.kind || spec.String() != tt.want {
+			t.Errorf("ParseSpec(%q) = (%d, %s), want (%d, %s)", tt.input, spec.Kind, spec, tt.kind, tt.want)
+		}
+	}
+}
+
+func TestSpecSelect(t *testing.T) {
+	candidates := []string{
+		"go1.23rc2", "go1.22.5", "go1.22.10", "go1.22rc1", "go1.21.13", "go1.21.0", "go1.20", "bogus",
+	}
+
+	tests := []struct {
+		spec string
+		want string
+		ok   bool
+	}{
+		{"1.22", "go1.22.10", true},
+		{"1.21.x", "go1.21.13", true},
+		{"1.20", "go1.20", true},
+		{"1.20.0", "go1.20", true},
+		{"1.23rc2", "go1.23rc2", true},
+		{"latest", "go1.22.10", true},
+		{"oldstable", "go1.21.13", true},
+		{"1.23", "", false},
+		{"1.19.1", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := MustParseSpec(tt.spec).Select(candidates)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Select(%q) = (%q, %v), want (%q, %v)", tt.spec, got, ok, tt.want, tt.ok)
+		}
+	}
+}
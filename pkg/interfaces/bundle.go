@@ -0,0 +1,57 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// Bundler 创建和安装离线安装包集合
+// 集合是一个 tar 文件，包含清单、多个版本和平台的官方安装包及其校验和，用于无法访问网络的机器
+type Bundler interface {
+	// Create 下载指定版本和平台的安装包并打包为集合
+	Create(ctx context.Context, opts BundleOptions) (*BundleManifest, error)
+
+	// Inspect 读取集合的清单
+	Inspect(bundlePath string) (*BundleManifest, error)
+
+	// Install 校验集合中的所有安装包，并安装与当前平台匹配的版本，不访问网络
+	Install(ctx context.Context, bundlePath string) (*BundleInstallResult, error)
+}
+
+// BundleOptions 创建集合的选项
+type BundleOptions struct {
+	Versions   []string               // 版本说明符，按远程版本列表解析为具体版本
+	Platforms  []PlatformInfo         // 目标平台，为空时使用当前平台
+	OutputPath string                 // 集合文件路径
+	Progress   BundleProgressCallback // 下载进度
+}
+
+// BundleProgressCallback 创建集合时每个安装包的下载进度
+type BundleProgressCallback func(entry BundleEntry, downloaded int64, total int64)
+
+// BundleManifest 集合的清单
+type BundleManifest struct {
+	Format  int           `json:"format"`  // 清单格式版本
+	Created time.Time     `json:"created"` // 创建时间
+	Entries []BundleEntry `json:"entries"` // 集合中的安装包
+}
+
+// BundleEntry 集合中的一个安装包
+type BundleEntry struct {
+	Version   string `json:"version"`    // 例如: "go1.22.3"
+	OS        string `json:"os"`         // 目标操作系统
+	Arch      string `json:"arch"`       // 目标架构
+	Filename  string `json:"filename"`   // 集合中的文件名
+	SHA256    string `json:"sha256"`     // 安装包的 SHA256
+	Size      int64  `json:"size"`       // 安装包大小（字节）
+	SourceURL string `json:"source_url"` // 创建集合时的下载地址
+	Source    string `json:"source"`     // 创建集合时提供安装包的发布源
+}
+
+// BundleInstallResult 安装集合的结果
+type BundleInstallResult struct {
+	Installed []string          `json:"installed"` // 新安装的版本
+	Skipped   []string          `json:"skipped"`   // 已安装而跳过的版本
+	Failed    map[string]string `json:"failed"`    // 安装失败的版本及原因
+	Foreign   []BundleEntry     `json:"foreign"`   // 其他平台的安装包（已校验，未安装）
+}
@@ -0,0 +1,48 @@
+package interfaces
+
+import "time"
+
+// ArchiveCache 按 SHA256 寻址的安装包缓存
+// 缓存目录默认不随 GX_HOME 变化，多个根目录共享同一份安装包；超出容量时淘汰最久未使用的安装包
+type ArchiveCache interface {
+	// Fetch 将校验和为 sha256 的安装包复制到 destPath，复制时重新校验
+	// 未命中时返回 nil；缓存中的文件已损坏时将其删除并视为未命中
+	Fetch(sha256 string, destPath string) (*CacheEntry, error)
+
+	// Store 将已校验的安装包加入缓存，entry.SHA256 必须是文件的实际校验和
+	Store(path string, entry CacheEntry) error
+
+	// List 返回缓存中的安装包，最近使用的在前
+	List() ([]CacheEntry, error)
+
+	// Verify 重新计算所有安装包的校验和，删除已损坏的安装包
+	Verify() (*CacheVerifyResult, error)
+
+	// Clean 删除指定版本的安装包，versions 为空时清空缓存
+	Clean(versions []string) ([]CacheEntry, error)
+
+	// Dir 返回缓存目录
+	Dir() string
+
+	// MaxSize 返回缓存容量（字节），0 表示禁用缓存
+	MaxSize() int64
+}
+
+// CacheEntry 缓存中的一个安装包
+type CacheEntry struct {
+	SHA256   string    `json:"sha256"`    // 安装包的 SHA256，也是缓存中的文件名
+	Filename string    `json:"filename"`  // 官方文件名，例如 "go1.22.3.linux-amd64.tar.gz"
+	Version  string    `json:"version"`   // 例如: "go1.22.3"
+	Size     int64     `json:"size"`      // 文件大小（字节）
+	Source   string    `json:"source"`    // 提供安装包的发布源
+	URL      string    `json:"url"`       // 下载地址
+	Added    time.Time `json:"added"`     // 加入缓存的时间
+	LastUsed time.Time `json:"last_used"` // 最近一次加入或取出的时间
+}
+
+// CacheVerifyResult 校验缓存的结果
+type CacheVerifyResult struct {
+	Valid     []CacheEntry `json:"valid"`     // 校验通过的安装包
+	Corrupted []CacheEntry `json:"corrupted"` // 已损坏并被删除的安装包
+	Missing   []CacheEntry `json:"missing"`   // 记录存在但文件已丢失的安装包
+}
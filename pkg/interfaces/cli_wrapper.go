@@ -1,5 +1,7 @@
 package interfaces
 
+import "io"
+
 // CLIWrapper 包装和转发 Go 原生命令
 type CLIWrapper interface {
 	// Execute 执行 Go 命令
@@ -9,4 +11,8 @@ type CLIWrapper interface {
 
 	// GetGoExecutable 获取当前使用的 Go 可执行文件路径
 	GetGoExecutable() (string, error)
+
+	// ExecuteWithVersion 使用指定的已安装版本执行 Go 命令
+	// 子进程的 GOROOT、PATH 和 GX_VERSION 指向该版本，输出写入 stdout/stderr
+	ExecuteWithVersion(version *GoVersion, command string, args []string, stdout, stderr io.Writer) error
 }
@@ -4,20 +4,36 @@ import "time"
 
 // ConfigStore 配置存储接口
 type ConfigStore interface {
-	// Load 加载配置
+	// Load 加载配置，不解析系统和项目配置
 	Load() (*Config, error)
 
 	// Save 保存配置
 	Save(config *Config) error
 
+	// Update 在跨进程锁内加载配置、调用 fn 修改并保存
+	// 并发运行的 gx 进程不会互相覆盖修改；fn 返回错误时不保存
+	Update(fn func(config *Config) error) error
+
 	// EnsureConfigDir 确保配置目录存在
 	EnsureConfigDir() error
+
+	// Settings 返回 dir 所在项目的分层合并后的生效配置，dir 为空时使用当前工作目录
+	// 系统或项目配置无法读取、解析或包含无效值时跳过并记录警告（见 Settings.Warnings），
+	// 只有用户配置本身有问题时返回错误
+	Settings(dir string) (*Settings, error)
+
+	// UpdateSettings 在跨进程锁内修改用户层的配置项（config.json 中的 settings）并保存
+	// 不解析其他配置层，因此即使现有配置项无效也可以修改；fn 返回错误时不保存
+	UpdateSettings(fn func(settings map[string]string) error) error
 }
 
 // Config 应用配置
 type Config struct {
-	ActiveVersion   string            `json:"active_version"`    // 当前激活版本
-	InstallPath     string            `json:"install_path"`      // 安装根目录
-	Versions        map[string]string `json:"versions"`          // 版本到路径的映射
-	LastUpdateCheck time.Time         `json:"last_update_check"` // 上次检查更新时间
+	SchemaVersion   int               `json:"schema_version"`     // 配置 schema 版本，由 ConfigStore 维护
+	ActiveVersion   string            `json:"active_version"`     // 当前激活版本
+	Versions        map[string]string `json:"versions"`           // 版本到路径的映射
+	Aliases         map[string]string `json:"aliases,omitempty"`  // 别名到版本说明符的映射
+	Linked          map[string]bool   `json:"linked,omitempty"`   // 通过 gx adopt 注册的外部版本，gx 不拥有其文件
+	LastUpdateCheck time.Time         `json:"last_update_check"`  // 上次检查更新时间
+	Settings        map[string]string `json:"settings,omitempty"` // 用户层的配置项，见 Settings
 }
@@ -1,12 +1,58 @@
 package interfaces
 
+import (
+	"context"
+	"io"
+)
+
 // Downloader 负责下载 Go 安装包
+// 版本列表和安装包按优先级依次从各个发布源获取；请求在 ctx 取消时中止，下载中止时返回 ErrCancelled
 type Downloader interface {
 	// Download 下载指定版本的 Go 安装包
-	Download(version string, destPath string, progress ProgressCallback) error
+	Download(ctx context.Context, version string, destPath string, progress ProgressCallback) (*DownloadResult, error)
+
+	// DownloadFor 下载指定版本和平台的 Go 安装包，用于为其他机器准备安装包
+	DownloadFor(ctx context.Context, version string, os string, arch string, destPath string, progress ProgressCallback) (*DownloadResult, error)
+
+	// DownloadSource 下载指定版本的 Go 源码包
+	DownloadSource(ctx context.Context, version string, destPath string, progress ProgressCallback) (*DownloadResult, error)
+
+	// GetDownloadURL 获取下载 URL，os 和 arch 为空时返回源码包地址
+	GetDownloadURL(ctx context.Context, version string, os string, arch string) (string, error)
+
+	// Versions 获取版本列表，来自第一个可用的发布源
+	// all 为 false 时只需包含当前支持的版本线，为 true 时包含所有历史版本
+	Versions(ctx context.Context, all bool) ([]RemoteVersion, error)
+}
+
+// DownloadResult 一次下载的结果
+type DownloadResult struct {
+	Source string // 提供文件的发布源名称
+	URL    string // 文件的地址
+	SHA256 string // 文件的 SHA256
+}
+
+// ReleaseSource 提供 Go 版本列表和发布文件的来源，例如 HTTP 镜像、本地目录或本地版本列表
+type ReleaseSource interface {
+	// Name 返回发布源的名称，记录在安装元数据中
+	Name() string
+
+	// Versions 获取版本列表，all 的含义与 Downloader.Versions 相同；不区分的发布源总是返回完整列表
+	Versions(ctx context.Context, all bool) ([]RemoteVersion, error)
+
+	// URL 返回文件的地址
+	URL(file File) string
+
+	// Open 从 offset 处开始读取文件，offset 大于 0 时用于续传中断的下载
+	// 发布源无法从 offset 处读取时从头返回文件，调用方按 FileStream.Offset 判断
+	Open(ctx context.Context, file File, offset int64) (*FileStream, error)
+}
 
-	// GetDownloadURL 获取下载 URL
-	GetDownloadURL(version string, os string, arch string) (string, error)
+// FileStream 发布源返回的文件内容
+type FileStream struct {
+	Body   io.ReadCloser
+	Offset int64 // Body 在文件中的起始位置
+	Size   int64 // 文件总大小，未知时为 -1
 }
 
 // RemoteVersion 表示远程可用的 Go 版本信息
@@ -22,4 +22,11 @@ type EnvironmentManager interface {
 
 	// Restore 恢复环境变量配置
 	Restore() error
+
+	// SwitchCurrent 将 current 指针原子地切换到指定 GOROOT
+	// shell 配置文件只需引用一次 current 目录，之后的切换无需重新加载
+	SwitchCurrent(goRoot string) error
+
+	// SessionEnv 计算指向指定 GOROOT 的会话级环境变量（GOROOT、PATH），不做持久化修改
+	SessionEnv(goRoot string) (map[string]string, error)
 }
@@ -1,9 +1,17 @@
 package interfaces
 
+import "context"
+
 // Installer 负责安装和卸载 Go 版本
 type Installer interface {
-	// Install 安装指定版本到目标路径
-	Install(archivePath string, version string, destPath string) error
+	// Install 安装指定版本到目标路径，ctx 取消时删除已解压的文件并返回 ErrCancelled
+	Install(ctx context.Context, archivePath string, version string, destPath string) error
+
+	// Extract 将安装包或源码包解压到目标路径，去掉顶层的 go 目录，不做验证
+	Extract(ctx context.Context, archivePath string, destPath string) error
+
+	// ArchiveVersion 读取安装包中 VERSION 文件记录的版本号
+	ArchiveVersion(archivePath string) (string, error)
 
 	// Uninstall 卸载指定版本
 	Uninstall(version string, installPath string) error
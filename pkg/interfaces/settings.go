@@ -0,0 +1,90 @@
+package interfaces
+
+import "sort"
+
+// SettingScope 配置项所在的层级
+type SettingScope string
+
+const (
+	// ScopeDefault 内置默认值
+	ScopeDefault SettingScope = "default"
+
+	// ScopeSystem 系统级配置（/etc/gx/config），通常由管理员统一下发
+	ScopeSystem SettingScope = "system"
+
+	// ScopeUser 用户配置（$GX_HOME/config.json 中的 settings）
+	ScopeUser SettingScope = "user"
+
+	// ScopeProject 项目配置（gx.toml 或 .gx.json）
+	ScopeProject SettingScope = "project"
+)
+
+// Setting 一个生效的配置项
+type Setting struct {
+	Key    string       // 配置项名称，例如 download.mirror
+	Value  string       // 生效值
+	Scope  SettingScope // 生效值所在的层级
+	Origin string       // 生效值所在的文件，默认值为空
+}
+
+// Settings 按层级合并后的生效配置
+// 优先级从低到高：默认值 < 系统 < 用户 < 项目
+type Settings struct {
+	entries  map[string]Setting
+	warnings []string
+}
+
+// NewSettings 创建空的配置集合
+func NewSettings() *Settings {
+	return &Settings{entries: make(map[string]Setting)}
+}
+
+// Set 设置配置项，覆盖已有的同名配置项
+// 按优先级从低到高依次设置各层级即可得到合并结果
+func (s *Settings) Set(setting Setting) {
+	s.entries[setting.Key] = setting
+}
+
+// Lookup 查找配置项
+func (s *Settings) Lookup(key string) (Setting, bool) {
+	if s == nil {
+		return Setting{}, false
+	}
+	setting, ok := s.entries[key]
+	return setting, ok
+}
+
+// Get 返回配置项的生效值，不存在时返回空字符串
+func (s *Settings) Get(key string) string {
+	setting, _ := s.Lookup(key)
+	return setting.Value
+}
+
+// List 返回按名称排序的所有配置项
+func (s *Settings) List() []Setting {
+	if s == nil {
+		return nil
+	}
+
+	list := make([]Setting, 0, len(s.entries))
+	for _, setting := range s.entries {
+		list = append(list, setting)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// Warn 记录合并时被忽略的配置层或配置项，由命令展示给用户
+func (s *Settings) Warn(message string) {
+	s.warnings = append(s.warnings, message)
+}
+
+// Warnings 返回合并时记录的警告
+func (s *Settings) Warnings() []string {
+	if s == nil {
+		return nil
+	}
+	return s.warnings
+}
@@ -1,6 +1,10 @@
 package interfaces
 
-import "time"
+import (
+	"context"
+	"io"
+	"time"
+)
 
 // VersionManager 管理 Go 版本的安装、切换和检测
 type VersionManager interface {
@@ -11,19 +15,69 @@ type VersionManager interface {
 	GetActive() (*GoVersion, error)
 
 	// Install 安装指定版本
-	Install(version string, progress ProgressCallback) error
+	// ctx 取消时中止下载或解压，回滚已做的改动并返回 ErrCancelled，InstallArchive 和 InstallFromSource 同样如此
+	Install(ctx context.Context, version string, progress ProgressCallback) error
+
+	// InstallArchive 从本地安装包离线安装，不经过下载器，返回安装的版本号
+	InstallArchive(ctx context.Context, archivePath string, opts ArchiveOptions) (string, error)
+
+	// InstallFromSource 从源码构建并安装 Go，返回注册的版本名
+	// source 可以是版本说明符（下载官方源码包）、git 引用、本地源码目录或 tip
+	InstallFromSource(ctx context.Context, source string, opts SourceOptions) (string, error)
 
 	// SwitchTo 切换到指定版本
 	SwitchTo(version string) error
 
 	// ListAvailable 获取可用的远程版本列表
-	ListAvailable() ([]string, error)
+	ListAvailable(ctx context.Context) ([]string, error)
 
 	// GetLatest 获取最新稳定版本
-	GetLatest() (string, error)
+	GetLatest(ctx context.Context) (string, error)
 
 	// Uninstall 卸载指定版本
 	Uninstall(version string) error
+
+	// Resolve 解析指定目录下应使用的版本
+	// 解析顺序: GX_VERSION 环境变量 > 最近的 .go-version 文件 > 全局默认版本
+	// dir 为空时使用当前工作目录
+	Resolve(dir string) (*ResolvedVersion, error)
+
+	// SetLocal 在指定目录写入 .go-version 文件
+	SetLocal(dir string, version string) error
+
+	// FindInstalled 将版本说明符（1.22、1.21.x、latest、别名等）解析为已安装的具体版本
+	FindInstalled(spec string) (string, error)
+
+	// FindRemote 将版本说明符解析为远程可下载的具体版本
+	FindRemote(ctx context.Context, spec string) (string, error)
+
+	// SetAlias 设置版本别名
+	SetAlias(name string, spec string) error
+
+	// RemoveAlias 删除版本别名
+	RemoveAlias(name string) error
+
+	// ListAliases 列出所有版本别名
+	ListAliases() (map[string]string, error)
+
+	// Adopt 将外部安装的 Go 工具链注册为链接版本，返回其版本号
+	Adopt(path string) (string, error)
+
+	// ScanToolchains 在常见位置查找外部安装的 Go 工具链
+	ScanToolchains() ([]string, error)
+
+	// RecordUsage 记录版本被使用的时间，供清理策略参考
+	RecordUsage(version string)
+
+	// Prune 按保留策略删除旧版本；dryRun 为 true 时只计算结果不删除
+	Prune(policy PrunePolicy, dryRun bool) (*PruneResult, error)
+
+	// PlanUpgrade 为每个已安装的次版本线查找远程最新的补丁版本
+	PlanUpgrade(ctx context.Context) ([]UpgradePlan, error)
+
+	// Repoint 将指向旧版本的激活版本、别名和 pins 中的 .go-version 文件改为指向新版本
+	// pins 中的目录会被递归查找，文件则只改写该文件
+	Repoint(from []string, to string, pins []string) (*RepointResult, error)
 }
 
 // GoVersion 表示一个 Go 版本的信息
@@ -32,6 +86,117 @@ type GoVersion struct {
 	Path        string    `json:"path"`         // 安装路径
 	IsActive    bool      `json:"is_active"`    // 是否为当前激活版本
 	InstallDate time.Time `json:"install_date"` // 安装日期
+	Linked      bool      `json:"linked"`       // 是否为链接的外部安装
+
+	// 以下元数据由 Storage 持久化，旧版本安装的条目可能为空
+	Origin          VersionOrigin `json:"origin,omitempty"`           // 版本来源
+	SourceURL       string        `json:"source_url,omitempty"`       // 下载地址（下载安装）或原始路径（链接版本）
+	ReleaseSource   string        `json:"release_source,omitempty"`   // 提供安装包的发布源（下载安装）
+	SHA256          string        `json:"sha256,omitempty"`           // 安装包的 SHA256
+	Size            int64         `json:"size,omitempty"`             // 安装后占用的磁盘空间（字节）
+	InstallDuration time.Duration `json:"install_duration,omitempty"` // 安装耗时
+	LastUsed        time.Time     `json:"last_used,omitempty"`        // 最近使用时间
+}
+
+// VersionOrigin 表示版本是如何进入 gx 的
+type VersionOrigin string
+
+const (
+	// OriginDownloaded 从官方发布包下载安装
+	OriginDownloaded VersionOrigin = "downloaded"
+
+	// OriginAdopted 通过 gx adopt 链接的外部安装
+	OriginAdopted VersionOrigin = "adopted"
+
+	// OriginArchive 通过 gx install --archive 从本地安装包离线安装
+	OriginArchive VersionOrigin = "archive"
+
+	// OriginSource 通过 gx install --from-source 从源码构建
+	OriginSource VersionOrigin = "source"
+)
+
+// VersionSource 表示解析出的版本来自哪里
+type VersionSource string
+
+const (
+	// VersionSourceEnv 来自 GX_VERSION 环境变量
+	VersionSourceEnv VersionSource = "env"
+
+	// VersionSourceFile 来自 .go-version 文件
+	VersionSourceFile VersionSource = "file"
+
+	// VersionSourceGlobal 来自全局默认版本
+	VersionSourceGlobal VersionSource = "global"
+
+	// VersionSourceSystem 来自系统 PATH 中的 Go
+	VersionSourceSystem VersionSource = "system"
+)
+
+// ResolvedVersion 表示解析链的结果
+type ResolvedVersion struct {
+	GoVersion
+	Source     VersionSource `json:"source"`      // 版本来源
+	SourcePath string        `json:"source_path"` // 来源文件路径（仅 .go-version 有效）
+}
+
+// PrunePolicy 版本清理的保留策略
+// 每条策略都用于保护版本，只有不受任何策略保护的版本才会被删除；当前激活版本始终保留
+type PrunePolicy struct {
+	KeepPatches    int           // 每个次版本保留最新的 N 个补丁版本，0 表示不按此策略保留
+	KeepPinnedDirs []string      // 保留这些目录（递归）中 .go-version 文件固定的版本
+	UnusedFor      time.Duration // 保留在该时长内使用过的版本，0 表示不按此策略保留
+}
+
+// PruneItem 清理结果中的单个版本
+type PruneItem struct {
+	Version  string    `json:"version"`   // 版本号
+	Path     string    `json:"path"`      // 安装路径
+	Size     int64     `json:"size"`      // 占用的磁盘空间（字节）
+	LastUsed time.Time `json:"last_used"` // 最近使用时间
+	Reason   string    `json:"reason"`    // 保留或删除的原因
+}
+
+// PruneResult 清理结果
+type PruneResult struct {
+	Removed    []PruneItem `json:"removed"`     // 已删除（或 dry-run 下将删除）的版本
+	Kept       []PruneItem `json:"kept"`        // 保留的版本
+	Failed     []PruneItem `json:"failed"`      // 删除失败的版本
+	FreedBytes int64       `json:"freed_bytes"` // 释放的磁盘空间（字节）
+}
+
+// UpgradePlan 单个次版本线的升级计划
+type UpgradePlan struct {
+	Line      string   `json:"line"`      // 版本线，例如 "go1.21"
+	Installed []string `json:"installed"` // 该版本线中 gx 管理的已安装版本（从旧到新），不含链接版本
+	From      string   `json:"from"`      // 已安装的最新版本
+	To        string   `json:"to"`        // 远程最新的补丁版本，与 From 相同表示已是最新
+}
+
+// UpToDate 是否已是最新版本
+func (p UpgradePlan) UpToDate() bool {
+	return p.From == p.To
+}
+
+// RepointResult 记录 Repoint 修改了哪些引用
+type RepointResult struct {
+	Active  bool     `json:"active"`  // 是否切换了激活版本
+	Aliases []string `json:"aliases"` // 更新的别名
+	Pins    []string `json:"pins"`    // 更新的 .go-version 文件
+}
+
+// ArchiveOptions 离线安装的选项
+type ArchiveOptions struct {
+	Version string // 安装包中的版本，为空时从文件名或安装包中的 VERSION 文件推断
+	SHA256  string // 期望的 SHA256，为空时使用安装包旁的 .sha256 文件
+	Source  string // 记录在元数据中的来源，为空时使用安装包路径
+}
+
+// SourceOptions 从源码构建的选项
+type SourceOptions struct {
+	Name      string           // 注册的版本名，为空时自动生成（发布版本使用版本号，tip 使用 "tip"）
+	Bootstrap string           // 引导工具链的版本说明符，为空时从已安装版本中自动选择
+	Progress  ProgressCallback // 源码包下载进度
+	Output    io.Writer        // 构建脚本的输出，为 nil 时丢弃
 }
 
 // ProgressCallback 下载进度回调函数